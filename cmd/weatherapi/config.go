@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/byatesrae/weather/internal/jsonprovider"
 	"github.com/byatesrae/weather/internal/platform/startupconfig"
+	"github.com/byatesrae/weather/internal/providerquery"
 )
 
 // appConfig is all of the application configuration.
@@ -19,8 +23,76 @@ type appConfig struct {
 	WeatherstackEndpointURL string        // Endpoint for the Weatherstack provider API endpoint.
 	WeatherstackAccessKey   string        // Access key for the Weatherstack provider. See https://weatherstack.com/documentation.
 	ResultTimeout           time.Duration // Timeout for getting a response from providers.
+	ProviderTimeout         time.Duration // Timeout for getting a response from an individual provider.
+	Cache                   string        // Where results are cached, one of "memory", "redis" or "peer".
+	RedisAddress            string        // The address of the Redis server results are cached in, e.g "localhost:6379".
+	RedisPassword           string        // The password for the Redis server, if required.
+	PeerSelf                string        // The URL peers reach this replica at, e.g "http://10.0.0.1:8080".
+	Peers                   string        // The URLs of all peers, e.g "http://10.0.0.1:8080,http://10.0.0.2:8080".
+	PeerSRV                 string        // The DNS SRV record peers are discovered from, instead of Peers.
+	PeerSRVInterval         time.Duration // How often peers are discovered from PeerSRV.
+	PeerSecret              string        // The secret shared by all peers, that requests between them are signed with.
 	ResultCacheTTL          time.Duration // The amount of time a weather result is cached for.
+	ForecastCacheTTL        time.Duration // The amount of time a forecast result is cached for.
+	MaxStaleness            time.Duration // How long an expired result may be served for when providers fail. 0 is until removed from the cache.
+	RefreshAheadWindow      time.Duration // How long before expiry a result is refreshed in the background. 0 disables.
+	HotCities               string        // Cities whose weather results are kept warm, e.g "Sydney,Melbourne".
+	HotCityRefreshInterval  time.Duration // How often the results of hot cities are checked & refreshed if expiring.
+	CoordinatePrecision     int           // The number of decimal places coordinates are rounded to when caching results.
+	ProviderOrdering        string        // The strategy used to order providers, one of "static", "sticky" or "score".
+	ProviderErrorPenalty    time.Duration // The cost of a provider error when ordering providers with the "score" strategy.
+	ProviderQueryMode       string        // How providers are queried, one of "sequential", "hedged" or "race".
+	ProviderHedgeDelay      time.Duration // How long to wait for a provider before also querying the next, in "hedged" mode.
+	AggregationMethod       string        // How weather is combined across providers, one of "none", "median" or "weighted_mean".
+	ProviderWeights         string        // The weight of each provider for "weighted_mean" aggregation, e.g "Openweather=2,Weatherstack=1".
+	BreakerFailureThreshold int           // The number of consecutive failures after which a provider is skipped. <= 0 disables.
+	BreakerCoolDown         time.Duration // The amount of time a provider is skipped for before being probed again.
+	BreakerHalfOpenProbes   int           // The maximum number of concurrent probes of a provider being skipped.
 	ColourizedOutput        bool          // If true, log messages are colourized.
+
+	// How far a provider's values may differ from the rest before it is rejected when aggregating.
+	OutlierTolerances providerquery.OutlierTolerances
+
+	// The bounds of the in-memory cache (used as is, or as the local part of a shared cache).
+	MemoryCacheMaxEntries      int
+	MemoryCacheExpiryGrace     time.Duration
+	MemoryCacheJanitorInterval time.Duration
+
+	// The file the in-memory cache is snapshot to, restoring it on startup. Empty disables.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+
+	// Where traces are exported, and the OTLP/HTTP collector they're sent to.
+	TraceExporter string
+	OTLPEndpoint  string
+
+	// When the application is ready to serve traffic, and how long it reports not being ready
+	// for on shutdown (such that load balancers drain traffic) before the server shuts down.
+	ReadyzMinAvailableProviders int
+	ReadyzMaxResultAge          time.Duration
+	ReadyzCacheRequired         bool
+	ShutdownDrainDelay          time.Duration
+
+	// Endpoints for the Open-Meteo provider (which needs no API key), queried after the other
+	// providers as a last resort. An empty OpenMeteoEndpointURL disables the provider.
+	OpenMeteoEndpointURL          string
+	OpenMeteoGeocodingEndpointURL string
+
+	// The NWS provider (covering only the US), enabled by setting the User-Agent it requires
+	// (& NWSEndpointURL). City names are resolved with the Open-Meteo geocoding API, without
+	// which the provider only answers queries by coordinates.
+	NWSEndpointURL    string
+	NWSUserAgent      string
+	NWSPointsCacheTTL time.Duration
+
+	// The Met Norway provider, enabled by setting the User-Agent it requires (&
+	// MetNoEndpointURL). Like the NWS, city names are resolved with the Open-Meteo geocoding API.
+	MetNoEndpointURL string
+	MetNoUserAgent   string
+
+	// The file defining providers by configuration (see the jsonprovider package), queried
+	// before Open-Meteo. Empty defines none.
+	JSONProvidersPath string
 }
 
 func (c *appConfig) masked() *appConfig {
@@ -34,6 +106,14 @@ func (c *appConfig) masked() *appConfig {
 		masked.WeatherstackAccessKey = "*****"
 	}
 
+	if masked.RedisPassword != "" {
+		masked.RedisPassword = "*****"
+	}
+
+	if masked.PeerSecret != "" {
+		masked.PeerSecret = "*****"
+	}
+
 	return &masked
 }
 
@@ -51,9 +131,56 @@ func loadConfig() (*appConfig, error) {
 	fs.StringVar(&c.OpenweatherAPIKey, "openweather-api-key", "", "Required. API key for the Openweather provider. See https://openweathermap.org/current.")
 	fs.StringVar(&c.WeatherstackEndpointURL, "weatherstack-endpoint-url", "http://api.weatherstack.com", "Endpoint for the Weatherstack provider API endpoint.")
 	fs.StringVar(&c.WeatherstackAccessKey, "weatherstack-access-key", "", "Required. Access key for the Weatherstack provider. See https://weatherstack.com/documentation.")
+	fs.StringVar(&c.OpenMeteoEndpointURL, "openmeteo-endpoint-url", "https://api.open-meteo.com/v1", "Endpoint for the Open-Meteo provider API, queried after the other providers as a last resort (needing no API key). Empty disables the provider.")
+	fs.StringVar(&c.OpenMeteoGeocodingEndpointURL, "openmeteo-geocoding-endpoint-url", "https://geocoding-api.open-meteo.com/v1", "Endpoint for the Open-Meteo geocoding API, used to find the coordinates of cities (for the Open-Meteo & NWS providers).")
+	fs.StringVar(&c.NWSEndpointURL, "nws-endpoint-url", "https://api.weather.gov", "Endpoint for the NWS (US National Weather Service) provider API, covering only the US. Empty disables the provider.")
+	fs.StringVar(&c.NWSUserAgent, "nws-user-agent", "", "The User-Agent identifying this application to the NWS, which asks that it includes a contact (e.g \"(myweatherapp.com, contact@myweatherapp.com)\"). Empty disables the provider.")
+	fs.DurationVar(&c.NWSPointsCacheTTL, "nws-points-cache-ttl", time.Hour*24, "How long the NWS grid & observation stations of a location are cached for. A value <= 0 disables the cache.")
+	fs.StringVar(&c.MetNoEndpointURL, "metno-endpoint-url", "https://api.met.no/weatherapi/locationforecast/2.0", "Endpoint for the Met Norway Locationforecast provider API. Empty disables the provider.")
+	fs.StringVar(&c.MetNoUserAgent, "metno-user-agent", "", "The User-Agent identifying this application to Met Norway, which asks that it includes a contact (e.g \"myweatherapp.com contact@myweatherapp.com\"). Empty disables the provider.")
+	fs.StringVar(&c.JSONProvidersPath, "json-providers", "", "The JSON file defining providers by configuration: a URL template, where the API key is sent, where each weather field is found in a response (& its unit), and how errors are detected. Empty defines none.")
 	fs.DurationVar(&c.ResultTimeout, "result-timeout", time.Second*10, "Timeout for getting a response from providers.")
+	fs.DurationVar(&c.ProviderTimeout, "provider-timeout", time.Second*3, "Timeout for getting a response from an individual provider, after which the next provider is queried.")
+	fs.StringVar(&c.Cache, "cache", "memory", "Where results are cached. One of \"memory\" (per process), \"redis\" (shared by all replicas, falling back to memory while Redis is unreachable) or \"peer\" (shared by replicas, each owning a portion of results).")
+	fs.IntVar(&c.MemoryCacheMaxEntries, "memory-cache-max-entries", 10000, "The maximum number of results cached in memory, past which the least recently used are evicted. A value <= 0 is unbounded.")
+	fs.DurationVar(&c.MemoryCacheExpiryGrace, "memory-cache-expiry-grace", time.Hour, "How long results are kept in memory past their expiry (to be served stale), before being removed. Raised to max-staleness if shorter.")
+	fs.DurationVar(&c.MemoryCacheJanitorInterval, "memory-cache-janitor-interval", time.Minute, "How often results past memory-cache-expiry-grace are removed from memory.")
+	fs.StringVar(&c.SnapshotPath, "snapshot-path", "", "The file the in-memory cache is snapshot to periodically & on shutdown, restoring it on startup (such that stale results survive restarts). Empty disables snapshots.")
+	fs.DurationVar(&c.SnapshotInterval, "snapshot-interval", time.Minute, "How often the in-memory cache is snapshot to snapshot-path.")
+	fs.StringVar(&c.RedisAddress, "redis-address", "localhost:6379", "The address of the Redis server results are cached in, when cache is \"redis\".")
+	fs.StringVar(&c.RedisPassword, "redis-password", "", "The password for the Redis server, if required.")
+	fs.StringVar(&c.PeerSelf, "peer-self", "", "The URL other replicas reach this replica at, when cache is \"peer\", e.g \"http://10.0.0.1:8080\".")
+	fs.StringVar(&c.Peers, "peers", "", "A comma separated list of the URLs of all replicas, when cache is \"peer\", e.g \"http://10.0.0.1:8080,http://10.0.0.2:8080\".")
+	fs.StringVar(&c.PeerSRV, "peer-srv", "", "The DNS SRV record replicas are discovered from (instead of peers), when cache is \"peer\", e.g \"_http._tcp.weatherapi.default.svc.cluster.local\".")
+	fs.DurationVar(&c.PeerSRVInterval, "peer-srv-interval", time.Second*30, "How often replicas are discovered from peer-srv.")
+	fs.StringVar(&c.PeerSecret, "peer-secret", "", "The secret shared by all replicas, when cache is \"peer\", that requests between them are signed with. Requests from replicas are rejected without it.")
 	fs.DurationVar(&c.ResultCacheTTL, "result-cache-ttl", time.Second*3, "The amount of time a weather result is cached for.")
+	fs.DurationVar(&c.ForecastCacheTTL, "forecast-cache-ttl", time.Minute*10, "The amount of time a forecast result is cached for.")
+	fs.DurationVar(&c.MaxStaleness, "max-staleness", time.Minute*15, "How long an expired result may still be served for when all providers fail, after which requests fail with 503 Service Unavailable. 0 serves expired results until they're removed from the cache, memory-cache-expiry-grace past their expiry.")
+	fs.DurationVar(&c.RefreshAheadWindow, "refresh-ahead-window", time.Second, "How long before expiry a cached result is refreshed in the background, while still being served. 0 disables refreshing ahead, such that the first request after expiry waits on providers.")
+	fs.StringVar(&c.HotCities, "hot-cities", "", "A comma separated list of cities whose weather results are kept warm in the background, e.g \"Sydney,Melbourne\".")
+	fs.DurationVar(&c.HotCityRefreshInterval, "hot-city-refresh-interval", time.Second, "How often the cached results of hot cities are checked, being refreshed if they would expire before the next check.")
+	fs.IntVar(&c.CoordinatePrecision, "coordinate-precision", 2, "The number of decimal places (0-6) coordinates are rounded to when caching results. Nearby coordinates that round to the same value share a result.")
+	fs.StringVar(&c.ProviderOrdering, "provider-ordering", "static", "The strategy used to decide the order providers are queried in. One of \"static\" (the order configured), \"sticky\" (the last successful provider first) or \"score\" (lowest latency + error rate * provider-error-penalty first).")
+	fs.DurationVar(&c.ProviderErrorPenalty, "provider-error-penalty", time.Second*3, "The cost of a provider error when ordering providers with the \"score\" strategy.")
+	fs.StringVar(&c.ProviderQueryMode, "provider-query-mode", string(providerquery.QueryModeSequential), "How providers are queried. One of \"sequential\" (the next provider is queried once the previous fails), \"hedged\" (the next provider is also queried if the previous hasn't answered within provider-hedge-delay) or \"race\" (all providers are queried in parallel).")
+	fs.DurationVar(&c.ProviderHedgeDelay, "provider-hedge-delay", time.Millisecond*500, "How long to wait for a provider to answer before also querying the next provider, in \"hedged\" query mode.")
+	fs.StringVar(&c.AggregationMethod, "aggregation-method", string(providerquery.AggregationMethodNone), "How weather is combined across providers. One of \"none\" (the first successful provider is used), \"median\" or \"weighted_mean\" (all providers are queried & their values combined).")
+	fs.StringVar(&c.ProviderWeights, "provider-weights", "", "The weight of each provider when using the \"weighted_mean\" aggregation method, e.g \"Openweather=2,Weatherstack=1\". Providers without a weight have a weight of 1.")
+	fs.Float64Var(&c.OutlierTolerances.Temperature, "outlier-tolerance-temperature", 5, "How far (in degrees celsius) a provider's temperature may differ from the median before it is rejected when aggregating. A value <= 0 disables the check.")
+	fs.Float64Var(&c.OutlierTolerances.WindSpeed, "outlier-tolerance-wind-speed", 15, "How far (in km/h) a provider's wind speed may differ from the median before it is rejected when aggregating. A value <= 0 disables the check.")
+	fs.Float64Var(&c.OutlierTolerances.Humidity, "outlier-tolerance-humidity", 20, "How far (in percent) a provider's humidity may differ from the median before it is rejected when aggregating. A value <= 0 disables the check.")
+	fs.Float64Var(&c.OutlierTolerances.Pressure, "outlier-tolerance-pressure", 10, "How far (in hPa) a provider's pressure may differ from the median before it is rejected when aggregating. A value <= 0 disables the check.")
+	fs.IntVar(&c.BreakerFailureThreshold, "breaker-failure-threshold", 5, "The number of consecutive failures after which a provider's circuit breaker opens (skipping that provider). A value <= 0 disables circuit breaking.")
+	fs.DurationVar(&c.BreakerCoolDown, "breaker-cool-down", time.Second*30, "The amount of time a provider's circuit breaker stays open before letting probe requests through.")
+	fs.IntVar(&c.BreakerHalfOpenProbes, "breaker-half-open-probes", 1, "The maximum number of concurrent probe requests let through a half-open provider circuit breaker.")
+	fs.IntVar(&c.ReadyzMinAvailableProviders, "readyz-min-available-providers", 1, "The minimum number of available providers (those whose circuit breaker isn't open) for /v1/readyz to report ready.")
+	fs.DurationVar(&c.ReadyzMaxResultAge, "readyz-max-result-age", 0, "How old the freshest result read may be for /v1/readyz to report ready. 0 disables the check.")
+	fs.BoolVar(&c.ReadyzCacheRequired, "readyz-cache-required", false, "If true, /v1/readyz reports not ready while the cache is unreachable. Otherwise an unreachable cache is only reported, results still being served (e.g. from memory while Redis is unreachable).")
+	fs.DurationVar(&c.ShutdownDrainDelay, "shutdown-drain-delay", time.Second*5, "How long /v1/readyz reports not ready for on shutdown, letting load balancers stop sending traffic, before the server stops accepting requests.")
 	fs.BoolVar(&c.ColourizedOutput, "colourized-output", false, "If true, log messages are colourized.")
+	fs.StringVar(&c.TraceExporter, "trace-exporter", "none", "Where traces are exported. One of \"none\", \"stdout\" (for local use) or \"otlp\" (to an OTLP/HTTP collector, see otlp-endpoint).")
+	fs.StringVar(&c.OTLPEndpoint, "otlp-endpoint", "http://localhost:4318", "The OTLP/HTTP collector traces are exported to, when trace-exporter is \"otlp\".")
 
 	if err := p.Parse(fs, os.Args[1:]); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -71,5 +198,147 @@ func loadConfig() (*appConfig, error) {
 		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "weatherstack-access-key", fmt.Errorf("value is required")))
 	}
 
+	if c.OpenMeteoEndpointURL != "" && c.OpenMeteoGeocodingEndpointURL == "" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "openmeteo-geocoding-endpoint-url", fmt.Errorf("value is required when openmeteo-endpoint-url is set")))
+	}
+
+	if c.ResultTimeout <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "result-timeout", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.ProviderTimeout <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "provider-timeout", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.Cache != "memory" && c.Cache != "redis" && c.Cache != "peer" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "cache", fmt.Errorf("value must be one of \"memory\", \"redis\" or \"peer\"")))
+	}
+
+	if c.MemoryCacheJanitorInterval <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "memory-cache-janitor-interval", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.TraceExporter != "none" && c.TraceExporter != "stdout" && c.TraceExporter != "otlp" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "trace-exporter", fmt.Errorf("value must be one of \"none\", \"stdout\" or \"otlp\"")))
+	}
+
+	if c.TraceExporter == "otlp" && c.OTLPEndpoint == "" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "otlp-endpoint", fmt.Errorf("value is required when trace-exporter is \"otlp\"")))
+	}
+
+	if c.SnapshotPath != "" && c.SnapshotInterval <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "snapshot-interval", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.Cache == "redis" && c.RedisAddress == "" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "redis-address", fmt.Errorf("value is required when cache is \"redis\"")))
+	}
+
+	if c.Cache == "peer" && c.PeerSelf == "" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "peer-self", fmt.Errorf("value is required when cache is \"peer\"")))
+	}
+
+	if c.Cache == "peer" && c.PeerSecret == "" {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "peer-secret", fmt.Errorf("value is required when cache is \"peer\"")))
+	}
+
+	if c.Cache == "peer" && c.PeerSRV != "" && c.PeerSRVInterval <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "peer-srv-interval", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.CoordinatePrecision < 0 || c.CoordinatePrecision > 6 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "coordinate-precision", fmt.Errorf("value must be in the range [0, 6]")))
+	}
+
+	if _, err := newOrderingStrategy(c.ProviderOrdering, c.ProviderErrorPenalty); err != nil {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "provider-ordering", err))
+	}
+
+	if !providerquery.QueryMode(c.ProviderQueryMode).Valid() {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "provider-query-mode", fmt.Errorf("value must be one of \"sequential\", \"hedged\" or \"race\"")))
+	}
+
+	if !providerquery.AggregationMethod(c.AggregationMethod).Valid() {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "aggregation-method", fmt.Errorf("value must be one of \"none\", \"median\" or \"weighted_mean\"")))
+	}
+
+	if _, err := parseProviderWeights(c.ProviderWeights); err != nil {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "provider-weights", err))
+	}
+
+	if c.JSONProvidersPath != "" {
+		if _, err := jsonprovider.LoadFile(c.JSONProvidersPath); err != nil {
+			return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "json-providers", err))
+		}
+	}
+
+	if c.HotCityRefreshInterval <= 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "hot-city-refresh-interval", fmt.Errorf("value must be greater than 0")))
+	}
+
+	if c.ReadyzMinAvailableProviders < 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "readyz-min-available-providers", fmt.Errorf("value must not be negative")))
+	}
+
+	if c.ShutdownDrainDelay < 0 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "shutdown-drain-delay", fmt.Errorf("value must not be negative")))
+	}
+
+	if c.BreakerHalfOpenProbes < 1 {
+		return nil, fmt.Errorf("weatherapi: validate configuration: %w", p.FlagError(fs, "breaker-half-open-probes", fmt.Errorf("value must be at least 1")))
+	}
+
 	return &c, nil
 }
+
+// newOrderingStrategy creates the provider ordering strategy named name.
+func newOrderingStrategy(name string, errorPenalty time.Duration) (providerquery.OrderingStrategy, error) {
+	switch name {
+	case "static":
+		return providerquery.NewStaticOrdering(), nil
+	case "sticky":
+		return providerquery.NewStickyOrdering(), nil
+	case "score":
+		return providerquery.NewScoreOrdering(errorPenalty), nil
+	default:
+		return nil, fmt.Errorf("value must be one of \"static\", \"sticky\" or \"score\"")
+	}
+}
+
+// parseProviderWeights parses provider weights of the form "Openweather=2,Weatherstack=1".
+func parseProviderWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		provider, weight, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q must be of the form provider=weight", pair)
+		}
+
+		parsedWeight, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil || parsedWeight < 0 {
+			return nil, fmt.Errorf("weight %q must be a non-negative number", weight)
+		}
+
+		weights[strings.TrimSpace(provider)] = parsedWeight
+	}
+
+	return weights, nil
+}
+
+// parseList parses a comma separated list, e.g "Sydney,Melbourne".
+func parseList(s string) []string {
+	var items []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
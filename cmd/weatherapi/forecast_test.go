@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecast(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	for _, tc := range []struct {
+		name                    string
+		withOpenweatherHandler  http.HandlerFunc
+		withWeatherstackHandler http.HandlerFunc
+		give                    *http.Request
+		expectedStatusCode      int
+		expectedBody            string
+	}{
+		{
+			name: "success_openweather",
+			withOpenweatherHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"list": [
+					{"dt": 1605052800, "main": {"temp": 20}, "wind": {"speed": 1}},
+					{"dt": 1605063600, "main": {"temp": 22}, "wind": {"speed": 2}},
+					{"dt": 1605139200, "main": {"temp": 18}, "wind": {"speed": 3}}
+				],
+				"city": {
+					"name": "Sydney",
+					"timezone": 36000
+				}
+			}`)),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			give:                    forecastRequest(context.Background(), t, serverURL, "Sydney", 2),
+			expectedStatusCode:      http.StatusOK,
+			expectedBody: `{"daily":[` +
+				`{"wind_speed":7.2,"temperature_degrees":21,"start":"2020-11-11T00:00:00+10:00","end":"2020-11-12T00:00:00+10:00","temperature_min_degrees":20,"temperature_max_degrees":22},` +
+				`{"wind_speed":10.8,"temperature_degrees":18,"start":"2020-11-12T00:00:00+10:00","end":"2020-11-13T00:00:00+10:00","temperature_min_degrees":18,"temperature_max_degrees":18}` +
+				`],"hourly":[` +
+				`{"wind_speed":3.6,"temperature_degrees":20,"start":"2020-11-11T10:00:00+10:00","end":"2020-11-11T13:00:00+10:00"},` +
+				`{"wind_speed":7.2,"temperature_degrees":22,"start":"2020-11-11T13:00:00+10:00","end":"2020-11-11T16:00:00+10:00"},` +
+				`{"wind_speed":10.8,"temperature_degrees":18,"start":"2020-11-12T10:00:00+10:00","end":"2020-11-12T13:00:00+10:00"}` +
+				`]}` + "\n",
+		},
+		{
+			name:                   "success_weatherstack",
+			withOpenweatherHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"location": {
+					"name": "Sydney",
+					"utc_offset": "10.0"
+				},
+				"forecast": {
+					"2020-11-11": {
+						"date": "2020-11-11",
+						"mintemp": 15,
+						"maxtemp": 25,
+						"avgtemp": 20,
+						"hourly": [
+							{"time": "0", "temperature": 16, "wind_speed": 5},
+							{"time": "1200", "temperature": 24, "wind_speed": 9}
+						]
+					}
+				}
+			}`)),
+			give:               forecastRequest(context.Background(), t, serverURL, "Sydney", 1),
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"daily":[` +
+				`{"wind_speed":9,"temperature_degrees":20,"start":"2020-11-11T00:00:00+10:00","end":"2020-11-12T00:00:00+10:00","temperature_min_degrees":15,"temperature_max_degrees":25}` +
+				`],"hourly":[` +
+				`{"wind_speed":5,"temperature_degrees":16,"start":"2020-11-11T00:00:00+10:00","end":"2020-11-11T03:00:00+10:00"},` +
+				`{"wind_speed":9,"temperature_degrees":24,"start":"2020-11-11T12:00:00+10:00","end":"2020-11-11T15:00:00+10:00"}` +
+				`]}` + "\n",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			requestID := newRequestID(t)
+
+			registerWeatherstackStub(t, requestID, tc.withWeatherstackHandler)
+			registerOpenweatherStub(t, requestID, tc.withOpenweatherHandler)
+
+			tc.give.Header.Add("X-Correlation-Id", requestID)
+
+			// Do
+			res, err := http.DefaultClient.Do(tc.give)
+			require.NoError(t, err, "request error")
+
+			t.Cleanup(func() {
+				if err := res.Body.Close(); err != nil {
+					t.Errorf("failed to close body: %s", err)
+				}
+			})
+
+			resultExpiry, err := time.Parse(http.TimeFormat, res.Header.Get("Expires"))
+			require.NoError(t, err, "parse expiry error")
+
+			// Assert
+			actualBody, err := io.ReadAll(res.Body)
+			require.NoError(t, err, "read body error")
+
+			assert.Equal(t, tc.expectedStatusCode, res.StatusCode, "response status code")
+			assert.Equal(t, tc.expectedBody, string(actualBody), "response body")
+
+			// Wait for cache, with +1 second as cache expiry header resolution
+			// is in seconds.
+			timeUntilExpiry := time.Until(resultExpiry) + time.Second
+			<-time.After(timeUntilExpiry)
+		})
+	}
+}
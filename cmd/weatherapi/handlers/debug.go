@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// ProviderScoresService is used to query the order providers will be queried in.
+type ProviderScoresService interface {
+	ProviderScores() *providerquery.ProviderScores
+}
+
+// ProviderScoresResponse is returned from the API for a provider scores request.
+type ProviderScoresResponse struct {
+	Strategy  string                  `json:"strategy"`
+	Providers []ProviderScoreResponse `json:"providers"` // In the order they will next be queried.
+}
+
+// ProviderScoreResponse is the recent stats & score of a provider.
+type ProviderScoreResponse struct {
+	Provider    string     `json:"provider"`
+	Score       float64    `json:"score"`
+	Queries     int        `json:"queries"`
+	ErrorRate   float64    `json:"error_rate"`
+	LatencyMS   float64    `json:"latency_ms"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// NewProviderScoresHandler creates a new handler that can be used to debug the order providers are
+// queried in, returning the ordering strategy along with the stats & score of each provider.
+func NewProviderScoresHandler(
+	providerScoresService ProviderScoresService,
+	getLoggerFromContext func(context.Context) logr.Logger,
+) http.HandlerFunc {
+	noopLogger := nooplogr.New()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		logger := noopLogger
+		if getLoggerFromContext != nil {
+			logger = getLoggerFromContext(req.Context())
+		}
+
+		scores := providerScoresService.ProviderScores()
+
+		response := ProviderScoresResponse{
+			Strategy:  scores.Strategy,
+			Providers: make([]ProviderScoreResponse, 0, len(scores.Providers)),
+		}
+
+		for _, score := range scores.Providers {
+			providerResponse := ProviderScoreResponse{
+				Provider:  score.Provider,
+				Score:     score.Score,
+				Queries:   score.Queries,
+				ErrorRate: score.ErrorRate,
+				LatencyMS: float64(score.Latency) / float64(time.Millisecond),
+			}
+
+			if !score.LastSuccess.IsZero() {
+				lastSuccess := score.LastSuccess.UTC()
+				providerResponse.LastSuccess = &lastSuccess
+			}
+
+			response.Providers = append(response.Providers, providerResponse)
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Cache-Control", "no-store")
+
+		if err := json.NewEncoder(rw).Encode(&response); err != nil {
+			logger.Error(err, "Failed to encode response body.")
+
+			http.Error(rw, "", http.StatusInternalServerError)
+		}
+	}
+}
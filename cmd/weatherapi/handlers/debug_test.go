@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestProviderScoresHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	service := &ProviderScoresServiceMock{
+		ProviderScoresFunc: func() *providerquery.ProviderScores {
+			return &providerquery.ProviderScores{
+				Strategy: "score",
+				Providers: []providerquery.ProviderScore{
+					{
+						ProviderStats: providerquery.ProviderStats{
+							Provider:    "goodProvider",
+							Queries:     10,
+							ErrorRate:   0.1,
+							Latency:     time.Millisecond * 250,
+							LastSuccess: now,
+						},
+						Score: 0.55,
+					},
+					{
+						ProviderStats: providerquery.ProviderStats{Provider: "newProvider"},
+					},
+				},
+			}
+		},
+	}
+
+	rw := httptest.NewRecorder()
+
+	NewProviderScoresHandler(service, nil)(rw, httptest.NewRequest("GET", "/debug/providers", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code, "response status code")
+	assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "response cache control")
+	assert.Equal(
+		t,
+		`{"strategy":"score","providers":[`+
+			`{"provider":"goodProvider","score":0.55,"queries":10,"error_rate":0.1,"latency_ms":250,"last_success":"2020-11-11T10:10:10Z"},`+
+			`{"provider":"newProvider","score":0,"queries":0,"error_rate":0,"latency_ms":0}]}`+"\n",
+		rw.Body.String(),
+		"response body",
+	)
+}
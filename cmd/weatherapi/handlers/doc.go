@@ -1,4 +1,4 @@
 // Package handlers contains various handlers for the weather-api application.
 package handlers
 
-//go:generate moq -out moq_test.go . WeatherService
+//go:generate moq -out moq_test.go . WeatherService ForecastService ProviderScoresService
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+const (
+	// defaultForecastDays is the number of days forecast when parameter "days" is not specified.
+	defaultForecastDays = 3
+
+	// maxForecastDays is the maximum number of days that can be forecast (limited by Openweather's
+	// 5 day forecast).
+	maxForecastDays = 5
+)
+
+// ForecastService is used to query a weather forecast for a city.
+type ForecastService interface {
+	ReadForecastResult(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error)
+}
+
+// NewForecastHandler creates a new handler that can be used to query a weather forecast for a city location
+// (parameter "city") covering a number of days (parameter "days").
+func NewForecastHandler(
+	forecastService ForecastService,
+	loadResultTimeout time.Duration,
+	getLoggerFromContext func(context.Context) logr.Logger,
+) http.HandlerFunc {
+	noopLogger := nooplogr.New()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		logger := noopLogger
+		if getLoggerFromContext != nil {
+			logger = getLoggerFromContext(req.Context())
+		}
+
+		query := req.URL.Query()
+
+		city := strings.TrimSpace(query.Get("city"))
+		if city == "" {
+			problemResponse(logger, rw, http.StatusBadRequest, ProblemCodeInvalidInput, "Missing parameter \"city\".")
+
+			return
+		}
+
+		if message := validateCity(city); message != "" {
+			problemResponse(logger, rw, http.StatusBadRequest, ProblemCodeInvalidInput, message)
+
+			return
+		}
+
+		days := defaultForecastDays
+		if daysParam := query.Get("days"); daysParam != "" {
+			var err error
+			if days, err = strconv.Atoi(daysParam); err != nil || days < 1 || days > maxForecastDays {
+				problemResponse(
+					logger,
+					rw,
+					http.StatusBadRequest,
+					ProblemCodeInvalidInput,
+					fmt.Sprintf("Parameter \"days\" must be a whole number in the range [1, %v].", maxForecastDays),
+				)
+
+				return
+			}
+		}
+
+		readForecastCtx, readForecastCancel := context.WithTimeout(req.Context(), loadResultTimeout)
+		defer readForecastCancel()
+
+		result, err := forecastService.ReadForecastResult(readForecastCtx, city, days)
+		if err != nil {
+			readErrorResponse(logger, rw, err)
+
+			return
+		}
+
+		setStaleHeaders(rw, result.Stale, result.Age)
+		resultResponse(logger, rw, result.CreatedAt, result.Expiry, result.Forecast)
+	}
+}
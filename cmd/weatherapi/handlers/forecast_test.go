@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestForecastHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	goodServiceResult := &providerquery.ForecastResult{
+		Forecast: &weather.Forecast{
+			Daily: []weather.ForecastEntry{
+				{
+					Summary: weather.Summary{Temperature: 123.456, WindSpeed: 7},
+					Start:   now,
+					End:     now.AddDate(0, 0, 1),
+				},
+			},
+			Hourly: []weather.ForecastEntry{},
+		},
+		CreatedAt: now,
+		Expiry:    now.Add(time.Minute),
+	}
+	goodService := &ForecastServiceMock{
+		ReadForecastResultFunc: func(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error) {
+			return goodServiceResult, nil
+		},
+	}
+	errService := &ForecastServiceMock{
+		ReadForecastResultFunc: func(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error) {
+			return nil, errors.New("intentional test error")
+		},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		withHandler  http.HandlerFunc
+		giveRequest  *http.Request
+		expectedCode int
+		expectedBody []byte
+	}{
+		{
+			name:         "success",
+			withHandler:  NewForecastHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast?city=Sydney&days=2", nil),
+			expectedCode: http.StatusOK,
+			expectedBody: []byte("{\"daily\":[{\"wind_speed\":7,\"temperature_degrees\":123.456,\"start\":\"2020-11-11T10:10:10Z\",\"end\":\"2020-11-12T10:10:10Z\"}],\"hourly\":[]}\n"),
+		},
+		{
+			name:         "success_default_days",
+			withHandler:  NewForecastHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast?city=Sydney", nil),
+			expectedCode: http.StatusOK,
+			expectedBody: []byte("{\"daily\":[{\"wind_speed\":7,\"temperature_degrees\":123.456,\"start\":\"2020-11-11T10:10:10Z\",\"end\":\"2020-11-12T10:10:10Z\"}],\"hourly\":[]}\n"),
+		},
+		{
+			name:         "city_empty",
+			withHandler:  NewForecastHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Missing parameter \\\"city\\\".\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "days_out_of_range",
+			withHandler:  NewForecastHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast?city=Sydney&days=6", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Parameter \\\"days\\\" must be a whole number in the range [1, 5].\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "days_not_a_number",
+			withHandler:  NewForecastHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast?city=Sydney&days=abc", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Parameter \\\"days\\\" must be a whole number in the range [1, 5].\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "forecast_service_err",
+			withHandler:  NewForecastHandler(errService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/forecast?city=Sydney", nil),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"detail\":\"Woops, something went wrong.\",\"code\":\"internal_error\"}\n"),
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			tc.withHandler.ServeHTTP(rr, tc.giveRequest)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			assert.Equal(t, string(tc.expectedBody), rr.Body.String())
+		})
+	}
+
+	t.Run("days_passed_to_service", func(t *testing.T) {
+		t.Parallel()
+
+		service := &ForecastServiceMock{
+			ReadForecastResultFunc: func(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error) {
+				return goodServiceResult, nil
+			},
+		}
+
+		NewForecastHandler(service, time.Millisecond*100, nil).ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest("GET", "/forecast?city=Sydney&days=5", nil),
+		)
+
+		if assert.Len(t, service.ReadForecastResultCalls(), 1) {
+			assert.Equal(t, "Sydney", service.ReadForecastResultCalls()[0].City)
+			assert.Equal(t, 5, service.ReadForecastResultCalls()[0].Days)
+		}
+	})
+}
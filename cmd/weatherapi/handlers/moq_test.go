@@ -22,6 +22,9 @@ var _ WeatherService = &WeatherServiceMock{}
 //			ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
 //				panic("mock out the ReadWeatherResult method")
 //			},
+//			ReadWeatherResultByCoordinatesFunc: func(ctx context.Context, coordinates providerquery.Coordinates) (*providerquery.WeatherResult, error) {
+//				panic("mock out the ReadWeatherResultByCoordinates method")
+//			},
 //		}
 //
 //		// use mockedWeatherService in code that requires WeatherService
@@ -32,6 +35,9 @@ type WeatherServiceMock struct {
 	// ReadWeatherResultFunc mocks the ReadWeatherResult method.
 	ReadWeatherResultFunc func(ctx context.Context, city string) (*providerquery.WeatherResult, error)
 
+	// ReadWeatherResultByCoordinatesFunc mocks the ReadWeatherResultByCoordinates method.
+	ReadWeatherResultByCoordinatesFunc func(ctx context.Context, coordinates providerquery.Coordinates) (*providerquery.WeatherResult, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// ReadWeatherResult holds details about calls to the ReadWeatherResult method.
@@ -41,8 +47,16 @@ type WeatherServiceMock struct {
 			// City is the city argument value.
 			City string
 		}
+		// ReadWeatherResultByCoordinates holds details about calls to the ReadWeatherResultByCoordinates method.
+		ReadWeatherResultByCoordinates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Coordinates is the coordinates argument value.
+			Coordinates providerquery.Coordinates
+		}
 	}
-	lockReadWeatherResult sync.RWMutex
+	lockReadWeatherResult              sync.RWMutex
+	lockReadWeatherResultByCoordinates sync.RWMutex
 }
 
 // ReadWeatherResult calls ReadWeatherResultFunc.
@@ -80,3 +94,242 @@ func (mock *WeatherServiceMock) ReadWeatherResultCalls() []struct {
 	mock.lockReadWeatherResult.RUnlock()
 	return calls
 }
+
+// ReadWeatherResultByCoordinates calls ReadWeatherResultByCoordinatesFunc.
+func (mock *WeatherServiceMock) ReadWeatherResultByCoordinates(ctx context.Context, coordinates providerquery.Coordinates) (*providerquery.WeatherResult, error) {
+	if mock.ReadWeatherResultByCoordinatesFunc == nil {
+		panic("WeatherServiceMock.ReadWeatherResultByCoordinatesFunc: method is nil but WeatherService.ReadWeatherResultByCoordinates was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Coordinates providerquery.Coordinates
+	}{
+		Ctx:         ctx,
+		Coordinates: coordinates,
+	}
+	mock.lockReadWeatherResultByCoordinates.Lock()
+	mock.calls.ReadWeatherResultByCoordinates = append(mock.calls.ReadWeatherResultByCoordinates, callInfo)
+	mock.lockReadWeatherResultByCoordinates.Unlock()
+	return mock.ReadWeatherResultByCoordinatesFunc(ctx, coordinates)
+}
+
+// ReadWeatherResultByCoordinatesCalls gets all the calls that were made to ReadWeatherResultByCoordinates.
+// Check the length with:
+//
+//	len(mockedWeatherService.ReadWeatherResultByCoordinatesCalls())
+func (mock *WeatherServiceMock) ReadWeatherResultByCoordinatesCalls() []struct {
+	Ctx         context.Context
+	Coordinates providerquery.Coordinates
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Coordinates providerquery.Coordinates
+	}
+	mock.lockReadWeatherResultByCoordinates.RLock()
+	calls = mock.calls.ReadWeatherResultByCoordinates
+	mock.lockReadWeatherResultByCoordinates.RUnlock()
+	return calls
+}
+
+// Ensure, that ForecastServiceMock does implement ForecastService.
+// If this is not the case, regenerate this file with moq.
+var _ ForecastService = &ForecastServiceMock{}
+
+// ForecastServiceMock is a mock implementation of ForecastService.
+//
+//	func TestSomethingThatUsesForecastService(t *testing.T) {
+//
+//		// make and configure a mocked ForecastService
+//		mockedForecastService := &ForecastServiceMock{
+//			ReadForecastResultFunc: func(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error) {
+//				panic("mock out the ReadForecastResult method")
+//			},
+//		}
+//
+//		// use mockedForecastService in code that requires ForecastService
+//		// and then make assertions.
+//
+//	}
+type ForecastServiceMock struct {
+	// ReadForecastResultFunc mocks the ReadForecastResult method.
+	ReadForecastResultFunc func(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ReadForecastResult holds details about calls to the ReadForecastResult method.
+		ReadForecastResult []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// City is the city argument value.
+			City string
+			// Days is the days argument value.
+			Days int
+		}
+	}
+	lockReadForecastResult sync.RWMutex
+}
+
+// ReadForecastResult calls ReadForecastResultFunc.
+func (mock *ForecastServiceMock) ReadForecastResult(ctx context.Context, city string, days int) (*providerquery.ForecastResult, error) {
+	if mock.ReadForecastResultFunc == nil {
+		panic("ForecastServiceMock.ReadForecastResultFunc: method is nil but ForecastService.ReadForecastResult was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		City string
+		Days int
+	}{
+		Ctx:  ctx,
+		City: city,
+		Days: days,
+	}
+	mock.lockReadForecastResult.Lock()
+	mock.calls.ReadForecastResult = append(mock.calls.ReadForecastResult, callInfo)
+	mock.lockReadForecastResult.Unlock()
+	return mock.ReadForecastResultFunc(ctx, city, days)
+}
+
+// ReadForecastResultCalls gets all the calls that were made to ReadForecastResult.
+// Check the length with:
+//
+//	len(mockedForecastService.ReadForecastResultCalls())
+func (mock *ForecastServiceMock) ReadForecastResultCalls() []struct {
+	Ctx  context.Context
+	City string
+	Days int
+} {
+	var calls []struct {
+		Ctx  context.Context
+		City string
+		Days int
+	}
+	mock.lockReadForecastResult.RLock()
+	calls = mock.calls.ReadForecastResult
+	mock.lockReadForecastResult.RUnlock()
+	return calls
+}
+
+// Ensure, that ProviderScoresServiceMock does implement ProviderScoresService.
+// If this is not the case, regenerate this file with moq.
+var _ ProviderScoresService = &ProviderScoresServiceMock{}
+
+// ProviderScoresServiceMock is a mock implementation of ProviderScoresService.
+//
+//	func TestSomethingThatUsesProviderScoresService(t *testing.T) {
+//
+//		// make and configure a mocked ProviderScoresService
+//		mockedProviderScoresService := &ProviderScoresServiceMock{
+//			ProviderScoresFunc: func() *providerquery.ProviderScores {
+//				panic("mock out the ProviderScores method")
+//			},
+//		}
+//
+//		// use mockedProviderScoresService in code that requires ProviderScoresService
+//		// and then make assertions.
+//
+//	}
+type ProviderScoresServiceMock struct {
+	// ProviderScoresFunc mocks the ProviderScores method.
+	ProviderScoresFunc func() *providerquery.ProviderScores
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ProviderScores holds details about calls to the ProviderScores method.
+		ProviderScores []struct {
+		}
+	}
+	lockProviderScores sync.RWMutex
+}
+
+// ProviderScores calls ProviderScoresFunc.
+func (mock *ProviderScoresServiceMock) ProviderScores() *providerquery.ProviderScores {
+	if mock.ProviderScoresFunc == nil {
+		panic("ProviderScoresServiceMock.ProviderScoresFunc: method is nil but ProviderScoresService.ProviderScores was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockProviderScores.Lock()
+	mock.calls.ProviderScores = append(mock.calls.ProviderScores, callInfo)
+	mock.lockProviderScores.Unlock()
+	return mock.ProviderScoresFunc()
+}
+
+// ProviderScoresCalls gets all the calls that were made to ProviderScores.
+// Check the length with:
+//
+//	len(mockedProviderScoresService.ProviderScoresCalls())
+func (mock *ProviderScoresServiceMock) ProviderScoresCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockProviderScores.RLock()
+	calls = mock.calls.ProviderScores
+	mock.lockProviderScores.RUnlock()
+	return calls
+}
+
+// Ensure, that ReadinessServiceMock does implement ReadinessService.
+// If this is not the case, regenerate this file with moq.
+var _ ReadinessService = &ReadinessServiceMock{}
+
+// ReadinessServiceMock is a mock implementation of ReadinessService.
+//
+//	func TestSomethingThatUsesReadinessService(t *testing.T) {
+//
+//		// make and configure a mocked ReadinessService
+//		mockedReadinessService := &ReadinessServiceMock{
+//			ReadinessFunc: func(ctx context.Context) *providerquery.Readiness {
+//				panic("mock out the Readiness method")
+//			},
+//		}
+//
+//		// use mockedReadinessService in code that requires ReadinessService
+//		// and then make assertions.
+//
+//	}
+type ReadinessServiceMock struct {
+	// ReadinessFunc mocks the Readiness method.
+	ReadinessFunc func(ctx context.Context) *providerquery.Readiness
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Readiness holds details about calls to the Readiness method.
+		Readiness []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockReadiness sync.RWMutex
+}
+
+// Readiness calls ReadinessFunc.
+func (mock *ReadinessServiceMock) Readiness(ctx context.Context) *providerquery.Readiness {
+	if mock.ReadinessFunc == nil {
+		panic("ReadinessServiceMock.ReadinessFunc: method is nil but ReadinessService.Readiness was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockReadiness.Lock()
+	mock.calls.Readiness = append(mock.calls.Readiness, callInfo)
+	mock.lockReadiness.Unlock()
+	return mock.ReadinessFunc(ctx)
+}
+
+// ReadinessCalls gets all the calls that were made to Readiness.
+// Check the length with:
+//
+//	len(mockedReadinessService.ReadinessCalls())
+func (mock *ReadinessServiceMock) ReadinessCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockReadiness.RLock()
+	calls = mock.calls.Readiness
+	mock.lockReadiness.RUnlock()
+	return calls
+}
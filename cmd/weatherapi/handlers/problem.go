@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// The machine readable codes of the problems returned from the API (see [ProblemResponse]).
+// These are stable, unlike the human readable detail.
+const (
+	ProblemCodeInvalidInput         = "invalid_input"         // 400, the request parameters are invalid.
+	ProblemCodeLocationNotFound     = "location_not_found"    // 404, no provider knows the location.
+	ProblemCodeInternal             = "internal_error"        // 500, an unexpected error.
+	ProblemCodeProvidersUnavailable = "providers_unavailable" // 503, no provider could answer & no (fresh enough) result is cached.
+	ProblemCodeUpstreamTimeout      = "upstream_timeout"      // 504, the providers didn't answer in time.
+)
+
+// correlationIDHeader is the header a request's correlation ID is read from & echoed in.
+const correlationIDHeader = "X-Correlation-Id"
+
+// ProblemResponse is returned from the API in the event of an error, as an RFC 7807 problem
+// details object (content type "application/problem+json").
+type ProblemResponse struct {
+	Type   string `json:"type"`   // Always "about:blank", problems are told apart by Code.
+	Title  string `json:"title"`  // The status text of Status, e.g "Not Found".
+	Status int    `json:"status"` // The response status code.
+	Detail string `json:"detail"` // A human readable explanation of the problem.
+
+	// Extension members.
+
+	Code          string `json:"code"`                     // One of the ProblemCode* constants.
+	CorrelationID string `json:"correlation_id,omitempty"` // The correlation ID of the request, if any.
+}
+
+// readErrorResponse writes the problem response for err, returned reading a result from
+// providerquery (within the handler's timeout, hence a deadline being exceeded is an upstream
+// timeout).
+func readErrorResponse(logger logr.Logger, rw http.ResponseWriter, err error) {
+	var tooStaleErr *providerquery.TooStaleError
+
+	switch {
+	case errors.As(err, &tooStaleErr):
+		unavailableResponse(logger, rw, tooStaleErr.RetryAfter)
+	case errors.Is(err, providerquery.ErrInvalidInput):
+		problemResponse(logger, rw, http.StatusBadRequest, ProblemCodeInvalidInput, "The location is invalid.")
+	case errors.Is(err, providerquery.ErrLocationNotFound):
+		problemResponse(logger, rw, http.StatusNotFound, ProblemCodeLocationNotFound, "Location not found.")
+	case errors.Is(err, providerquery.ErrProvidersUnavailable):
+		problemResponse(logger, rw, http.StatusServiceUnavailable, ProblemCodeProvidersUnavailable, "Weather is temporarily unavailable, try again later.")
+	case errors.Is(err, providerquery.ErrUpstreamTimeout), errors.Is(err, context.DeadlineExceeded):
+		problemResponse(logger, rw, http.StatusGatewayTimeout, ProblemCodeUpstreamTimeout, "Weather providers didn't respond in time, try again later.")
+	default:
+		problemResponse(logger, rw, http.StatusInternalServerError, ProblemCodeInternal, "Woops, something went wrong.")
+	}
+}
+
+// unavailableResponse writes a service unavailable response, suggesting the client retries after
+// retryAfter (rounded up to the second).
+func unavailableResponse(logger logr.Logger, rw http.ResponseWriter, retryAfter time.Duration) {
+	retryAfterSeconds := int64(math.Ceil(retryAfter.Seconds()))
+	if retryAfterSeconds < 1 {
+		retryAfterSeconds = 1
+	}
+
+	rw.Header().Set("Retry-After", strconv.FormatInt(retryAfterSeconds, 10))
+
+	problemResponse(logger, rw, http.StatusServiceUnavailable, ProblemCodeProvidersUnavailable, "Weather is temporarily unavailable, try again later.")
+}
+
+// problemResponse writes a [ProblemResponse] with status, code & detail. The correlation ID is
+// read from the response header, where it's echoed by the correlation ID middleware.
+func problemResponse(logger logr.Logger, rw http.ResponseWriter, status int, code, detail string) {
+	problem := ProblemResponse{
+		Type:          "about:blank",
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        detail,
+		Code:          code,
+		CorrelationID: rw.Header().Get(correlationIDHeader),
+	}
+
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(status)
+
+	if err := json.NewEncoder(rw).Encode(&problem); err != nil {
+		logger.Error(err, "Failed to encode error response body.")
+
+		http.Error(rw, detail, status)
+	}
+}
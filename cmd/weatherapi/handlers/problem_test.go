@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestReadErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		giveErr      error
+		expectedCode int
+		expected     ProblemResponse
+	}{
+		{
+			name:         "invalid_input",
+			giveErr:      fmt.Errorf("Test123: %w", providerquery.ErrInvalidInput),
+			expectedCode: http.StatusBadRequest,
+			expected:     ProblemResponse{Title: "Bad Request", Code: ProblemCodeInvalidInput},
+		},
+		{
+			name:         "location_not_found",
+			giveErr:      fmt.Errorf("Test123: %w", providerquery.ErrLocationNotFound),
+			expectedCode: http.StatusNotFound,
+			expected:     ProblemResponse{Title: "Not Found", Code: ProblemCodeLocationNotFound},
+		},
+		{
+			name:         "providers_unavailable",
+			giveErr:      fmt.Errorf("Test123: %w", providerquery.ErrProvidersUnavailable),
+			expectedCode: http.StatusServiceUnavailable,
+			expected:     ProblemResponse{Title: "Service Unavailable", Code: ProblemCodeProvidersUnavailable},
+		},
+		{
+			name:         "too_stale",
+			giveErr:      &providerquery.TooStaleError{RetryAfter: time.Second},
+			expectedCode: http.StatusServiceUnavailable,
+			expected:     ProblemResponse{Title: "Service Unavailable", Code: ProblemCodeProvidersUnavailable},
+		},
+		{
+			name:         "upstream_timeout",
+			giveErr:      fmt.Errorf("Test123: %w", providerquery.ErrUpstreamTimeout),
+			expectedCode: http.StatusGatewayTimeout,
+			expected:     ProblemResponse{Title: "Gateway Timeout", Code: ProblemCodeUpstreamTimeout},
+		},
+		{
+			name:         "deadline_exceeded",
+			giveErr:      fmt.Errorf("Test123: %w", context.DeadlineExceeded),
+			expectedCode: http.StatusGatewayTimeout,
+			expected:     ProblemResponse{Title: "Gateway Timeout", Code: ProblemCodeUpstreamTimeout},
+		},
+		{
+			name:         "unexpected",
+			giveErr:      errors.New("Test123"),
+			expectedCode: http.StatusInternalServerError,
+			expected:     ProblemResponse{Title: "Internal Server Error", Code: ProblemCodeInternal},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			rr.Header().Set("X-Correlation-Id", "ABC123")
+
+			readErrorResponse(nooplogr.New(), rr, tc.giveErr)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "response status code")
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"), "response content type")
+
+			var actual ProblemResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&actual), "response body")
+
+			assert.Equal(t, "about:blank", actual.Type)
+			assert.Equal(t, tc.expected.Title, actual.Title)
+			assert.Equal(t, tc.expectedCode, actual.Status)
+			assert.NotEmpty(t, actual.Detail)
+			assert.Equal(t, tc.expected.Code, actual.Code)
+			assert.Equal(t, "ABC123", actual.CorrelationID)
+		})
+	}
+}
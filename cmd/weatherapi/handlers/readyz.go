@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// The status of the application or one of its dependencies, as returned by the readyz handler.
+const (
+	readyzStatusOK       = "ok"
+	readyzStatusFailing  = "failing"
+	readyzStatusDraining = "draining"
+)
+
+// ReadinessService is used to query the state of the dependencies results are read from.
+type ReadinessService interface {
+	Readiness(ctx context.Context) *providerquery.Readiness
+}
+
+// ReadinessThresholds decide whether the application is ready to serve traffic.
+type ReadinessThresholds struct {
+	// Fewer available providers (those not being skipped by their circuit breaker) than this
+	// fails readiness.
+	MinAvailableProviders int
+
+	// The freshest result being older than this fails readiness. 0 disables the check, as
+	// does no result having been read yet.
+	MaxResultAge time.Duration
+
+	// If true, an unreachable cache fails readiness. Otherwise an unreachable cache is only
+	// reported, as results are still served (e.g. from an in-memory fallback).
+	CacheRequired bool
+}
+
+// ReadyzResponse is returned from the API for a readiness request.
+type ReadyzResponse struct {
+	Status         string                   `json:"status"` // One of "ok", "failing" or "draining".
+	Providers      []ReadyzProviderResponse `json:"providers"`
+	Cache          ReadyzCacheResponse      `json:"cache"`
+	FreshestResult ReadyzResultResponse     `json:"freshest_result"`
+}
+
+// ReadyzProviderResponse is the state of a provider.
+type ReadyzProviderResponse struct {
+	Provider    string     `json:"provider"`
+	Status      string     `json:"status"`
+	Breaker     string     `json:"breaker"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// ReadyzCacheResponse is the state of the cache.
+type ReadyzCacheResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadyzResultResponse is the state of the freshest result read.
+type ReadyzResultResponse struct {
+	Status     string   `json:"status"`
+	AgeSeconds *float64 `json:"age_seconds,omitempty"` // Omitted if no result has been read yet.
+}
+
+// ReadyzHandler is a handler that can be used to check whether the application is ready to
+// serve traffic, as opposed to merely alive (see [NewHealthzHandler]). It responds 200 OK when
+// ready, otherwise 503 Service Unavailable, along with the status of each dependency.
+type ReadyzHandler struct {
+	readinessService     ReadinessService
+	thresholds           ReadinessThresholds
+	getLoggerFromContext func(context.Context) logr.Logger
+	draining             atomic.Bool
+}
+
+// NewReadyzHandler creates a new [ReadyzHandler] deciding readiness using thresholds.
+func NewReadyzHandler(
+	readinessService ReadinessService,
+	thresholds ReadinessThresholds,
+	getLoggerFromContext func(context.Context) logr.Logger,
+) *ReadyzHandler {
+	if getLoggerFromContext == nil {
+		noopLogger := nooplogr.New()
+
+		getLoggerFromContext = func(ctx context.Context) logr.Logger {
+			return noopLogger
+		}
+	}
+
+	return &ReadyzHandler{
+		readinessService:     readinessService,
+		thresholds:           thresholds,
+		getLoggerFromContext: getLoggerFromContext,
+	}
+}
+
+// Drain fails readiness from now on, such that load balancers stop sending traffic ahead of
+// the server shutting down.
+func (h *ReadyzHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// ServeHTTP responds with the readiness of the application.
+func (h *ReadyzHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := h.getLoggerFromContext(req.Context())
+
+	readiness := h.readinessService.Readiness(req.Context())
+
+	response := ReadyzResponse{
+		Status:         readyzStatusOK,
+		Providers:      make([]ReadyzProviderResponse, 0, len(readiness.Providers)),
+		Cache:          ReadyzCacheResponse{Status: readyzStatusOK},
+		FreshestResult: ReadyzResultResponse{Status: readyzStatusOK},
+	}
+
+	availableProviders := 0
+
+	for _, provider := range readiness.Providers {
+		providerResponse := ReadyzProviderResponse{
+			Provider: provider.Provider,
+			Status:   readyzStatusFailing,
+			Breaker:  provider.Breaker,
+		}
+
+		if provider.Available {
+			providerResponse.Status = readyzStatusOK
+			availableProviders++
+		}
+
+		if !provider.LastSuccess.IsZero() {
+			lastSuccess := provider.LastSuccess.UTC()
+			providerResponse.LastSuccess = &lastSuccess
+		}
+
+		response.Providers = append(response.Providers, providerResponse)
+	}
+
+	if availableProviders < h.thresholds.MinAvailableProviders {
+		response.Status = readyzStatusFailing
+	}
+
+	if readiness.CacheErr != nil {
+		response.Cache = ReadyzCacheResponse{Status: readyzStatusFailing, Error: readiness.CacheErr.Error()}
+
+		if h.thresholds.CacheRequired {
+			response.Status = readyzStatusFailing
+		}
+	}
+
+	if !readiness.FreshestResult.IsZero() {
+		age := time.Since(readiness.FreshestResult)
+		ageSeconds := age.Seconds()
+		response.FreshestResult.AgeSeconds = &ageSeconds
+
+		if h.thresholds.MaxResultAge > 0 && age > h.thresholds.MaxResultAge {
+			response.FreshestResult.Status = readyzStatusFailing
+			response.Status = readyzStatusFailing
+		}
+	}
+
+	if h.draining.Load() {
+		response.Status = readyzStatusDraining
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Cache-Control", "no-store")
+
+	if response.Status != readyzStatusOK {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(rw).Encode(&response); err != nil {
+		logger.Error(err, "Failed to encode response body.")
+	}
+}
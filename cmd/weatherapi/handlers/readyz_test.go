@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	t.Parallel()
+
+	lastSuccess := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name               string
+		giveReadiness      *providerquery.Readiness
+		giveThresholds     ReadinessThresholds
+		giveDraining       bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "ready",
+			giveReadiness: &providerquery.Readiness{
+				Providers: []providerquery.ProviderReadiness{
+					{Provider: "goodProvider", Breaker: "closed", Available: true, LastSuccess: lastSuccess},
+					{Provider: "errProvider", Breaker: "open"},
+				},
+			},
+			giveThresholds:     ReadinessThresholds{MinAvailableProviders: 1},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"status":"ok","providers":[` +
+				`{"provider":"goodProvider","status":"ok","breaker":"closed","last_success":"2020-11-11T10:10:10Z"},` +
+				`{"provider":"errProvider","status":"failing","breaker":"open"}],` +
+				`"cache":{"status":"ok"},"freshest_result":{"status":"ok"}}` + "\n",
+		},
+		{
+			name: "providers_unavailable",
+			giveReadiness: &providerquery.Readiness{
+				Providers: []providerquery.ProviderReadiness{
+					{Provider: "errProvider", Breaker: "open"},
+				},
+			},
+			giveThresholds:     ReadinessThresholds{MinAvailableProviders: 1},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"failing","providers":[` +
+				`{"provider":"errProvider","status":"failing","breaker":"open"}],` +
+				`"cache":{"status":"ok"},"freshest_result":{"status":"ok"}}` + "\n",
+		},
+		{
+			name:               "cache_unreachable",
+			giveReadiness:      &providerquery.Readiness{CacheErr: errors.New("Test123")},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"ok","providers":[],"cache":{"status":"failing","error":"Test123"},"freshest_result":{"status":"ok"}}` + "\n",
+		},
+		{
+			name:               "cache_required_unreachable",
+			giveReadiness:      &providerquery.Readiness{CacheErr: errors.New("Test123")},
+			giveThresholds:     ReadinessThresholds{CacheRequired: true},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `{"status":"failing","providers":[],"cache":{"status":"failing","error":"Test123"},"freshest_result":{"status":"ok"}}` + "\n",
+		},
+		{
+			name:               "draining",
+			giveReadiness:      &providerquery.Readiness{},
+			giveDraining:       true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `{"status":"draining","providers":[],"cache":{"status":"ok"},"freshest_result":{"status":"ok"}}` + "\n",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &ReadinessServiceMock{
+				ReadinessFunc: func(ctx context.Context) *providerquery.Readiness {
+					return tc.giveReadiness
+				},
+			}
+
+			handler := NewReadyzHandler(service, tc.giveThresholds, nil)
+			if tc.giveDraining {
+				handler.Drain()
+			}
+
+			rw := httptest.NewRecorder()
+
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", "/readyz", nil))
+
+			assert.Equal(t, tc.expectedStatusCode, rw.Code, "response status code")
+			assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "response cache control")
+			assert.Equal(t, tc.expectedBody, rw.Body.String(), "response body")
+		})
+	}
+
+	t.Run("result_too_old", func(t *testing.T) {
+		t.Parallel()
+
+		service := &ReadinessServiceMock{
+			ReadinessFunc: func(ctx context.Context) *providerquery.Readiness {
+				return &providerquery.Readiness{FreshestResult: time.Now().Add(-time.Hour)}
+			},
+		}
+
+		rw := httptest.NewRecorder()
+
+		NewReadyzHandler(service, ReadinessThresholds{MaxResultAge: time.Minute}, nil).ServeHTTP(rw, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rw.Code, "response status code")
+		assert.Contains(t, rw.Body.String(), `"freshest_result":{"status":"failing","age_seconds":3600`, "response body")
+	})
+}
@@ -4,28 +4,87 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-logr/logr"
 
+	"github.com/byatesrae/weather"
 	"github.com/byatesrae/weather/internal/platform/nooplogr"
 	"github.com/byatesrae/weather/internal/providerquery"
 )
 
-const supportedCity = "Sydney"
+// maxCityLength is the maximum length (in characters) of a city name.
+const maxCityLength = 100
 
-// ErrorResponse is returned from the API in the event of an error.
-type ErrorResponse struct {
-	Message string `json:"msg"`
+// WeatherResponse is returned from the API for a successful weather request.
+type WeatherResponse struct {
+	weather.Summary
+
+	// Provenance of the summary.
+
+	Provider   string    `json:"provider"`    // The name of the provider the summary was fetched from.
+	FetchedAt  time.Time `json:"fetched_at"`  // When the summary was fetched from the provider.
+	Stale      bool      `json:"stale"`       // True if the summary could not be refreshed after expiring.
+	AgeSeconds int64     `json:"age_seconds"` // The number of seconds since the summary was fetched.
+
+	// Set if the summary was aggregated from multiple providers.
+	Debug *WeatherDebugResponse `json:"debug,omitempty"`
+}
+
+// WeatherDebugResponse details how a weather summary was aggregated from multiple providers.
+type WeatherDebugResponse struct {
+	Sources []WeatherSourceResponse `json:"sources"`
+}
+
+// WeatherSourceResponse is the weather summary from a single provider.
+type WeatherSourceResponse struct {
+	Provider string           `json:"provider"`
+	Outlier  bool             `json:"outlier"` // If true, the summary was rejected & not aggregated.
+	Weather  *weather.Summary `json:"weather"`
+}
+
+// newWeatherResponse creates a [WeatherResponse] from result.
+func newWeatherResponse(result *providerquery.WeatherResult) *WeatherResponse {
+	response := &WeatherResponse{
+		Provider:   result.Provider,
+		FetchedAt:  result.CreatedAt.UTC(),
+		Stale:      result.Stale,
+		AgeSeconds: int64(result.Age / time.Second),
+	}
+
+	if result.Weather != nil {
+		response.Summary = *result.Weather
+	}
+
+	if len(result.Sources) > 0 {
+		response.Debug = &WeatherDebugResponse{Sources: make([]WeatherSourceResponse, 0, len(result.Sources))}
+
+		for _, source := range result.Sources {
+			response.Debug.Sources = append(response.Debug.Sources, WeatherSourceResponse{
+				Provider: source.Provider,
+				Outlier:  source.Outlier,
+				Weather:  source.Weather,
+			})
+		}
+	}
+
+	return response
 }
 
-// WeatherService is used to query weather for a city.
+// WeatherService is used to query weather for a city or coordinates.
 type WeatherService interface {
 	ReadWeatherResult(ctx context.Context, city string) (*providerquery.WeatherResult, error)
+	ReadWeatherResultByCoordinates(ctx context.Context, coordinates providerquery.Coordinates) (*providerquery.WeatherResult, error)
 }
 
-// NewWeatherHandler creates a new handler that can be used to query a weather summary for a city location.
+// NewWeatherHandler creates a new handler that can be used to query a weather summary for a city location
+// (parameter "city") or for coordinates (parameters "lat" & "lon").
 func NewWeatherHandler(
 	weatherService WeatherService,
 	loadResultTimeout time.Duration,
@@ -39,20 +98,9 @@ func NewWeatherHandler(
 			logger = getLoggerFromContext(req.Context())
 		}
 
-		city := req.URL.Query().Get("city")
-		if city == "" {
-			errorResponse(logger, rw, "Missing parameter \"city\".", http.StatusBadRequest)
-
-			return
-		}
-
-		if city != supportedCity {
-			errorResponse(
-				logger,
-				rw,
-				fmt.Sprintf("City %q is not supported. Only %q is currently supported.", city, supportedCity),
-				http.StatusBadRequest,
-			)
+		read, message := parseWeatherRequest(weatherService, req.URL.Query())
+		if message != "" {
+			problemResponse(logger, rw, http.StatusBadRequest, ProblemCodeInvalidInput, message)
 
 			return
 		}
@@ -60,38 +108,121 @@ func NewWeatherHandler(
 		readWeatherCtx, readWeatherCancel := context.WithTimeout(req.Context(), loadResultTimeout)
 		defer readWeatherCancel()
 
-		result, err := weatherService.ReadWeatherResult(readWeatherCtx, city)
+		result, err := read(readWeatherCtx)
 		if err != nil {
-			errorResponse(
-				logger,
-				rw,
-				"Woops, something went wrong.",
-				http.StatusInternalServerError,
-			)
+			readErrorResponse(logger, rw, err)
+
+			return
 		}
 
-		if result != nil {
-			rw.Header().Set("Content-Type", "application/json")
-			rw.Header().Set("Cache-Control", "public")
-			rw.Header().Set("Last-modified", result.CreatedAt.Format(http.TimeFormat))
-			rw.Header().Set("Expires", result.Expiry.Format(http.TimeFormat))
+		setStaleHeaders(rw, result.Stale, result.Age)
+		resultResponse(logger, rw, result.CreatedAt, result.Expiry, newWeatherResponse(result))
+	}
+}
 
-			if err := json.NewEncoder(rw).Encode(result.Weather); err != nil {
-				logger.Error(err, "Failed to encode response body.")
+// parseWeatherRequest parses the query parameters of a weather request. It returns a function
+// to read the requested weather result or, if the parameters are invalid, a message describing
+// why.
+func parseWeatherRequest(
+	weatherService WeatherService,
+	query url.Values,
+) (func(ctx context.Context) (*providerquery.WeatherResult, error), string) {
+	city := strings.TrimSpace(query.Get("city"))
+	latitude, longitude := query.Get("lat"), query.Get("lon")
+
+	if latitude != "" || longitude != "" {
+		if city != "" {
+			return nil, "Specify either parameter \"city\" or parameters \"lat\" and \"lon\", not both."
+		}
 
-				http.Error(rw, "", http.StatusInternalServerError)
-			}
+		coordinates, message := parseCoordinates(latitude, longitude)
+		if message != "" {
+			return nil, message
 		}
+
+		return func(ctx context.Context) (*providerquery.WeatherResult, error) {
+			return weatherService.ReadWeatherResultByCoordinates(ctx, coordinates)
+		}, ""
 	}
+
+	if city == "" {
+		return nil, "Missing parameter \"city\" (or parameters \"lat\" and \"lon\")."
+	}
+
+	if message := validateCity(city); message != "" {
+		return nil, message
+	}
+
+	return func(ctx context.Context) (*providerquery.WeatherResult, error) {
+		return weatherService.ReadWeatherResult(ctx, city)
+	}, ""
+}
+
+// validateCity validates a (trimmed, non-empty) city parameter. If it is invalid then a
+// message describing why is returned.
+func validateCity(city string) string {
+	if utf8.RuneCountInString(city) > maxCityLength {
+		return fmt.Sprintf("Parameter \"city\" must be at most %v characters.", maxCityLength)
+	}
+
+	return ""
+}
+
+// parseCoordinates parses latitude & longitude parameters. If they are invalid then a message
+// describing why is returned.
+func parseCoordinates(latitude, longitude string) (providerquery.Coordinates, string) {
+	if latitude == "" {
+		return providerquery.Coordinates{}, "Missing parameter \"lat\"."
+	}
+
+	if longitude == "" {
+		return providerquery.Coordinates{}, "Missing parameter \"lon\"."
+	}
+
+	var coordinates providerquery.Coordinates
+	var err error
+
+	if coordinates.Latitude, err = strconv.ParseFloat(latitude, 64); err != nil || math.IsNaN(coordinates.Latitude) {
+		return providerquery.Coordinates{}, "Parameter \"lat\" must be a number."
+	}
+
+	if coordinates.Longitude, err = strconv.ParseFloat(longitude, 64); err != nil || math.IsNaN(coordinates.Longitude) {
+		return providerquery.Coordinates{}, "Parameter \"lon\" must be a number."
+	}
+
+	if coordinates.Latitude < -90 || coordinates.Latitude > 90 {
+		return providerquery.Coordinates{}, "Parameter \"lat\" must be in the range [-90, 90]."
+	}
+
+	if coordinates.Longitude < -180 || coordinates.Longitude > 180 {
+		return providerquery.Coordinates{}, "Parameter \"lon\" must be in the range [-180, 180]."
+	}
+
+	return coordinates, ""
+}
+
+// setStaleHeaders sets the headers of a response for a result that is age old, if the result is
+// stale (served past expiry because it could not be refreshed).
+func setStaleHeaders(rw http.ResponseWriter, stale bool, age time.Duration) {
+	if !stale {
+		return
+	}
+
+	rw.Header().Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	rw.Header().Set("Warning", `110 - "Response is Stale"`)
 }
 
-func errorResponse(logger logr.Logger, rw http.ResponseWriter, message string, code int) {
+// resultResponse writes a successful response with body encoded as JSON, including caching
+// headers derived from createdAt & expiry.
+func resultResponse(logger logr.Logger, rw http.ResponseWriter, createdAt, expiry time.Time, body interface{}) {
 	rw.Header().Set("Content-Type", "application/json")
-	rw.WriteHeader(code)
+	rw.Header().Set("Cache-Control", "public")
+	rw.Header().Set("Last-modified", createdAt.Format(http.TimeFormat))
+	rw.Header().Set("Expires", expiry.Format(http.TimeFormat))
 
-	if err := json.NewEncoder(rw).Encode(&ErrorResponse{Message: message}); err != nil {
-		logger.Error(err, "Failed to encode error response body.")
+	if err := json.NewEncoder(rw).Encode(body); err != nil {
+		logger.Error(err, "Failed to encode response body.")
 
-		http.Error(rw, message, code)
+		http.Error(rw, "", http.StatusInternalServerError)
 	}
 }
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,17 +24,61 @@ func TestWeatherHandler(t *testing.T) {
 		Weather:   &weather.Summary{Temperature: 123.456},
 		CreatedAt: now,
 		Expiry:    now.Add(time.Second * 5),
+		Provider:  "goodProvider",
+		Age:       time.Millisecond * 2500,
+	}
+	staleServiceResult := &providerquery.WeatherResult{
+		Weather:   goodServiceResult.Weather,
+		CreatedAt: now,
+		Expiry:    now.Add(time.Second * 5),
+		Provider:  "goodProvider",
+		Stale:     true,
+		Age:       time.Minute,
+	}
+	aggregatedServiceResult := &providerquery.WeatherResult{
+		Weather:   &weather.Summary{Temperature: 11},
+		CreatedAt: now,
+		Expiry:    now.Add(time.Second * 5),
+		Provider:  "a,b",
+		Sources: []providerquery.WeatherSource{
+			{Provider: "a", Weather: &weather.Summary{Temperature: 10}},
+			{Provider: "b", Weather: &weather.Summary{Temperature: 12}},
+			{Provider: "c", Weather: &weather.Summary{Temperature: 30}, Outlier: true},
+		},
+	}
+	aggregatedService := &WeatherServiceMock{
+		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
+			return aggregatedServiceResult, nil
+		},
 	}
 	goodService := &WeatherServiceMock{
 		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
 			return goodServiceResult, nil
 		},
+		ReadWeatherResultByCoordinatesFunc: func(ctx context.Context, coordinates providerquery.Coordinates) (*providerquery.WeatherResult, error) {
+			return goodServiceResult, nil
+		},
+	}
+	staleService := &WeatherServiceMock{
+		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
+			return staleServiceResult, nil
+		},
+	}
+	tooStaleService := &WeatherServiceMock{
+		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
+			return nil, &providerquery.TooStaleError{Staleness: time.Hour, MaxStaleness: time.Minute, RetryAfter: time.Millisecond * 2500}
+		},
 	}
 	errService := &WeatherServiceMock{
 		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
 			return nil, errors.New("intentional test error")
 		},
 	}
+	notFoundService := &WeatherServiceMock{
+		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
+			return nil, fmt.Errorf("intentional test error: %w", providerquery.ErrLocationNotFound)
+		},
+	}
 	hangService := &WeatherServiceMock{
 		ReadWeatherResultFunc: func(ctx context.Context, city string) (*providerquery.WeatherResult, error) {
 			<-ctx.Done()
@@ -44,46 +90,133 @@ func TestWeatherHandler(t *testing.T) {
 	goodRequest := httptest.NewRequest("GET", "/weather?city=Sydney", nil)
 
 	for _, tc := range []struct {
-		name         string
-		withHandler  http.HandlerFunc
-		giveRequest  *http.Request
-		expectedCode int
-		expectedBody []byte
+		name            string
+		withHandler     http.HandlerFunc
+		giveRequest     *http.Request
+		expectedCode    int
+		expectedBody    []byte
+		expectedHeaders map[string]string
 	}{
 		{
 			name:         "success",
 			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
 			giveRequest:  goodRequest,
 			expectedCode: http.StatusOK,
-			expectedBody: []byte("{\"wind_speed\":0,\"temperature_degrees\":123.456}\n"),
+			expectedBody: []byte("{\"wind_speed\":0,\"temperature_degrees\":123.456,\"provider\":\"goodProvider\",\"fetched_at\":\"2020-11-11T10:10:10Z\",\"stale\":false,\"age_seconds\":2}\n"),
+		},
+		{
+			name:         "success_stale",
+			withHandler:  NewWeatherHandler(staleService, time.Millisecond*100, nil),
+			giveRequest:  goodRequest,
+			expectedCode: http.StatusOK,
+			expectedBody: []byte("{\"wind_speed\":0,\"temperature_degrees\":123.456,\"provider\":\"goodProvider\",\"fetched_at\":\"2020-11-11T10:10:10Z\",\"stale\":true,\"age_seconds\":60}\n"),
+			expectedHeaders: map[string]string{
+				"Age":     "60",
+				"Warning": `110 - "Response is Stale"`,
+			},
+		},
+		{
+			name:         "success_aggregated",
+			withHandler:  NewWeatherHandler(aggregatedService, time.Millisecond*100, nil),
+			giveRequest:  goodRequest,
+			expectedCode: http.StatusOK,
+			expectedBody: []byte(`{"wind_speed":0,"temperature_degrees":11,"provider":"a,b","fetched_at":"2020-11-11T10:10:10Z","stale":false,"age_seconds":0,` +
+				`"debug":{"sources":[` +
+				`{"provider":"a","outlier":false,"weather":{"wind_speed":0,"temperature_degrees":10}},` +
+				`{"provider":"b","outlier":false,"weather":{"wind_speed":0,"temperature_degrees":12}},` +
+				`{"provider":"c","outlier":true,"weather":{"wind_speed":0,"temperature_degrees":30}}]}}` + "\n"),
 		},
 		{
 			name:         "city_empty",
 			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
 			giveRequest:  httptest.NewRequest("GET", "/weather", nil),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: []byte("{\"msg\":\"Missing parameter \\\"city\\\".\"}\n"),
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Missing parameter \\\"city\\\" (or parameters \\\"lat\\\" and \\\"lon\\\").\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "success_other_city",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?city=Melbourne", nil),
+			expectedCode: http.StatusOK,
+			expectedBody: []byte("{\"wind_speed\":0,\"temperature_degrees\":123.456,\"provider\":\"goodProvider\",\"fetched_at\":\"2020-11-11T10:10:10Z\",\"stale\":false,\"age_seconds\":2}\n"),
+		},
+		{
+			name:         "city_whitespace",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?city=%20%20", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Missing parameter \\\"city\\\" (or parameters \\\"lat\\\" and \\\"lon\\\").\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "city_too_long",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?city="+strings.Repeat("a", 101), nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Parameter \\\"city\\\" must be at most 100 characters.\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "success_coordinates",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?lat=-33.87&lon=151.21", nil),
+			expectedCode: http.StatusOK,
+			expectedBody: []byte("{\"wind_speed\":0,\"temperature_degrees\":123.456,\"provider\":\"goodProvider\",\"fetched_at\":\"2020-11-11T10:10:10Z\",\"stale\":false,\"age_seconds\":2}\n"),
+		},
+		{
+			name:         "coordinates_and_city",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?city=Sydney&lat=-33.87&lon=151.21", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Specify either parameter \\\"city\\\" or parameters \\\"lat\\\" and \\\"lon\\\", not both.\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "lon_missing",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?lat=-33.87", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Missing parameter \\\"lon\\\".\",\"code\":\"invalid_input\"}\n"),
+		},
+		{
+			name:         "lat_not_a_number",
+			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?lat=abc&lon=151.21", nil),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Parameter \\\"lat\\\" must be a number.\",\"code\":\"invalid_input\"}\n"),
 		},
 		{
-			name:         "city_invalid",
+			name:         "lon_out_of_range",
 			withHandler:  NewWeatherHandler(goodService, time.Millisecond*100, nil),
-			giveRequest:  httptest.NewRequest("GET", "/weather?city=abc", nil),
+			giveRequest:  httptest.NewRequest("GET", "/weather?lat=-33.87&lon=181", nil),
 			expectedCode: http.StatusBadRequest,
-			expectedBody: []byte("{\"msg\":\"City \\\"abc\\\" is not supported. Only \\\"Sydney\\\" is currently supported.\"}\n"),
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Parameter \\\"lon\\\" must be in the range [-180, 180].\",\"code\":\"invalid_input\"}\n"),
 		},
 		{
 			name:         "weather_service_err",
 			withHandler:  NewWeatherHandler(errService, time.Millisecond*100, nil),
 			giveRequest:  goodRequest,
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: []byte("{\"msg\":\"Woops, something went wrong.\"}\n"),
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"detail\":\"Woops, something went wrong.\",\"code\":\"internal_error\"}\n"),
+		},
+		{
+			name:            "weather_service_too_stale",
+			withHandler:     NewWeatherHandler(tooStaleService, time.Millisecond*100, nil),
+			giveRequest:     goodRequest,
+			expectedCode:    http.StatusServiceUnavailable,
+			expectedBody:    []byte("{\"type\":\"about:blank\",\"title\":\"Service Unavailable\",\"status\":503,\"detail\":\"Weather is temporarily unavailable, try again later.\",\"code\":\"providers_unavailable\"}\n"),
+			expectedHeaders: map[string]string{"Retry-After": "3"},
+		},
+		{
+			name:         "weather_service_location_not_found",
+			withHandler:  NewWeatherHandler(notFoundService, time.Millisecond*100, nil),
+			giveRequest:  goodRequest,
+			expectedCode: http.StatusNotFound,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"Location not found.\",\"code\":\"location_not_found\"}\n"),
 		},
 		{
 			name:         "weather_service_hang",
 			withHandler:  NewWeatherHandler(hangService, time.Millisecond*100, nil),
 			giveRequest:  goodRequest,
-			expectedCode: http.StatusInternalServerError,
-			expectedBody: []byte("{\"msg\":\"Woops, something went wrong.\"}\n"),
+			expectedCode: http.StatusGatewayTimeout,
+			expectedBody: []byte("{\"type\":\"about:blank\",\"title\":\"Gateway Timeout\",\"status\":504,\"detail\":\"Weather providers didn't respond in time, try again later.\",\"code\":\"upstream_timeout\"}\n"),
 		},
 	} {
 		tc := tc
@@ -97,6 +230,10 @@ func TestWeatherHandler(t *testing.T) {
 
 			assert.Equal(t, tc.expectedCode, rr.Code)
 			assert.Equal(t, string(tc.expectedBody), rr.Body.String())
+
+			for name, value := range tc.expectedHeaders {
+				assert.Equal(t, value, rr.Header().Get(name), "header %s", name)
+			}
 		})
 	}
 }
@@ -48,6 +48,17 @@ func registerOpenweatherStub(t *testing.T, requestID string, h http.Handler) {
 	t.Cleanup(cleanup)
 }
 
+// registerOpenMeteoStub calls openMeteoStubServerHandler.Register(), checks the error and
+// handles the cleanup.
+func registerOpenMeteoStub(t *testing.T, requestID string, h http.Handler) {
+	t.Helper()
+
+	cleanup, err := openMeteoStubServerHandler.Register(requestID, h)
+	require.NoError(t, err, "openMeteoStubServerHandler register error")
+
+	t.Cleanup(cleanup)
+}
+
 // weatherRequest creates an http request for the weather endpoint.
 func weatherRequest(ctx context.Context, t *testing.T, serverURL, city string) *http.Request {
 	t.Helper()
@@ -64,6 +75,31 @@ func weatherRequest(ctx context.Context, t *testing.T, serverURL, city string) *
 	return req
 }
 
+// coordinatesWeatherRequest creates an http request for the weather endpoint using
+// coordinates.
+func coordinatesWeatherRequest(ctx context.Context, t *testing.T, serverURL string, latitude, longitude float64) *http.Request {
+	t.Helper()
+
+	url := fmt.Sprintf("%s/v1/weather?lat=%v&lon=%v", serverURL, latitude, longitude)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	require.NoError(t, err, "create weather request")
+
+	return req
+}
+
+// forecastRequest creates an http request for the forecast endpoint.
+func forecastRequest(ctx context.Context, t *testing.T, serverURL, city string, days int) *http.Request {
+	t.Helper()
+
+	url := fmt.Sprintf("%s/v1/forecast?city=%s&days=%v", serverURL, city, days)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	require.NoError(t, err, "create forecast request")
+
+	return req
+}
+
 // stubHandler creates a new http handler that returns a status code & body.
 func stubHandler(t *testing.T, statuscode int, body []byte) http.HandlerFunc {
 	t.Helper()
@@ -79,3 +115,36 @@ func stubHandler(t *testing.T, statuscode int, body []byte) http.HandlerFunc {
 		}
 	}
 }
+
+// registerNWSStub calls nwsStubServerHandler.Register(), checks the error and handles the
+// cleanup.
+func registerNWSStub(t *testing.T, requestID string, h http.Handler) {
+	t.Helper()
+
+	cleanup, err := nwsStubServerHandler.Register(requestID, h)
+	require.NoError(t, err, "nwsStubServerHandler register error")
+
+	t.Cleanup(cleanup)
+}
+
+// registerMetNoStub calls metNoStubServerHandler.Register(), checks the error and handles the
+// cleanup.
+func registerMetNoStub(t *testing.T, requestID string, h http.Handler) {
+	t.Helper()
+
+	cleanup, err := metNoStubServerHandler.Register(requestID, h)
+	require.NoError(t, err, "metNoStubServerHandler register error")
+
+	t.Cleanup(cleanup)
+}
+
+// registerJSONProviderStub calls jsonProviderStubServerHandler.Register(), checks the error and
+// handles the cleanup.
+func registerJSONProviderStub(t *testing.T, requestID string, h http.Handler) {
+	t.Helper()
+
+	cleanup, err := jsonProviderStubServerHandler.Register(requestID, h)
+	require.NoError(t, err, "jsonProviderStubServerHandler register error")
+
+	t.Cleanup(cleanup)
+}
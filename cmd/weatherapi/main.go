@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -18,6 +19,7 @@ import (
 	"github.com/rs/zerolog"
 	hostmetrics "go.opentelemetry.io/contrib/instrumentation/host"
 	runtimemetrics "go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
@@ -25,14 +27,24 @@ import (
 	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
 	selector "go.opentelemetry.io/otel/sdk/metric/selector/simple"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/byatesrae/weather/cmd/weatherapi/handlers"
 	"github.com/byatesrae/weather/cmd/weatherapi/providers"
+	"github.com/byatesrae/weather/internal/jsonprovider"
 	"github.com/byatesrae/weather/internal/memorycache"
+	"github.com/byatesrae/weather/internal/metno"
+	"github.com/byatesrae/weather/internal/nws"
+	"github.com/byatesrae/weather/internal/openmeteo"
 	"github.com/byatesrae/weather/internal/openweather"
 	"github.com/byatesrae/weather/internal/otelmetrics"
+	"github.com/byatesrae/weather/internal/oteltracing"
+	"github.com/byatesrae/weather/internal/peercache"
+	"github.com/byatesrae/weather/internal/platform/apiclient"
 	"github.com/byatesrae/weather/internal/providerquery"
+	"github.com/byatesrae/weather/internal/rediscache"
 	"github.com/byatesrae/weather/internal/weatherstack"
 )
 
@@ -60,7 +72,20 @@ func main() {
 	ctx := context.Background()
 	ctx = setLoggerInContext(ctx, logger)
 
-	server, err := createServer(logger, config)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Error(err, "Otel error.")
+	}))
+
+	tracerProvider, err := newTracerProvider(ctx, component, "v0.0.0", "local", config)
+	if err != nil {
+		logger.Error(err, "Failed to create tracer provider.")
+		os.Exit(1)
+	}
+
+	backgroundCtx, backgroundCancel := context.WithCancel(ctx)
+	defer backgroundCancel()
+
+	server, memoryCache, readyzHandler, err := createServer(backgroundCtx, logger, config, tracerProvider)
 	if err != nil {
 		logger.Error(err, "Failed to create server.")
 		os.Exit(1)
@@ -81,6 +106,15 @@ func main() {
 
 	logger.Info("Interrupted!")
 
+	// Load balancers stop sending traffic once readiness fails, before the server stops
+	// accepting it.
+	readyzHandler.Drain()
+
+	logger.Info("Draining.", "delay", config.ShutdownDrainDelay)
+	time.Sleep(config.ShutdownDrainDelay)
+
+	backgroundCancel()
+
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
@@ -89,6 +123,19 @@ func main() {
 		runtime.Goexit()
 	}
 
+	if config.SnapshotPath != "" {
+		if n, err := memoryCache.SaveSnapshot(config.SnapshotPath); err != nil {
+			logger.Error(err, "Failed to save cache snapshot.")
+		} else {
+			logger.Info("Cache snapshot saved.", "path", config.SnapshotPath, "entries", n)
+		}
+	}
+
+	// Export any spans still buffered.
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		logger.Error(err, "Failed to shut down tracer provider.")
+	}
+
 	logger.Info("Server exited.")
 }
 
@@ -99,72 +146,325 @@ func newLogger(name string, colourized bool) logr.Logger {
 	return zerologr.New(&zl).WithName(name)
 }
 
+// createServer creates the http server, as well as the in-memory cache it uses (to be snapshot
+// on shutdown) and its readiness handler (to be drained on shutdown). Background work (e.g
+// keeping hot cities warm) runs until ctx is done.
 func createServer(
+	ctx context.Context,
 	logger logr.Logger,
 	config *appConfig,
-) (*http.Server, error) {
+	tracerProvider trace.TracerProvider,
+) (*http.Server, *memorycache.MemoryCache, *handlers.ReadyzHandler, error) {
 	metricController, err := instrument(component, "v0.0.0", "local")
 	if err != nil {
-		return nil, fmt.Errorf("instrument application: %w", err)
+		return nil, nil, nil, fmt.Errorf("instrument application: %w", err)
 	}
 
 	prometheusExporter, err := exportToPrometheus(metricController)
 	if err != nil {
-		return nil, fmt.Errorf("export to prometheus: %w", err)
+		return nil, nil, nil, fmt.Errorf("export to prometheus: %w", err)
+	}
+
+	memoryCacheMetrics, err := memorycache.NewMetrics(metricController.Meter(""))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create memory cache metrics: %w", err)
+	}
+
+	providerQueryerMetrics, err := providerquery.NewMetrics(metricController.Meter(""))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create provider queryer metrics: %w", err)
+	}
+
+	orderingStrategy, err := newOrderingStrategy(config.ProviderOrdering, config.ProviderErrorPenalty)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create provider ordering strategy: %w", err)
+	}
+
+	providerWeights, err := parseProviderWeights(config.ProviderWeights)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse provider weights: %w", err)
+	}
+
+	var jsonProviderDefinitions []*jsonprovider.Definition
+	if config.JSONProvidersPath != "" {
+		if jsonProviderDefinitions, err = jsonprovider.LoadFile(config.JSONProvidersPath); err != nil {
+			return nil, nil, nil, fmt.Errorf("load json providers: %w", err)
+		}
 	}
 
 	providerHTTPClient := http.Client{Timeout: config.ResultTimeout}
 
-	providerQueryer := providerquery.New(
-		[]providerquery.Provider{
-			providers.NewOpenWeatherProvider(
-				openweather.New(
-					config.OpenweatherEndpointURL,
-					config.OpenweatherAPIKey,
-					openweather.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
-					openweather.WithGetLoggerFromContext(getLoggerFromContext),
-				),
+	// Results are kept in memory for at least as long as they may be served stale.
+	memoryCacheExpiryGrace := config.MemoryCacheExpiryGrace
+	if config.MaxStaleness > memoryCacheExpiryGrace {
+		memoryCacheExpiryGrace = config.MaxStaleness
+	}
+
+	// Used as is, or as the local part of a shared cache.
+	memoryCache := memorycache.New(
+		memorycache.WithMaxEntries(config.MemoryCacheMaxEntries),
+		memorycache.WithExpiryGrace(memoryCacheExpiryGrace),
+		memorycache.WithJanitorInterval(config.MemoryCacheJanitorInterval),
+		memorycache.WithMetrics(memoryCacheMetrics),
+		memorycache.WithGetLoggerFromContext(getLoggerFromContext),
+	)
+
+	go memoryCache.RunJanitor(ctx)
+
+	if config.SnapshotPath != "" {
+		n, err := memoryCache.LoadSnapshot(ctx, config.SnapshotPath, config.MaxStaleness)
+		if err != nil {
+			// Starting cold is better than not starting.
+			logger.Error(err, "Failed to load cache snapshot.")
+		} else {
+			logger.Info("Cache snapshot loaded.", "path", config.SnapshotPath, "entries", n)
+		}
+
+		go memoryCache.RunSnapshots(ctx, config.SnapshotPath, config.SnapshotInterval)
+	}
+
+	var resultCache providerquery.Cache = memoryCache
+	var peerCache *peercache.PeerCache
+
+	cacheTimeout := time.Second * 2
+
+	switch config.Cache {
+	case "redis":
+		resultCache = newRedisCache(config, memoryCache)
+	case "peer":
+		peerCache = newPeerCache(ctx, config, memoryCache)
+		resultCache = peerCache
+
+		// Reading a result may wait for its owner to load it.
+		cacheTimeout = config.ResultTimeout
+	}
+
+	queryerProviders := []providerquery.Provider{
+		providers.NewOpenWeatherProvider(
+			openweather.New(
+				config.OpenweatherEndpointURL,
+				config.OpenweatherAPIKey,
+				openweather.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
+				openweather.WithGetLoggerFromContext(getLoggerFromContext),
+				openweather.WithTracerProvider(tracerProvider),
 			),
-			providers.NewWeatherStackProvider(
-				weatherstack.New(
-					config.WeatherstackEndpointURL,
-					config.WeatherstackAccessKey,
-					weatherstack.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
-					weatherstack.WithGetLoggerFromContext(getLoggerFromContext),
-				),
+		),
+		providers.NewWeatherStackProvider(
+			weatherstack.New(
+				config.WeatherstackEndpointURL,
+				config.WeatherstackAccessKey,
+				weatherstack.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
+				weatherstack.WithGetLoggerFromContext(getLoggerFromContext),
+				weatherstack.WithTracerProvider(tracerProvider),
 			),
-		},
-		memorycache.New(),
+		),
+	}
+
+	// The options shared by the clients of the providers below.
+	apiClientOptions := []func(*apiclient.NewOptions){
+		apiclient.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
+		apiclient.WithGetLoggerFromContext(getLoggerFromContext),
+		apiclient.WithTracerProvider(tracerProvider),
+	}
+
+	// Providers that can only be queried by coordinates resolve city names with the Open-Meteo
+	// geocoding API.
+	var geocoder *openmeteo.Client
+	if config.OpenMeteoGeocodingEndpointURL != "" {
+		geocoder = openmeteo.New(
+			"",
+			config.OpenMeteoGeocodingEndpointURL,
+			openmeteo.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
+			openmeteo.WithGetLoggerFromContext(getLoggerFromContext),
+			openmeteo.WithTracerProvider(tracerProvider),
+		)
+	}
+
+	// The NWS covers only the US, answering queries for other locations such that the next
+	// provider is queried.
+	if config.NWSEndpointURL != "" && config.NWSUserAgent != "" {
+		queryerProviders = append(queryerProviders, providers.NewNWSProvider(
+			nws.New(
+				config.NWSEndpointURL,
+				config.NWSUserAgent,
+				config.NWSPointsCacheTTL,
+				apiClientOptions...,
+			),
+			geocoder,
+		))
+	}
+
+	if config.MetNoEndpointURL != "" && config.MetNoUserAgent != "" {
+		queryerProviders = append(queryerProviders, providers.NewMetNoProvider(
+			metno.New(
+				config.MetNoEndpointURL,
+				config.MetNoUserAgent,
+				apiClientOptions...,
+			),
+			geocoder,
+		))
+	}
+
+	for _, definition := range jsonProviderDefinitions {
+		queryerProviders = append(queryerProviders, providers.NewJSONProvider(
+			jsonprovider.New(
+				definition,
+				apiClientOptions...,
+			),
+			geocoder,
+		))
+	}
+
+	// Open-Meteo needs no API key, so is added last (being queried last with the "static"
+	// ordering) as a fallback should the others fail.
+	if config.OpenMeteoEndpointURL != "" {
+		queryerProviders = append(queryerProviders, providers.NewOpenMeteoProvider(
+			openmeteo.New(
+				config.OpenMeteoEndpointURL,
+				config.OpenMeteoGeocodingEndpointURL,
+				openmeteo.NewWithHTTPClient(&httpClientWithCorrelationID{innerClient: providerHTTPClient}),
+				openmeteo.WithGetLoggerFromContext(getLoggerFromContext),
+				openmeteo.WithTracerProvider(tracerProvider),
+			),
+		))
+	}
+
+	providerQueryer, err := providerquery.New(
+		queryerProviders,
+		resultCache,
+		providerquery.WithCacheTimeout(cacheTimeout),
+		providerquery.WithProviderTimeout(config.ProviderTimeout),
+		providerquery.WithResultTimeout(config.ResultTimeout),
 		providerquery.WithResultCacheTTL(config.ResultCacheTTL),
+		providerquery.WithForecastCacheTTL(config.ForecastCacheTTL),
+		providerquery.WithMaxStaleness(config.MaxStaleness),
+		providerquery.WithRefreshAheadWindow(config.RefreshAheadWindow),
+		providerquery.WithCoordinatePrecision(config.CoordinatePrecision),
 		providerquery.WithGetLoggerFromContext(getLoggerFromContext),
+		providerquery.WithTracerProvider(tracerProvider),
+		providerquery.WithMetrics(providerQueryerMetrics),
+		providerquery.WithOrderingStrategy(orderingStrategy),
+		providerquery.WithQueryMode(providerquery.QueryMode(config.ProviderQueryMode)),
+		providerquery.WithHedgeDelay(config.ProviderHedgeDelay),
+		providerquery.WithAggregationMethod(providerquery.AggregationMethod(config.AggregationMethod)),
+		providerquery.WithProviderWeights(providerWeights),
+		providerquery.WithOutlierTolerances(config.OutlierTolerances),
+		providerquery.WithBreakerFailureThreshold(config.BreakerFailureThreshold),
+		providerquery.WithBreakerCoolDown(config.BreakerCoolDown),
+		providerquery.WithBreakerHalfOpenProbes(config.BreakerHalfOpenProbes),
 	)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create provider queryer: %w", err)
+	}
+
+	if hotCities := parseList(config.HotCities); len(hotCities) > 0 {
+		hotCityScheduler := providerquery.NewHotCityScheduler(
+			providerQueryer,
+			hotCities,
+			providerquery.WithHotCityRefreshInterval(config.HotCityRefreshInterval),
+		)
+
+		go hotCityScheduler.Run(ctx)
+	}
 
 	healthzHandler := handlers.NewHealthzHandler(getLoggerFromContext)
+	readyzHandler := handlers.NewReadyzHandler(
+		providerQueryer,
+		handlers.ReadinessThresholds{
+			MinAvailableProviders: config.ReadyzMinAvailableProviders,
+			MaxResultAge:          config.ReadyzMaxResultAge,
+			CacheRequired:         config.ReadyzCacheRequired,
+		},
+		getLoggerFromContext,
+	)
 	weatherHandler := handlers.NewWeatherHandler(providerQueryer, config.ResultTimeout, getLoggerFromContext)
+	forecastHandler := handlers.NewForecastHandler(providerQueryer, config.ResultTimeout, getLoggerFromContext)
+	providerScoresHandler := handlers.NewProviderScoresHandler(providerQueryer, getLoggerFromContext)
 
 	metricsMiddleware, err := otelmetrics.MuxMiddleware(metricController.Meter(""))
 	if err != nil {
-		return nil, fmt.Errorf("create mux metrics middleware: %w", err)
+		return nil, nil, nil, fmt.Errorf("create mux metrics middleware: %w", err)
 	}
 
+	tracingMiddleware := oteltracing.MuxMiddleware(tracerProvider)
+
 	rootRouter := mux.NewRouter()
 	rootRouter.Path("/metrics").HandlerFunc(prometheusExporter.ServeHTTP)
 
+	if peerCache != nil {
+		// Only the peer owning a result loads it from providers.
+		peerCache.SetLoader(providerQueryer.ReadSerializedResult)
+
+		rootRouter.Path(peercache.Path).Handler(tracingMiddleware(correlationIDMiddleware(logger)(peerCache)))
+	}
+
 	v1Router := rootRouter.PathPrefix("/v1").Subrouter()
-	v1Router.Use(correlationIDMiddleware(logger), metricsMiddleware)
+	v1Router.Use(tracingMiddleware, correlationIDMiddleware(logger), metricsMiddleware)
 	v1Router.Path("/healthz").Methods("GET").HandlerFunc(healthzHandler)
+	v1Router.Path("/readyz").Methods("GET").Handler(readyzHandler)
 	v1Router.Path("/weather").Methods("GET").Handler(weatherHandler)
+	v1Router.Path("/forecast").Methods("GET").Handler(forecastHandler)
+	v1Router.Path("/debug/providers").Methods("GET").Handler(providerScoresHandler)
 
 	return &http.Server{
 		Addr:              fmt.Sprintf(":%v", config.Port),
 		Handler:           rootRouter,
 		ReadHeaderTimeout: time.Second * 1,
-	}, nil
+	}, memoryCache, readyzHandler, nil
+}
+
+// newRedisCache creates a cache, stored in Redis, for provider results. While Redis is
+// unreachable, fallback is used instead.
+func newRedisCache(config *appConfig, fallback rediscache.Cache) *rediscache.RedisCache {
+	options := []func(o *rediscache.NewOptions){
+		rediscache.WithFallback(fallback),
+		rediscache.WithPassword(config.RedisPassword),
+		rediscache.WithGetLoggerFromContext(getLoggerFromContext),
+	}
+
+	// Results are kept in Redis for as long as they may be served stale. Without a limit, they're
+	// kept for as long as in memory.
+	if config.MaxStaleness > 0 {
+		options = append(options, rediscache.WithStaleRetention(config.MaxStaleness))
+	} else {
+		options = append(options, rediscache.WithStaleRetention(config.MemoryCacheExpiryGrace))
+	}
+
+	return rediscache.New(config.RedisAddress, options...)
+}
+
+// newPeerCache creates a cache, shared with peers, for provider results. The results owned by
+// this peer are stored in local. Peers are discovered until ctx is done.
+func newPeerCache(ctx context.Context, config *appConfig, local peercache.Cache) *peercache.PeerCache {
+	peerCache := peercache.New(
+		config.PeerSelf,
+		peercache.WithLocal(local),
+		peercache.WithSecret(config.PeerSecret),
+		// The owner of a result may take as long as providers do to load it.
+		peercache.WithHTTPClient(&httpClientWithCorrelationID{innerClient: http.Client{Timeout: config.ResultTimeout}}),
+		// The owner's answer stands, rather than other replicas querying providers themselves.
+		peercache.WithLoadErrors(map[int]error{
+			http.StatusBadRequest:         providerquery.ErrInvalidInput,
+			http.StatusNotFound:           providerquery.ErrLocationNotFound,
+			http.StatusServiceUnavailable: providerquery.ErrProvidersUnavailable,
+			http.StatusGatewayTimeout:     providerquery.ErrUpstreamTimeout,
+		}),
+		peercache.WithGetLoggerFromContext(getLoggerFromContext),
+	)
+
+	if config.PeerSRV != "" {
+		go peerCache.DiscoverSRVPeers(ctx, net.DefaultResolver, config.PeerSRV, config.PeerSRVInterval)
+	} else {
+		peerCache.SetPeers(parseList(config.Peers))
+	}
+
+	return peerCache
 }
 
 type correlationIDCtxKey struct{}
 
-// correlationIDMiddleware is middleware that adds a correlation ID to the context.
+// correlationIDMiddleware is middleware that adds a correlation ID to the context, echoing it
+// in the "X-Correlation-Id" response header.
 func correlationIDMiddleware(logger logr.Logger) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -173,6 +473,8 @@ func correlationIDMiddleware(logger logr.Logger) mux.MiddlewareFunc {
 				correlationID = uuid.New().String()
 			}
 
+			rw.Header().Set("X-Correlation-Id", correlationID)
+
 			ctx := context.WithValue(req.Context(), correlationIDCtxKey{}, correlationID)
 			ctx = setLoggerInContext(ctx, logger.WithValues("correlationID", correlationID))
 
@@ -197,7 +499,7 @@ func getLoggerFromContext(ctx context.Context) logr.Logger {
 
 // httpClientWithCorrelationID is an http client that adds an "X-Correlation-Id"
 // header to requests as they are sent. The value of this header is pulled out of
-// the request context.
+// the request context, along with the trace context (sent as a W3C "traceparent" header).
 type httpClientWithCorrelationID struct {
 	innerClient http.Client
 }
@@ -206,19 +508,21 @@ type httpClientWithCorrelationID struct {
 // to the request.
 func (c *httpClientWithCorrelationID) Do(req *http.Request) (*http.Response, error) {
 	if _, ok := req.Header["X-Correlation-Id"]; !ok {
-		requestID := req.Context().Value(correlationIDCtxKey{}).(string) // Should never panic
+		// Background requests (e.g. keeping hot cities warm) have no correlation ID.
+		requestID, _ := req.Context().Value(correlationIDCtxKey{}).(string)
 
 		if requestID != "" {
 			req.Header.Set("X-Correlation-Id", requestID)
 		}
 	}
 
+	oteltracing.InjectHeaders(req.Context(), req.Header)
+
 	return c.innerClient.Do(req)
 }
 
-// instrument configures an otel metric controller.
-func instrument(serviceName, serviceVersion, deploymentEnvironment string) (*controller.Controller, error) {
-	// Resource
+// newResource creates the otel resource metrics & traces are attributed to.
+func newResource(serviceName, serviceVersion, deploymentEnvironment string) (*resource.Resource, error) {
 	res, err := resource.Merge(
 		resource.Default(),
 		resource.NewSchemaless([]attribute.KeyValue{
@@ -230,6 +534,42 @@ func instrument(serviceName, serviceVersion, deploymentEnvironment string) (*con
 		return nil, fmt.Errorf("create otel resource: %w", err)
 	}
 
+	return res, nil
+}
+
+// newTracerProvider creates an otel tracer provider, exporting spans as configured. Even
+// without an exporter, trace context is still propagated to providers.
+func newTracerProvider(ctx context.Context, serviceName, serviceVersion, deploymentEnvironment string, config *appConfig) (*sdktrace.TracerProvider, error) {
+	res, err := newResource(serviceName, serviceVersion, deploymentEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch config.TraceExporter {
+	case "stdout":
+		options = append(options, sdktrace.WithBatcher(oteltracing.NewStdoutExporter(os.Stdout)))
+	case "otlp":
+		exporter, err := oteltracing.NewOTLPHTTPExporter(ctx, config.OTLPEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// instrument configures an otel metric controller.
+func instrument(serviceName, serviceVersion, deploymentEnvironment string) (*controller.Controller, error) {
+	// Resource
+	res, err := newResource(serviceName, serviceVersion, deploymentEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Controller (metric provider)
 	metricController := controller.New(
 		processor.NewFactory(
@@ -13,6 +13,7 @@ import (
 	"github.com/go-logr/logr"
 
 	"github.com/byatesrae/weather/internal/httphandlermap"
+	"github.com/byatesrae/weather/internal/providerquery"
 )
 
 // serverURL is the URL of the server started with go main().
@@ -25,6 +26,20 @@ var (
 
 	// weatherstackStubServerHandler is a test double shared by tests.
 	weatherstackStubServerHandler httphandlermap.Map
+
+	// openMeteoStubServerHandler is a test double shared by tests, serving both the Open-Meteo
+	// API & its geocoding API.
+	openMeteoStubServerHandler httphandlermap.Map
+
+	// nwsStubServerHandler is a test double shared by tests.
+	nwsStubServerHandler httphandlermap.Map
+
+	// metNoStubServerHandler is a test double shared by tests.
+	metNoStubServerHandler httphandlermap.Map
+
+	// jsonProviderStubServerHandler is a test double shared by tests, serving the provider
+	// defined by writeJSONProvidersFile.
+	jsonProviderStubServerHandler httphandlermap.Map
 )
 
 func TestMain(m *testing.M) {
@@ -34,8 +49,25 @@ func TestMain(m *testing.M) {
 
 	openweatherStubServerHandler := startOpenweatherStubServer(logger)
 	weatherstackStubServerHandler := startWeatherstackStubServer(logger)
+	openMeteoStubServerHandler := startOpenMeteoStubServer(logger)
+	nwsStubServerHandler := startNWSStubServer(logger)
+	metNoStubServerHandler := startMetNoStubServer(logger)
+	jsonProviderStubServerHandler := startJSONProviderStubServer(logger)
+
+	jsonProvidersPath, err := writeJSONProvidersFile(jsonProviderStubServerHandler.URL)
+	if err != nil {
+		logger.Error(err, "Failed to write json providers file, exiting.")
+		os.Exit(1)
+	}
 
-	config, err := newTestConfig(openweatherStubServerHandler.URL, weatherstackStubServerHandler.URL)
+	config, err := newTestConfig(
+		openweatherStubServerHandler.URL,
+		weatherstackStubServerHandler.URL,
+		openMeteoStubServerHandler.URL,
+		nwsStubServerHandler.URL,
+		metNoStubServerHandler.URL,
+		jsonProvidersPath,
+	)
 	if err != nil {
 		logger.Error(err, "Failed to create test config, exiting.")
 		os.Exit(1)
@@ -55,6 +87,10 @@ func TestMain(m *testing.M) {
 	os.Args = originalArgs
 	m.Run()
 
+	jsonProviderStubServerHandler.Close()
+	metNoStubServerHandler.Close()
+	nwsStubServerHandler.Close()
+	openMeteoStubServerHandler.Close()
 	weatherstackStubServerHandler.Close()
 	openweatherStubServerHandler.Close()
 
@@ -63,11 +99,15 @@ func TestMain(m *testing.M) {
 		logger.Error(err, "Failed to stop main, exiting.")
 		os.Exit(1)
 	}
+
+	if err := os.Remove(jsonProvidersPath); err != nil {
+		logger.Error(err, "Failed to remove json providers file.")
+	}
 }
 
 // newTestConfig creates config that can be used in boostraping the server such that
 // it can be tested.
-func newTestConfig(openweatherURL, weatherstackURL string) (*appConfig, error) {
+func newTestConfig(openweatherURL, weatherstackURL, openMeteoURL, nwsURL, metNoURL, jsonProvidersPath string) (*appConfig, error) {
 	serverPort, err := getOpenPort()
 	if err != nil {
 		return nil, fmt.Errorf("get open port for server: %w", err)
@@ -79,7 +119,52 @@ func newTestConfig(openweatherURL, weatherstackURL string) (*appConfig, error) {
 		OpenweatherAPIKey:       "SET_BY_TESTMAIN",
 		WeatherstackEndpointURL: weatherstackURL,
 		WeatherstackAccessKey:   "SET_BY_TESTMAIN",
+		ResultTimeout:           time.Second * 10,
+		ProviderTimeout:         time.Second * 3,
+		Cache:                   "memory",
 		ResultCacheTTL:          time.Millisecond * 500,
+		ForecastCacheTTL:        time.Millisecond * 500,
+		MaxStaleness:            time.Minute * 15,
+		HotCityRefreshInterval:  time.Second,
+		PeerSRVInterval:         time.Second * 30,
+		CoordinatePrecision:     2,
+		ProviderOrdering:        "static",
+		ProviderErrorPenalty:    time.Second * 3,
+		ProviderQueryMode:       "sequential",
+		ProviderHedgeDelay:      time.Millisecond * 500,
+		AggregationMethod:       "none",
+		OutlierTolerances: providerquery.OutlierTolerances{
+			Temperature: 5,
+			WindSpeed:   15,
+			Humidity:    20,
+			Pressure:    10,
+		},
+		BreakerFailureThreshold: 20, // Tests share breakers, many failing a provider to reach the next.
+		BreakerCoolDown:         time.Second * 30,
+		BreakerHalfOpenProbes:   1,
+
+		MemoryCacheMaxEntries:      10000,
+		MemoryCacheExpiryGrace:     time.Hour,
+		MemoryCacheJanitorInterval: time.Minute,
+
+		SnapshotInterval: time.Minute,
+
+		TraceExporter: "none",
+		OTLPEndpoint:  "http://localhost:4318",
+
+		ReadyzMinAvailableProviders: 1,
+
+		OpenMeteoEndpointURL:          openMeteoURL,
+		OpenMeteoGeocodingEndpointURL: openMeteoURL,
+
+		NWSEndpointURL:    nwsURL,
+		NWSUserAgent:      "weatherapi-component-test",
+		NWSPointsCacheTTL: time.Hour,
+
+		MetNoEndpointURL: metNoURL,
+		MetNoUserAgent:   "weatherapi-component-test",
+
+		JSONProvidersPath: jsonProvidersPath,
 	}, nil
 }
 
@@ -92,8 +177,56 @@ func getMainArguments(config *appConfig) []string {
 		fmt.Sprintf("-openweather-api-key=%s", config.OpenweatherAPIKey),
 		fmt.Sprintf("-weatherstack-endpoint-url=%s", config.WeatherstackEndpointURL),
 		fmt.Sprintf("-weatherstack-access-key=%s", config.WeatherstackAccessKey),
+		fmt.Sprintf("-result-timeout=%s", config.ResultTimeout),
+		fmt.Sprintf("-provider-timeout=%s", config.ProviderTimeout),
+		fmt.Sprintf("-cache=%s", config.Cache),
+		fmt.Sprintf("-memory-cache-max-entries=%v", config.MemoryCacheMaxEntries),
+		fmt.Sprintf("-memory-cache-expiry-grace=%s", config.MemoryCacheExpiryGrace),
+		fmt.Sprintf("-memory-cache-janitor-interval=%s", config.MemoryCacheJanitorInterval),
+		fmt.Sprintf("-snapshot-path=%s", config.SnapshotPath),
+		fmt.Sprintf("-snapshot-interval=%s", config.SnapshotInterval),
+		fmt.Sprintf("-redis-address=%s", config.RedisAddress),
+		fmt.Sprintf("-redis-password=%s", config.RedisPassword),
+		fmt.Sprintf("-peer-self=%s", config.PeerSelf),
+		fmt.Sprintf("-peers=%s", config.Peers),
+		fmt.Sprintf("-peer-srv=%s", config.PeerSRV),
+		fmt.Sprintf("-peer-srv-interval=%s", config.PeerSRVInterval),
+		fmt.Sprintf("-peer-secret=%s", config.PeerSecret),
 		fmt.Sprintf("-result-cache-ttl=%s", config.ResultCacheTTL),
+		fmt.Sprintf("-forecast-cache-ttl=%s", config.ForecastCacheTTL),
+		fmt.Sprintf("-max-staleness=%s", config.MaxStaleness),
+		fmt.Sprintf("-refresh-ahead-window=%s", config.RefreshAheadWindow),
+		fmt.Sprintf("-hot-cities=%s", config.HotCities),
+		fmt.Sprintf("-hot-city-refresh-interval=%s", config.HotCityRefreshInterval),
+		fmt.Sprintf("-coordinate-precision=%v", config.CoordinatePrecision),
+		fmt.Sprintf("-provider-ordering=%s", config.ProviderOrdering),
+		fmt.Sprintf("-provider-error-penalty=%s", config.ProviderErrorPenalty),
+		fmt.Sprintf("-provider-query-mode=%s", config.ProviderQueryMode),
+		fmt.Sprintf("-provider-hedge-delay=%s", config.ProviderHedgeDelay),
+		fmt.Sprintf("-aggregation-method=%s", config.AggregationMethod),
+		fmt.Sprintf("-provider-weights=%s", config.ProviderWeights),
+		fmt.Sprintf("-outlier-tolerance-temperature=%v", config.OutlierTolerances.Temperature),
+		fmt.Sprintf("-outlier-tolerance-wind-speed=%v", config.OutlierTolerances.WindSpeed),
+		fmt.Sprintf("-outlier-tolerance-humidity=%v", config.OutlierTolerances.Humidity),
+		fmt.Sprintf("-outlier-tolerance-pressure=%v", config.OutlierTolerances.Pressure),
+		fmt.Sprintf("-breaker-failure-threshold=%v", config.BreakerFailureThreshold),
+		fmt.Sprintf("-breaker-cool-down=%s", config.BreakerCoolDown),
+		fmt.Sprintf("-breaker-half-open-probes=%v", config.BreakerHalfOpenProbes),
 		fmt.Sprintf("-colourized-output=%v", config.ColourizedOutput),
+		fmt.Sprintf("-trace-exporter=%s", config.TraceExporter),
+		fmt.Sprintf("-otlp-endpoint=%s", config.OTLPEndpoint),
+		fmt.Sprintf("-readyz-min-available-providers=%v", config.ReadyzMinAvailableProviders),
+		fmt.Sprintf("-readyz-max-result-age=%s", config.ReadyzMaxResultAge),
+		fmt.Sprintf("-readyz-cache-required=%v", config.ReadyzCacheRequired),
+		fmt.Sprintf("-shutdown-drain-delay=%s", config.ShutdownDrainDelay),
+		fmt.Sprintf("-openmeteo-endpoint-url=%s", config.OpenMeteoEndpointURL),
+		fmt.Sprintf("-openmeteo-geocoding-endpoint-url=%s", config.OpenMeteoGeocodingEndpointURL),
+		fmt.Sprintf("-nws-endpoint-url=%s", config.NWSEndpointURL),
+		fmt.Sprintf("-nws-user-agent=%s", config.NWSUserAgent),
+		fmt.Sprintf("-nws-points-cache-ttl=%s", config.NWSPointsCacheTTL),
+		fmt.Sprintf("-metno-endpoint-url=%s", config.MetNoEndpointURL),
+		fmt.Sprintf("-metno-user-agent=%s", config.MetNoUserAgent),
+		fmt.Sprintf("-json-providers=%s", config.JSONProvidersPath),
 	}
 }
 
@@ -152,6 +285,101 @@ func startOpenweatherStubServer(logger logr.Logger) *httptest.Server {
 	return s
 }
 
+// startOpenMeteoStubServer starts an httptest.Server using the correlation ID header as a
+// request discriminator. Open-Meteo is the last resort provider, so requests without a
+// registered handler fail (as if Open-Meteo were down), leaving tests of the other providers
+// unaffected.
+func startOpenMeteoStubServer(logger logr.Logger) *httptest.Server {
+	openMeteoStubServerHandler.KeyGenFunc = getCorrelationIDOrNil
+	openMeteoStubServerHandler.DefaultHandler = func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	s := httptest.NewServer(&openMeteoStubServerHandler)
+
+	logger.V(0).Info("Open-Meteo stub server started.", "addr", s.URL)
+
+	return s
+}
+
+// startNWSStubServer starts an httptest.Server using the correlation ID header as a request
+// discriminator. Requests without a registered handler are answered as if the location were
+// outside the US, leaving tests of the other providers unaffected.
+func startNWSStubServer(logger logr.Logger) *httptest.Server {
+	nwsStubServerHandler.KeyGenFunc = getCorrelationIDOrNil
+	nwsStubServerHandler.DefaultHandler = func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/problem+json")
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte(`{"type":"https://api.weather.gov/problems/InvalidPoint","title":"Data Unavailable For Requested Point","status":404}`))
+	}
+
+	s := httptest.NewServer(&nwsStubServerHandler)
+
+	logger.V(0).Info("NWS stub server started.", "addr", s.URL)
+
+	return s
+}
+
+// startMetNoStubServer starts an httptest.Server using the correlation ID header as a request
+// discriminator. Requests without a registered handler fail (as if Met Norway were down).
+func startMetNoStubServer(logger logr.Logger) *httptest.Server {
+	metNoStubServerHandler.KeyGenFunc = getCorrelationIDOrNil
+	metNoStubServerHandler.DefaultHandler = func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	s := httptest.NewServer(&metNoStubServerHandler)
+
+	logger.V(0).Info("Met Norway stub server started.", "addr", s.URL)
+
+	return s
+}
+
+// startJSONProviderStubServer starts an httptest.Server using the correlation ID header as a
+// request discriminator. Requests without a registered handler fail (as if the provider were
+// down).
+func startJSONProviderStubServer(logger logr.Logger) *httptest.Server {
+	jsonProviderStubServerHandler.KeyGenFunc = getCorrelationIDOrNil
+	jsonProviderStubServerHandler.DefaultHandler = func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	s := httptest.NewServer(&jsonProviderStubServerHandler)
+
+	logger.V(0).Info("JSON provider stub server started.", "addr", s.URL)
+
+	return s
+}
+
+// writeJSONProvidersFile writes a temporary file defining a provider served at serverURL,
+// returning its path.
+func writeJSONProvidersFile(serverURL string) (string, error) {
+	f, err := os.CreateTemp("", "weatherapi-json-providers-*.json")
+	if err != nil {
+		return "", fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, `[{
+		"name": "Example JSON",
+		"url": "%s/current?q={city}",
+		"key": "SET_BY_TESTMAIN",
+		"auth": {"in": "header", "name": "X-Api-Key"},
+		"fields": {
+			"temperature_degrees": {"path": "$.current.temp_f", "unit": "fahrenheit"},
+			"wind_speed": {"path": "$.current.wind", "unit": "m/s"},
+			"condition": {"path": "$.current.weather[0]", "map": {"Sunny": "clear"}},
+			"observed_at": {"path": "$.current.time", "unit": "rfc3339"}
+		},
+		"error": {"path": "$.error", "code_path": "$.error.code", "location_not_found_codes": ["404"]}
+	}]`, serverURL)
+	if err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
 func getCorrelationIDOrNil(r *http.Request) any {
 	correlationID := r.Header.Get("X-Correlation-Id")
 
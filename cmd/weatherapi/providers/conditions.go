@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"strings"
+
+	"github.com/byatesrae/weather"
+)
+
+// openWeatherCondition translates an Openweather weather condition ID to a [weather.ConditionCode].
+// See https://openweathermap.org/weather-conditions.
+func openWeatherCondition(id int) weather.ConditionCode {
+	switch {
+	case id >= 200 && id < 300:
+		return weather.ConditionThunderstorm
+	case id >= 300 && id < 400:
+		return weather.ConditionDrizzle
+	case id == 511:
+		return weather.ConditionSleet // freezing rain
+	case id >= 500 && id < 600:
+		return weather.ConditionRain
+	case id >= 611 && id <= 616:
+		return weather.ConditionSleet
+	case id >= 600 && id < 700:
+		return weather.ConditionSnow
+	case id == 701 || id == 741:
+		return weather.ConditionFog // mist, fog
+	case id >= 700 && id < 800:
+		return weather.ConditionHaze // smoke, haze, dust, sand, ash, squalls, tornado
+	case id == 800:
+		return weather.ConditionClear
+	case id == 801 || id == 802:
+		return weather.ConditionPartlyCloudy
+	case id == 803 || id == 804:
+		return weather.ConditionCloudy
+	default:
+		return weather.ConditionUnknown
+	}
+}
+
+// weatherStackConditions translates Weatherstack weather codes to [weather.ConditionCode]s.
+// See https://weatherstack.com/site_resources/weatherstack-weather-condition-codes.zip.
+var weatherStackConditions = map[int]weather.ConditionCode{
+	113: weather.ConditionClear,
+	116: weather.ConditionPartlyCloudy,
+	119: weather.ConditionCloudy,
+	122: weather.ConditionCloudy,
+	143: weather.ConditionFog,
+	248: weather.ConditionFog,
+	260: weather.ConditionFog,
+	185: weather.ConditionDrizzle,
+	263: weather.ConditionDrizzle,
+	266: weather.ConditionDrizzle,
+	281: weather.ConditionDrizzle,
+	284: weather.ConditionDrizzle,
+	176: weather.ConditionRain,
+	293: weather.ConditionRain,
+	296: weather.ConditionRain,
+	299: weather.ConditionRain,
+	302: weather.ConditionRain,
+	305: weather.ConditionRain,
+	308: weather.ConditionRain,
+	353: weather.ConditionRain,
+	356: weather.ConditionRain,
+	359: weather.ConditionRain,
+	182: weather.ConditionSleet,
+	311: weather.ConditionSleet,
+	314: weather.ConditionSleet,
+	317: weather.ConditionSleet,
+	320: weather.ConditionSleet,
+	350: weather.ConditionSleet,
+	362: weather.ConditionSleet,
+	365: weather.ConditionSleet,
+	374: weather.ConditionSleet,
+	377: weather.ConditionSleet,
+	179: weather.ConditionSnow,
+	227: weather.ConditionSnow,
+	230: weather.ConditionSnow,
+	323: weather.ConditionSnow,
+	326: weather.ConditionSnow,
+	329: weather.ConditionSnow,
+	332: weather.ConditionSnow,
+	335: weather.ConditionSnow,
+	338: weather.ConditionSnow,
+	368: weather.ConditionSnow,
+	371: weather.ConditionSnow,
+	200: weather.ConditionThunderstorm,
+	386: weather.ConditionThunderstorm,
+	389: weather.ConditionThunderstorm,
+	392: weather.ConditionThunderstorm,
+	395: weather.ConditionThunderstorm,
+}
+
+// weatherStackCondition translates a Weatherstack weather code to a [weather.ConditionCode].
+func weatherStackCondition(code int) weather.ConditionCode {
+	if c, ok := weatherStackConditions[code]; ok {
+		return c
+	}
+
+	return weather.ConditionUnknown
+}
+
+// openMeteoCondition translates a WMO weather interpretation code, as supplied by Open-Meteo, to
+// a [weather.ConditionCode]. See https://open-meteo.com/en/docs.
+func openMeteoCondition(code int) weather.ConditionCode {
+	switch code {
+	case 0:
+		return weather.ConditionClear
+	case 1, 2:
+		return weather.ConditionPartlyCloudy // mainly clear, partly cloudy
+	case 3:
+		return weather.ConditionCloudy
+	case 45, 48:
+		return weather.ConditionFog
+	case 51, 53, 55:
+		return weather.ConditionDrizzle
+	case 56, 57, 66, 67:
+		return weather.ConditionSleet // freezing drizzle, freezing rain
+	case 61, 63, 65, 80, 81, 82:
+		return weather.ConditionRain // rain, rain showers
+	case 71, 73, 75, 77, 85, 86:
+		return weather.ConditionSnow // snow, snow grains, snow showers
+	case 95, 96, 99:
+		return weather.ConditionThunderstorm
+	default:
+		return weather.ConditionUnknown
+	}
+}
+
+// nwsConditionKeywords are keywords of NWS observation text descriptions (e.g "Light Rain and
+// Fog/Mist") & the [weather.ConditionCode] they translate to, most significant first.
+var nwsConditionKeywords = []struct {
+	keyword   string
+	condition weather.ConditionCode
+}{
+	{"thunderstorm", weather.ConditionThunderstorm},
+	{"freezing", weather.ConditionSleet}, // freezing rain, freezing drizzle
+	{"ice pellets", weather.ConditionSleet},
+	{"sleet", weather.ConditionSleet},
+	{"hail", weather.ConditionSleet},
+	{"snow", weather.ConditionSnow},
+	{"drizzle", weather.ConditionDrizzle},
+	{"rain", weather.ConditionRain},
+	{"showers", weather.ConditionRain},
+	{"fog", weather.ConditionFog},
+	{"mist", weather.ConditionFog},
+	{"haze", weather.ConditionHaze},
+	{"smoke", weather.ConditionHaze},
+	{"dust", weather.ConditionHaze},
+	{"sand", weather.ConditionHaze},
+	{"ash", weather.ConditionHaze},
+	{"partly", weather.ConditionPartlyCloudy}, // partly cloudy, partly sunny
+	{"mostly sunny", weather.ConditionPartlyCloudy},
+	{"mostly clear", weather.ConditionPartlyCloudy},
+	{"few clouds", weather.ConditionPartlyCloudy},
+	{"cloudy", weather.ConditionCloudy}, // cloudy, mostly cloudy
+	{"overcast", weather.ConditionCloudy},
+	{"clear", weather.ConditionClear},
+	{"sunny", weather.ConditionClear},
+	{"fair", weather.ConditionClear},
+}
+
+// nwsCondition translates the text description of an NWS observation to a
+// [weather.ConditionCode]. See https://api.weather.gov/icons for the conditions described.
+func nwsCondition(textDescription string) weather.ConditionCode {
+	textDescription = strings.ToLower(textDescription)
+
+	for _, k := range nwsConditionKeywords {
+		if strings.Contains(textDescription, k.keyword) {
+			return k.condition
+		}
+	}
+
+	return weather.ConditionUnknown
+}
+
+// metNoCondition translates a Met Norway weather symbol code (e.g "lightrainshowers_day") to a
+// [weather.ConditionCode]. See https://github.com/metno/weathericons.
+func metNoCondition(symbolCode string) weather.ConditionCode {
+	// The variant of the symbol, for the time of day, is irrelevant.
+	if i := strings.IndexByte(symbolCode, '_'); i >= 0 {
+		symbolCode = symbolCode[:i]
+	}
+
+	switch {
+	case symbolCode == "clearsky":
+		return weather.ConditionClear
+	case symbolCode == "fair" || symbolCode == "partlycloudy":
+		return weather.ConditionPartlyCloudy
+	case symbolCode == "cloudy":
+		return weather.ConditionCloudy
+	case symbolCode == "fog":
+		return weather.ConditionFog
+	case strings.Contains(symbolCode, "thunder"):
+		return weather.ConditionThunderstorm
+	case strings.Contains(symbolCode, "sleet"):
+		return weather.ConditionSleet
+	case strings.Contains(symbolCode, "snow"):
+		return weather.ConditionSnow
+	case strings.Contains(symbolCode, "rain"):
+		return weather.ConditionRain
+	default:
+		return weather.ConditionUnknown
+	}
+}
@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"time"
+
+	"github.com/byatesrae/weather"
+)
+
+// dailyFromHourly aggregates hourly forecast entries into daily forecast entries, where days
+// start at midnight in location loc. The daily temperature is the mean of the hourly temperatures
+// and the daily windspeed is the maximum of the hourly windspeeds.
+//
+// hourly must be ordered by start time. At most days daily entries are returned.
+func dailyFromHourly(hourly []weather.ForecastEntry, loc *time.Location, days int) []weather.ForecastEntry {
+	daily := []weather.ForecastEntry{}
+
+	var temperatureSum float64
+	var count int
+
+	for _, h := range hourly {
+		start := h.Start.In(loc)
+		dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+
+		if len(daily) == 0 || !daily[len(daily)-1].Start.Equal(dayStart) {
+			if len(daily) == days {
+				break
+			}
+
+			daily = append(daily, weather.ForecastEntry{
+				Start:          dayStart,
+				End:            dayStart.AddDate(0, 0, 1),
+				TemperatureMin: floatPtr(h.Temperature),
+				TemperatureMax: floatPtr(h.Temperature),
+			})
+
+			temperatureSum, count = 0, 0
+		}
+
+		day := &daily[len(daily)-1]
+
+		temperatureSum += h.Temperature
+		count++
+
+		day.Temperature = temperatureSum / float64(count)
+		*day.TemperatureMin = minFloat(*day.TemperatureMin, h.Temperature)
+		*day.TemperatureMax = maxFloat(*day.TemperatureMax, h.Temperature)
+		day.WindSpeed = maxFloat(day.WindSpeed, h.WindSpeed)
+	}
+
+	return daily
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+// sumFloatPtrs sums the non-nil values. If all values are nil then nil is returned.
+func sumFloatPtrs(values ...*float64) *float64 {
+	var sum *float64
+
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+
+		if sum == nil {
+			sum = floatPtr(0)
+		}
+
+		*sum += *v
+	}
+
+	return sum
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
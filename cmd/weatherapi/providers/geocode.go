@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// geocode resolves a city name to coordinates with geocoder, for providers that can only be
+// queried by coordinates. If geocoder is nil, city names are unsupported.
+func geocode(ctx context.Context, geocoder *openmeteo.Client, cityName string) (*providerquery.Coordinates, error) {
+	if geocoder == nil {
+		return nil, fmt.Errorf("%w: city names unsupported without a geocoder", providerquery.ErrLocationUnsupported)
+	}
+
+	location, err := geocoder.LocationByCityName(ctx, cityName)
+	if err != nil {
+		// Another provider may still know the city.
+		if errors.Is(err, openmeteo.ErrCityNotFound) {
+			err = fmt.Errorf("%w: %w", providerquery.ErrLocationUnsupported, err)
+		}
+
+		return nil, fmt.Errorf("geocode city name: %w", err)
+	}
+
+	return &providerquery.Coordinates{Latitude: location.Latitude, Longitude: location.Longitude}, nil
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// assertSummaryInDelta asserts that actual equals expected, with its numbers (being converted
+// between units) compared within a small delta.
+func assertSummaryInDelta(t *testing.T, expected, actual *weather.Summary) {
+	t.Helper()
+
+	if !assert.NotNil(t, actual) {
+		return
+	}
+
+	assert.InDelta(t, expected.Temperature, actual.Temperature, 1e-9, "Temperature")
+	assert.InDelta(t, expected.WindSpeed, actual.WindSpeed, 1e-9, "WindSpeed")
+
+	for _, f := range []struct {
+		name             string
+		expected, actual *float64
+	}{
+		{"FeelsLike", expected.FeelsLike, actual.FeelsLike},
+		{"Humidity", expected.Humidity, actual.Humidity},
+		{"Pressure", expected.Pressure, actual.Pressure},
+		{"CloudCover", expected.CloudCover, actual.CloudCover},
+		{"Visibility", expected.Visibility, actual.Visibility},
+		{"WindDirection", expected.WindDirection, actual.WindDirection},
+		{"WindGust", expected.WindGust, actual.WindGust},
+		{"Precipitation", expected.Precipitation, actual.Precipitation},
+	} {
+		if f.expected == nil || f.actual == nil {
+			assert.Equal(t, f.expected, f.actual, f.name)
+		} else {
+			assert.InDelta(t, *f.expected, *f.actual, 1e-9, f.name)
+		}
+	}
+
+	assert.Equal(t, expected.WindDirectionCompass, actual.WindDirectionCompass, "WindDirectionCompass")
+	assert.Equal(t, expected.Condition, actual.Condition, "Condition")
+	assert.Equal(t, expected.ObservedAt, actual.ObservedAt, "ObservedAt")
+}
+
+// assertErrorClass asserts that err is of the class of [providerquery] error expected, or of
+// none if expected is nil.
+func assertErrorClass(t *testing.T, expected, err error) {
+	t.Helper()
+
+	for _, class := range []error{
+		providerquery.ErrInvalidInput,
+		providerquery.ErrLocationNotFound,
+		providerquery.ErrLocationUnsupported,
+		providerquery.ErrProviderRejected,
+	} {
+		assert.Equal(t, class == expected, errors.Is(err, class), "is %v", class)
+	}
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/jsonprovider"
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// JSONProvider wraps a [jsonprovider.Client] to satisfy the [providerquery.Provider] interface,
+// being a provider defined by configuration rather than code.
+type JSONProvider struct {
+	client   *jsonprovider.Client
+	geocoder *openmeteo.Client
+}
+
+var _ providerquery.Provider = (*JSONProvider)(nil)
+
+// NewJSONProvider creates a new [JSONProvider]. If the provider can only be queried by
+// coordinates, city names are resolved to coordinates with geocoder (if not nil).
+func NewJSONProvider(c *jsonprovider.Client, geocoder *openmeteo.Client) *JSONProvider {
+	return &JSONProvider{client: c, geocoder: geocoder}
+}
+
+// ProviderName is the unique name for this provider, as configured.
+func (p *JSONProvider) ProviderName() string {
+	return p.client.Name()
+}
+
+// GetWeatherSummary gets a [weather.Summary] for a city.
+func (p *JSONProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
+	summary, err := p.client.CurrentByCityName(ctx, cityName)
+	if errors.Is(err, jsonprovider.ErrUnsupported) {
+		coordinates, err := geocode(ctx, p.geocoder, cityName)
+		if err != nil {
+			return nil, err
+		}
+
+		return p.GetWeatherSummaryByCoordinates(ctx, *coordinates)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("current by city name: %w", classifyJSONProviderError(err))
+	}
+
+	return summary, nil
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *JSONProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	summary, err := p.client.CurrentByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("current by coordinates: %w", classifyJSONProviderError(err))
+	}
+
+	return summary, nil
+}
+
+// classifyJSONProviderError wraps err in the class of [providerquery] error it belongs to (if
+// any), as classified by the error rule of the provider's definition.
+func classifyJSONProviderError(err error) error {
+	switch {
+	case errors.Is(err, jsonprovider.ErrLocationNotFound):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationNotFound, err)
+	case errors.Is(err, jsonprovider.ErrRejected):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	case errors.Is(err, jsonprovider.ErrUnsupported):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationUnsupported, err)
+	default:
+		return err
+	}
+}
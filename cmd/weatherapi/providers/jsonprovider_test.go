@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather/internal/jsonprovider"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestJSONProviderGetWeatherSummaryUnsupported(t *testing.T) {
+	t.Parallel()
+
+	// Only queried by coordinates, without a geocoder to resolve city names.
+	definitions, err := jsonprovider.Load(strings.NewReader(`[{
+		"name": "Example",
+		"coordinates_url": "https://api.example.com/current?lat={lat}&lon={lon}",
+		"fields": {
+			"temperature_degrees": {"path": "$.temp"},
+			"wind_speed": {"path": "$.wind"}
+		}
+	}]`))
+	require.NoError(t, err)
+
+	provider := NewJSONProvider(jsonprovider.New(definitions[0]), nil)
+
+	actual, err := provider.GetWeatherSummary(context.Background(), "Sydney")
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "providerquery: location unsupported: city names unsupported without a geocoder")
+	assertErrorClass(t, providerquery.ErrLocationUnsupported, err)
+}
+
+func TestClassifyJSONProviderError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        error
+		expectedIs  error // The providerquery error class, nil if none.
+		expectedErr string
+	}{
+		{
+			// Not found (a 404) rather than unsupported, as the definition's error rule says so.
+			name:        "location_not_found",
+			give:        fmt.Errorf("%w: Test123", jsonprovider.ErrLocationNotFound),
+			expectedIs:  providerquery.ErrLocationNotFound,
+			expectedErr: "providerquery: location not found: jsonprovider: location not found: Test123",
+		},
+		{
+			name:        "rejected",
+			give:        jsonprovider.ErrRejected,
+			expectedIs:  providerquery.ErrProviderRejected,
+			expectedErr: "providerquery: provider rejected query: jsonprovider: rejected",
+		},
+		{
+			name:        "unsupported",
+			give:        jsonprovider.ErrUnsupported,
+			expectedIs:  providerquery.ErrLocationUnsupported,
+			expectedErr: "providerquery: location unsupported: jsonprovider: query unsupported",
+		},
+		{
+			name:        "other",
+			give:        errors.New("Test123"),
+			expectedErr: "Test123",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := classifyJSONProviderError(tc.give)
+
+			assert.EqualError(t, actual, tc.expectedErr)
+			assert.ErrorIs(t, actual, tc.give, "the original error is kept")
+			assertErrorClass(t, tc.expectedIs, actual)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/metno"
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// MetNoProvider wraps a [metno.Client] to satisfy the [providerquery.Provider] interface.
+type MetNoProvider struct {
+	client   *metno.Client
+	geocoder *openmeteo.Client
+}
+
+var _ providerquery.Provider = (*MetNoProvider)(nil)
+
+// NewMetNoProvider creates a new [MetNoProvider]. Met Norway has no way to look up a city, so
+// city names are resolved to coordinates with geocoder. If geocoder is nil, only coordinates are
+// supported.
+func NewMetNoProvider(c *metno.Client, geocoder *openmeteo.Client) *MetNoProvider {
+	return &MetNoProvider{client: c, geocoder: geocoder}
+}
+
+// ProviderName is the unique name for this provider.
+func (p *MetNoProvider) ProviderName() string {
+	return "Met Norway"
+}
+
+// GetWeatherSummary gets a [weather.Summary] for a city.
+func (p *MetNoProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
+	coordinates, err := geocode(ctx, p.geocoder, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetWeatherSummaryByCoordinates(ctx, *coordinates)
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *MetNoProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	res, err := p.client.CurrentByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("current by coordinates: %w", classifyMetNoError(err))
+	}
+
+	return metNoToSummary(res), nil
+}
+
+// metNoToSummary translates a Met Norway nowcast to a [weather.Summary]. Met Norway forecasts
+// don't supply the apparent temperature or visibility.
+func metNoToSummary(nowcast *metno.TimeStep) *weather.Summary {
+	details := nowcast.Data.Instant.Details
+
+	summary := weather.Summary{
+		Temperature: *details.AirTemperature, // Checked by the client.
+		WindSpeed:   metresPerSecondToKMPerHour(*details.WindSpeed),
+		Humidity:    details.RelativeHumidity,
+		Pressure:    details.AirPressureAtSeaLevel,
+		CloudCover:  details.CloudAreaFraction,
+	}
+
+	if details.WindSpeedOfGust != nil {
+		summary.WindGust = floatPtr(metresPerSecondToKMPerHour(*details.WindSpeedOfGust))
+	}
+
+	if details.WindFromDirection != nil {
+		summary.SetWindDirection(*details.WindFromDirection)
+	}
+
+	if next := nowcast.Data.Next1Hours; next != nil {
+		summary.Precipitation = next.Details.PrecipitationAmount
+
+		if next.Summary.SymbolCode != "" {
+			summary.Condition = weather.NewCondition(metNoCondition(next.Summary.SymbolCode))
+		}
+	}
+
+	if !nowcast.Time.IsZero() {
+		observedAt := nowcast.Time.UTC()
+		summary.ObservedAt = &observedAt
+	}
+
+	return &summary
+}
+
+// classifyMetNoError wraps err in the class of [providerquery] error it belongs to (if any), such
+// that the queryer can tell an invalid query from Met Norway rejecting queries.
+func classifyMetNoError(err error) error {
+	switch {
+	case errors.Is(err, metno.ErrInvalidQuery):
+		return fmt.Errorf("%w: %w", providerquery.ErrInvalidInput, err)
+	case errors.Is(err, metno.ErrRateLimited), errors.Is(err, metno.ErrForbidden):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/metno"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestMetNoToSummary(t *testing.T) {
+	t.Parallel()
+
+	observedAt := time.Date(2020, time.November, 11, 10, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		give     string // A time step of the "Locationforecast" endpoint.
+		expected *weather.Summary
+	}{
+		{
+			name: "complete",
+			give: `{
+				"time": "2020-11-11T10:00:00Z",
+				"data": {
+					"instant": {
+						"details": {
+							"air_temperature": 12.5,
+							"wind_speed": 10,
+							"wind_speed_of_gust": 15,
+							"wind_from_direction": 225,
+							"relative_humidity": 75,
+							"air_pressure_at_sea_level": 1013.2,
+							"cloud_area_fraction": 40
+						}
+					},
+					"next_1_hours": {
+						"summary": {"symbol_code": "lightrainshowers_day"},
+						"details": {"precipitation_amount": 0.4}
+					}
+				}
+			}`,
+			expected: &weather.Summary{
+				Temperature:          12.5,
+				WindSpeed:            36,
+				WindGust:             floatPtr(54),
+				WindDirection:        floatPtr(225),
+				WindDirectionCompass: "SW",
+				Humidity:             floatPtr(75),
+				Pressure:             floatPtr(1013.2),
+				CloudCover:           floatPtr(40),
+				Precipitation:        floatPtr(0.4),
+				Condition:            weather.NewCondition(weather.ConditionRain),
+				ObservedAt:           &observedAt,
+			},
+		},
+		{
+			name: "compact",
+			give: `{
+				"time": "2020-11-11T10:00:00Z",
+				"data": {"instant": {"details": {"air_temperature": -2, "wind_speed": 2.5}}}
+			}`,
+			expected: &weather.Summary{Temperature: -2, WindSpeed: 9, ObservedAt: &observedAt},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var give metno.TimeStep
+			require.NoError(t, json.Unmarshal([]byte(tc.give), &give))
+
+			assertSummaryInDelta(t, tc.expected, metNoToSummary(&give))
+		})
+	}
+}
+
+func TestClassifyMetNoError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        error
+		expectedIs  error // The providerquery error class, nil if none.
+		expectedErr string
+	}{
+		{
+			name:        "invalid_query",
+			give:        fmt.Errorf("%w: Test123", metno.ErrInvalidQuery),
+			expectedIs:  providerquery.ErrInvalidInput,
+			expectedErr: "providerquery: invalid input: metno: invalid query: Test123",
+		},
+		{
+			name:        "rate_limited",
+			give:        metno.ErrRateLimited,
+			expectedIs:  providerquery.ErrProviderRejected,
+			expectedErr: "providerquery: provider rejected query: metno: rate limited",
+		},
+		{
+			name:        "forbidden",
+			give:        metno.ErrForbidden,
+			expectedIs:  providerquery.ErrProviderRejected,
+			expectedErr: "providerquery: provider rejected query: metno: forbidden",
+		},
+		{
+			name:        "other",
+			give:        errors.New("Test123"),
+			expectedErr: "Test123",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := classifyMetNoError(tc.give)
+
+			assert.EqualError(t, actual, tc.expectedErr)
+			assert.ErrorIs(t, actual, tc.give, "the original error is kept")
+			assertErrorClass(t, tc.expectedIs, actual)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/nws"
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// NWSProvider wraps an [nws.Client] to satisfy the [providerquery.Provider] interface. The NWS
+// only covers the US, answering queries for other locations with an
+// [providerquery.ErrLocationUnsupported] such that the next provider is queried.
+type NWSProvider struct {
+	client   *nws.Client
+	geocoder *openmeteo.Client
+}
+
+var _ providerquery.Provider = (*NWSProvider)(nil)
+
+// NewNWSProvider creates a new [NWSProvider]. The NWS has no way to look up a city, so city names
+// are resolved to coordinates with geocoder. If geocoder is nil, only coordinates are supported.
+func NewNWSProvider(c *nws.Client, geocoder *openmeteo.Client) *NWSProvider {
+	return &NWSProvider{client: c, geocoder: geocoder}
+}
+
+// ProviderName is the unique name for this provider.
+func (p *NWSProvider) ProviderName() string {
+	return "NWS"
+}
+
+// GetWeatherSummary gets a [weather.Summary] for a city.
+func (p *NWSProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
+	coordinates, err := geocode(ctx, p.geocoder, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetWeatherSummaryByCoordinates(ctx, *coordinates)
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *NWSProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	res, err := p.client.CurrentByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("current by coordinates: %w", classifyNWSError(err))
+	}
+
+	summary, err := nwsToSummary(res)
+	if err != nil {
+		return nil, fmt.Errorf("translate observation: %w", err)
+	}
+
+	return summary, nil
+}
+
+// nwsToSummary translates an NWS observation to a [weather.Summary], converting its values to
+// the units of the summary. The NWS does not supply the cloud cover as a percentage.
+func nwsToSummary(observation *nws.Observation) (*weather.Summary, error) {
+	var summary weather.Summary
+
+	// Each value & the unit of the summary it is converted to. A nil value is left unset.
+	values := []struct {
+		name  string
+		value nws.QuantitativeValue
+		unit  string
+		set   func(v *float64)
+	}{
+		{"temperature", observation.Temperature, nws.UnitCelsius, func(v *float64) { summary.Temperature = *v }},
+		{"windSpeed", observation.WindSpeed, nws.UnitKilometresPerHour, func(v *float64) { summary.WindSpeed = *v }},
+		{"windGust", observation.WindGust, nws.UnitKilometresPerHour, func(v *float64) { summary.WindGust = v }},
+		{"windDirection", observation.WindDirection, nws.UnitDegrees, func(v *float64) { summary.SetWindDirection(*v) }},
+		{"heatIndex", observation.HeatIndex, nws.UnitCelsius, func(v *float64) { summary.FeelsLike = v }},
+		{"windChill", observation.WindChill, nws.UnitCelsius, func(v *float64) { summary.FeelsLike = v }},
+		{"relativeHumidity", observation.RelativeHumidity, nws.UnitPercent, func(v *float64) { summary.Humidity = v }},
+		{"barometricPressure", observation.BarometricPressure, nws.UnitHectopascals, func(v *float64) { summary.Pressure = v }},
+		{"seaLevelPressure", observation.SeaLevelPressure, nws.UnitHectopascals, func(v *float64) { summary.Pressure = v }},
+		{"visibility", observation.Visibility, nws.UnitKilometres, func(v *float64) { summary.Visibility = v }},
+		{"precipitationLastHour", observation.PrecipitationLastHour, nws.UnitMillimetres, func(v *float64) { summary.Precipitation = v }},
+	}
+
+	// Where two values set the same field, the latter is preferred.
+	for _, v := range values {
+		converted, err := v.value.In(v.unit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.name, err)
+		}
+
+		if converted != nil {
+			v.set(converted)
+		}
+	}
+
+	if observation.TextDescription != "" {
+		summary.Condition = weather.NewCondition(nwsCondition(observation.TextDescription))
+	}
+
+	if !observation.Timestamp.IsZero() {
+		observedAt := observation.Timestamp.UTC()
+		summary.ObservedAt = &observedAt
+	}
+
+	return &summary, nil
+}
+
+// classifyNWSError wraps err in the class of [providerquery] error it belongs to (if any), such
+// that the queryer can tell a location the NWS doesn't cover from the NWS rejecting queries.
+func classifyNWSError(err error) error {
+	switch {
+	case errors.Is(err, nws.ErrPointUnsupported), errors.Is(err, nws.ErrObservationUnavailable):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationUnsupported, err)
+	case errors.Is(err, nws.ErrInvalidQuery):
+		return fmt.Errorf("%w: %w", providerquery.ErrInvalidInput, err)
+	case errors.Is(err, nws.ErrRateLimited):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,173 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/nws"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// nwsValue creates an [nws.QuantitativeValue] of value in unitCode.
+func nwsValue(value float64, unitCode string) nws.QuantitativeValue {
+	return nws.QuantitativeValue{Value: &value, UnitCode: unitCode}
+}
+
+func TestNWSToSummary(t *testing.T) {
+	t.Parallel()
+
+	observedAt := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.FixedZone("EST", -5*60*60))
+	observedAtUTC := observedAt.UTC()
+
+	for _, tc := range []struct {
+		name        string
+		give        *nws.Observation
+		expected    *weather.Summary
+		expectedErr string
+	}{
+		{
+			name: "converted",
+			give: &nws.Observation{
+				Timestamp:             observedAt,
+				TextDescription:       "Light Rain and Fog/Mist",
+				Temperature:           nwsValue(212, "wmoUnit:degF"),
+				WindSpeed:             nwsValue(10, "wmoUnit:m_s-1"),
+				WindGust:              nwsValue(10, "wmoUnit:kt"),
+				WindDirection:         nwsValue(180, nws.UnitDegrees),
+				RelativeHumidity:      nwsValue(75, nws.UnitPercent),
+				BarometricPressure:    nwsValue(101325, "wmoUnit:Pa"),
+				Visibility:            nwsValue(16000, "wmoUnit:m"),
+				PrecipitationLastHour: nwsValue(1, "wmoUnit:cm"),
+			},
+			expected: &weather.Summary{
+				Temperature:          100,
+				WindSpeed:            36,
+				WindGust:             floatPtr(18.52),
+				WindDirection:        floatPtr(180.0),
+				WindDirectionCompass: "S",
+				Humidity:             floatPtr(75.0),
+				Pressure:             floatPtr(1013.25),
+				Visibility:           floatPtr(16.0),
+				Precipitation:        floatPtr(10.0),
+				Condition:            weather.NewCondition(weather.ConditionRain),
+				ObservedAt:           &observedAtUTC,
+			},
+		},
+		{
+			name: "latter_preferred",
+			give: &nws.Observation{
+				Temperature:        nwsValue(10, nws.UnitCelsius),
+				WindSpeed:          nwsValue(20, nws.UnitKilometresPerHour),
+				HeatIndex:          nwsValue(11, nws.UnitCelsius),
+				WindChill:          nwsValue(8, nws.UnitCelsius),
+				BarometricPressure: nwsValue(1000, nws.UnitHectopascals),
+				SeaLevelPressure:   nwsValue(1013, nws.UnitHectopascals),
+			},
+			expected: &weather.Summary{Temperature: 10, WindSpeed: 20, FeelsLike: floatPtr(8.0), Pressure: floatPtr(1013.0)},
+		},
+		{
+			name: "former_kept_without_latter",
+			give: &nws.Observation{
+				Temperature:        nwsValue(30, nws.UnitCelsius),
+				WindSpeed:          nwsValue(20, nws.UnitKilometresPerHour),
+				HeatIndex:          nwsValue(33, nws.UnitCelsius),
+				WindChill:          nws.QuantitativeValue{UnitCode: nws.UnitCelsius},
+				BarometricPressure: nwsValue(1000, nws.UnitHectopascals),
+				SeaLevelPressure:   nws.QuantitativeValue{Value: floatPtr(1013.0), UnitCode: nws.UnitHectopascals, QualityControl: "X"},
+			},
+			expected: &weather.Summary{Temperature: 30, WindSpeed: 20, FeelsLike: floatPtr(33.0), Pressure: floatPtr(1000.0)},
+		},
+		{
+			name: "unknown_unit",
+			give: &nws.Observation{
+				Temperature: nwsValue(10, "wmoUnit:Test123"),
+			},
+			expectedErr: `temperature: nws: unknown unit "wmoUnit:Test123"`,
+		},
+		{
+			name: "mismatched_unit",
+			give: &nws.Observation{
+				Temperature: nwsValue(10, nws.UnitCelsius),
+				WindSpeed:   nwsValue(10, nws.UnitCelsius),
+			},
+			expectedErr: "windSpeed: nws: can't convert wmoUnit:degC (temperature) to wmoUnit:km_h-1 (speed)",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := nwsToSummary(tc.give)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, actual)
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assertSummaryInDelta(t, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestClassifyNWSError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        error
+		expectedIs  error // The providerquery error class, nil if none.
+		expectedErr string
+	}{
+		{
+			name:        "point_unsupported",
+			give:        fmt.Errorf("%w: Test123", nws.ErrPointUnsupported),
+			expectedIs:  providerquery.ErrLocationUnsupported,
+			expectedErr: "providerquery: location unsupported: nws: point unsupported: Test123",
+		},
+		{
+			name:        "observation_unavailable",
+			give:        nws.ErrObservationUnavailable,
+			expectedIs:  providerquery.ErrLocationUnsupported,
+			expectedErr: "providerquery: location unsupported: nws: observation unavailable",
+		},
+		{
+			name:        "invalid_query",
+			give:        nws.ErrInvalidQuery,
+			expectedIs:  providerquery.ErrInvalidInput,
+			expectedErr: "providerquery: invalid input: nws: invalid query",
+		},
+		{
+			name:        "rate_limited",
+			give:        nws.ErrRateLimited,
+			expectedIs:  providerquery.ErrProviderRejected,
+			expectedErr: "providerquery: provider rejected query: nws: rate limited",
+		},
+		{
+			name:        "other",
+			give:        errors.New("Test123"),
+			expectedErr: "Test123",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := classifyNWSError(tc.give)
+
+			assert.EqualError(t, actual, tc.expectedErr)
+			assert.ErrorIs(t, actual, tc.give, "the original error is kept")
+			assertErrorClass(t, tc.expectedIs, actual)
+		})
+	}
+}
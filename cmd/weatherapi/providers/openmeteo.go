@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+// OpenMeteoProvider wraps an [openmeteo.Client] to satisfy the [providerquery.Provider] interface.
+type OpenMeteoProvider struct {
+	client *openmeteo.Client
+}
+
+var _ providerquery.Provider = (*OpenMeteoProvider)(nil)
+
+// NewOpenMeteoProvider creates a new [OpenMeteoProvider].
+func NewOpenMeteoProvider(c *openmeteo.Client) *OpenMeteoProvider {
+	return &OpenMeteoProvider{client: c}
+}
+
+// ProviderName is the unique name for this provider.
+func (p *OpenMeteoProvider) ProviderName() string {
+	return "Open-Meteo"
+}
+
+// GetWeatherSummary gets a [weather.Summary] for a city.
+func (p *OpenMeteoProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
+	res, err := p.client.CurrentByCityName(ctx, cityName)
+	if err != nil {
+		return nil, fmt.Errorf("current by city name: %w", classifyOpenMeteoError(err))
+	}
+
+	return openMeteoToSummary(&res.Current), nil
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *OpenMeteoProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	res, err := p.client.CurrentByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("current by coordinates: %w", classifyOpenMeteoError(err))
+	}
+
+	return openMeteoToSummary(&res.Current), nil
+}
+
+// openMeteoToSummary translates Open-Meteo weather to a [weather.Summary]. Open-Meteo does not
+// supply the visibility of the current weather.
+func openMeteoToSummary(current *openmeteo.CurrentWeather) *weather.Summary {
+	summary := weather.Summary{
+		Temperature:   current.Temperature,
+		WindSpeed:     current.WindSpeed,
+		WindGust:      current.WindGusts,
+		FeelsLike:     current.ApparentTemperature,
+		Humidity:      current.RelativeHumidity,
+		Pressure:      current.PressureMSL,
+		CloudCover:    current.CloudCover,
+		Precipitation: current.Precipitation,
+	}
+
+	if current.WindDirection != nil {
+		summary.SetWindDirection(*current.WindDirection)
+	}
+
+	if current.WeatherCode != nil {
+		summary.Condition = weather.NewCondition(openMeteoCondition(*current.WeatherCode))
+	}
+
+	if current.Time != 0 {
+		observedAt := time.Unix(current.Time, 0).UTC()
+		summary.ObservedAt = &observedAt
+	}
+
+	return &summary
+}
+
+// classifyOpenMeteoError wraps err in the class of [providerquery] error it belongs to (if any),
+// such that the queryer can tell a city Open-Meteo doesn't know from Open-Meteo rejecting
+// queries. Like when geocoding for other providers, a city the geocoder doesn't know is
+// unsupported rather than not found, as another provider may still know it.
+func classifyOpenMeteoError(err error) error {
+	switch {
+	case errors.Is(err, openmeteo.ErrCityNotFound):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationUnsupported, err)
+	case errors.Is(err, openmeteo.ErrInvalidQuery):
+		return fmt.Errorf("%w: %w", providerquery.ErrInvalidInput, err)
+	case errors.Is(err, openmeteo.ErrRateLimited):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/openmeteo"
+	"github.com/byatesrae/weather/internal/providerquery"
+)
+
+func TestOpenMeteoToSummary(t *testing.T) {
+	t.Parallel()
+
+	observedAt := time.Date(2020, time.November, 11, 10, 0, 0, 0, time.UTC)
+	weatherCode := 3
+
+	for _, tc := range []struct {
+		name     string
+		give     *openmeteo.CurrentWeather
+		expected *weather.Summary
+	}{
+		{
+			name: "complete",
+			give: &openmeteo.CurrentWeather{
+				Time:                observedAt.Unix(),
+				Temperature:         12.5,
+				WindSpeed:           20,
+				WindDirection:       floatPtr(90),
+				WindGusts:           floatPtr(35),
+				ApparentTemperature: floatPtr(11),
+				RelativeHumidity:    floatPtr(75),
+				PressureMSL:         floatPtr(1013.2),
+				CloudCover:          floatPtr(100),
+				Precipitation:       floatPtr(0.2),
+				WeatherCode:         &weatherCode,
+			},
+			expected: &weather.Summary{
+				Temperature:          12.5,
+				WindSpeed:            20,
+				WindDirection:        floatPtr(90),
+				WindDirectionCompass: "E",
+				WindGust:             floatPtr(35),
+				FeelsLike:            floatPtr(11),
+				Humidity:             floatPtr(75),
+				Pressure:             floatPtr(1013.2),
+				CloudCover:           floatPtr(100),
+				Precipitation:        floatPtr(0.2),
+				Condition:            weather.NewCondition(weather.ConditionCloudy),
+				ObservedAt:           &observedAt,
+			},
+		},
+		{
+			name:     "required_only",
+			give:     &openmeteo.CurrentWeather{Temperature: -2, WindSpeed: 5},
+			expected: &weather.Summary{Temperature: -2, WindSpeed: 5},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assertSummaryInDelta(t, tc.expected, openMeteoToSummary(tc.give))
+		})
+	}
+}
+
+func TestClassifyOpenMeteoError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        error
+		expectedIs  error // The providerquery error class, nil if none.
+		expectedErr string
+	}{
+		{
+			// Unsupported rather than not found, as another provider may still know the city.
+			name:        "city_not_found",
+			give:        fmt.Errorf("%w: Test123", openmeteo.ErrCityNotFound),
+			expectedIs:  providerquery.ErrLocationUnsupported,
+			expectedErr: "providerquery: location unsupported: openmeteo: city not found: Test123",
+		},
+		{
+			name:        "invalid_query",
+			give:        openmeteo.ErrInvalidQuery,
+			expectedIs:  providerquery.ErrInvalidInput,
+			expectedErr: "providerquery: invalid input: openmeteo: invalid query",
+		},
+		{
+			name:        "rate_limited",
+			give:        openmeteo.ErrRateLimited,
+			expectedIs:  providerquery.ErrProviderRejected,
+			expectedErr: "providerquery: provider rejected query: openmeteo: rate limited",
+		},
+		{
+			name:        "other",
+			give:        errors.New("Test123"),
+			expectedErr: "Test123",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := classifyOpenMeteoError(tc.give)
+
+			assert.EqualError(t, actual, tc.expectedErr)
+			assert.ErrorIs(t, actual, tc.give, "the original error is kept")
+			assertErrorClass(t, tc.expectedIs, actual)
+		})
+	}
+}
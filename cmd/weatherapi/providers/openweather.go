@@ -2,7 +2,9 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/byatesrae/weather"
 	"github.com/byatesrae/weather/internal/openweather"
@@ -14,7 +16,10 @@ type OpenWeatherProvider struct {
 	client *openweather.Client
 }
 
-var _ providerquery.Provider = (*OpenWeatherProvider)(nil)
+var _ providerquery.ForecastProvider = (*OpenWeatherProvider)(nil)
+
+// openWeatherForecastPeriod is the length of each period in an Openweather forecast.
+const openWeatherForecastPeriod = time.Hour * 3
 
 // NewOpenWeatherProvider creates a new [OpenWeatherProvider].
 func NewOpenWeatherProvider(w *openweather.Client) *OpenWeatherProvider {
@@ -30,11 +35,132 @@ func (p *OpenWeatherProvider) ProviderName() string {
 func (p *OpenWeatherProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
 	res, err := p.client.WeatherByCityName(ctx, cityName)
 	if err != nil {
-		return nil, fmt.Errorf("get weather by city name: %w", err)
+		return nil, fmt.Errorf("get weather by city name: %w", classifyOpenWeatherError(err))
 	}
 
-	return &weather.Summary{
-		Temperature: res.Main.Temperature,
-		WindSpeed:   (res.Wind.WindSpeed * 60 * 60) / 1000, // translate between m/s to km/h
+	return openWeatherToSummary(res), nil
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *OpenWeatherProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	res, err := p.client.WeatherByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("get weather by coordinates: %w", classifyOpenWeatherError(err))
+	}
+
+	return openWeatherToSummary(res), nil
+}
+
+// GetWeatherForecast gets a [weather.Forecast] covering days days for a city. Openweather
+// forecasts cover at most 5 days, in 3 hour periods.
+func (p *OpenWeatherProvider) GetWeatherForecast(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+	periodsPerDay := int((time.Hour * 24) / openWeatherForecastPeriod)
+
+	res, err := p.client.ForecastByCityName(ctx, cityName, days*periodsPerDay)
+	if err != nil {
+		return nil, fmt.Errorf("forecast by city name: %w", classifyOpenWeatherError(err))
+	}
+
+	loc := time.FixedZone(res.City.Name, res.City.Timezone)
+
+	hourly := make([]weather.ForecastEntry, 0, len(res.List))
+	for _, item := range res.List {
+		start := time.Unix(item.Time, 0).In(loc)
+
+		hourly = append(hourly, weather.ForecastEntry{
+			Summary: *openWeatherToSummary(&openweather.WeatherSuccess{
+				Main:       item.Main,
+				Wind:       item.Wind,
+				Weather:    item.Weather,
+				Clouds:     item.Clouds,
+				Visibility: item.Visibility,
+				Rain:       item.Rain,
+				Snow:       item.Snow,
+			}),
+			Start: start,
+			End:   start.Add(openWeatherForecastPeriod),
+		})
+	}
+
+	return &weather.Forecast{
+		Daily:  dailyFromHourly(hourly, loc, days),
+		Hourly: hourly,
 	}, nil
 }
+
+// openWeatherToSummary translates an Openweather response to a [weather.Summary].
+func openWeatherToSummary(res *openweather.WeatherSuccess) *weather.Summary {
+	summary := weather.Summary{
+		Temperature: res.Main.Temperature,
+		WindSpeed:   metresPerSecondToKMPerHour(res.Wind.WindSpeed),
+		FeelsLike:   res.Main.FeelsLike,
+		Humidity:    res.Main.Humidity,
+		Pressure:    res.Main.Pressure,
+	}
+
+	if res.Clouds != nil {
+		summary.CloudCover = res.Clouds.All
+	}
+
+	if res.Visibility != nil {
+		summary.Visibility = floatPtr(*res.Visibility / 1000) // translate between m to km
+	}
+
+	if res.Wind.Direction != nil {
+		summary.SetWindDirection(*res.Wind.Direction)
+	}
+
+	if res.Wind.Gust != nil {
+		summary.WindGust = floatPtr(metresPerSecondToKMPerHour(*res.Wind.Gust))
+	}
+
+	summary.Precipitation = sumFloatPtrs(openWeatherPrecipitation(res.Rain), openWeatherPrecipitation(res.Snow))
+
+	if len(res.Weather) > 0 {
+		summary.Condition = weather.NewCondition(openWeatherCondition(res.Weather[0].ID))
+	}
+
+	if res.Time != nil {
+		observedAt := time.Unix(*res.Time, 0).UTC()
+		summary.ObservedAt = &observedAt
+	}
+
+	return &summary
+}
+
+// openWeatherPrecipitation returns the most recent precipitation volume (in mm) from p, or nil if
+// unknown.
+func openWeatherPrecipitation(p *openweather.WeatherPrecipitation) *float64 {
+	switch {
+	case p == nil:
+		return nil
+	case p.OneHour != nil:
+		return p.OneHour
+	default:
+		return p.ThreeHours
+	}
+}
+
+// metresPerSecondToKMPerHour translates a speed in m/s to km/h.
+func metresPerSecondToKMPerHour(v float64) float64 {
+	return (v * 60 * 60) / 1000
+}
+
+// classifyOpenWeatherError wraps err in the class of [providerquery] error it belongs to (if
+// any), such that the queryer can tell a city Openweather doesn't know from Openweather
+// rejecting queries.
+func classifyOpenWeatherError(err error) error {
+	switch {
+	case errors.Is(err, openweather.ErrCityNotFound):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationNotFound, err)
+	case errors.Is(err, openweather.ErrInvalidQuery):
+		return fmt.Errorf("%w: %w", providerquery.ErrInvalidInput, err)
+	case errors.Is(err, openweather.ErrInvalidKey), errors.Is(err, openweather.ErrRateLimited):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	default:
+		return err
+	}
+}
@@ -2,7 +2,11 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/byatesrae/weather"
 	"github.com/byatesrae/weather/internal/providerquery"
@@ -14,7 +18,10 @@ type WeatherStackProvider struct {
 	client *weatherstack.Client
 }
 
-var _ providerquery.Provider = (*WeatherStackProvider)(nil)
+var _ providerquery.ForecastProvider = (*WeatherStackProvider)(nil)
+
+// weatherStackForecastIntervalHours is the interval requested for Weatherstack forecasts.
+const weatherStackForecastIntervalHours = 3
 
 // NewWeatherStackProvider creates a new [WeatherStackProvider].
 func NewWeatherStackProvider(w *weatherstack.Client) *WeatherStackProvider {
@@ -30,11 +37,158 @@ func (p *WeatherStackProvider) ProviderName() string {
 func (p *WeatherStackProvider) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
 	res, err := p.client.CurrentByCityName(ctx, cityName)
 	if err != nil {
-		return nil, fmt.Errorf("current by city name: %w", err)
+		return nil, fmt.Errorf("current by city name: %w", classifyWeatherStackError(err))
+	}
+
+	summary := weatherStackToSummary(&res.Current)
+	summary.ObservedAt = weatherStackObservedAt(res)
+
+	return summary, nil
+}
+
+// GetWeatherSummaryByCoordinates gets a [weather.Summary] for a latitude/longitude.
+func (p *WeatherStackProvider) GetWeatherSummaryByCoordinates(
+	ctx context.Context,
+	coordinates providerquery.Coordinates,
+) (*weather.Summary, error) {
+	res, err := p.client.CurrentByCoordinates(ctx, coordinates.Latitude, coordinates.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("current by coordinates: %w", classifyWeatherStackError(err))
 	}
 
-	return &weather.Summary{
-		Temperature: float64(res.Current.Temperature),
-		WindSpeed:   float64(res.Current.WindSpeed),
-	}, nil
+	summary := weatherStackToSummary(&res.Current)
+	summary.ObservedAt = weatherStackObservedAt(res)
+
+	return summary, nil
+}
+
+// GetWeatherForecast gets a [weather.Forecast] covering days days for a city.
+func (p *WeatherStackProvider) GetWeatherForecast(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+	res, err := p.client.ForecastByCityName(ctx, cityName, days, weatherStackForecastIntervalHours)
+	if err != nil {
+		return nil, fmt.Errorf("forecast by city name: %w", classifyWeatherStackError(err))
+	}
+
+	utcOffsetHours, err := strconv.ParseFloat(res.Location.UTCOffset, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse utc offset %q: %w", res.Location.UTCOffset, err)
+	}
+
+	loc := time.FixedZone(res.Location.Name, int(utcOffsetHours*60*60))
+
+	dates := make([]string, 0, len(res.Forecast))
+	for date := range res.Forecast {
+		dates = append(dates, date)
+	}
+
+	sort.Strings(dates)
+
+	if len(dates) > days {
+		dates = dates[:days]
+	}
+
+	forecast := weather.Forecast{Daily: []weather.ForecastEntry{}, Hourly: []weather.ForecastEntry{}}
+
+	for _, date := range dates {
+		day := res.Forecast[date]
+
+		dayStart, err := time.ParseInLocation("2006-01-02", day.Date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("parse forecast date %q: %w", day.Date, err)
+		}
+
+		daily := weather.ForecastEntry{
+			Summary:        weather.Summary{Temperature: float64(day.AvgTemp)},
+			Start:          dayStart,
+			End:            dayStart.AddDate(0, 0, 1),
+			TemperatureMin: floatPtr(float64(day.MinTemp)),
+			TemperatureMax: floatPtr(float64(day.MaxTemp)),
+		}
+
+		for _, hour := range day.Hourly {
+			hhmm, err := strconv.Atoi(hour.Time)
+			if err != nil {
+				return nil, fmt.Errorf("parse forecast time %q: %w", hour.Time, err)
+			}
+
+			start := dayStart.Add(time.Duration(hhmm/100)*time.Hour + time.Duration(hhmm%100)*time.Minute)
+
+			forecast.Hourly = append(forecast.Hourly, weather.ForecastEntry{
+				Summary: *weatherStackToSummary(&hour.CurrentWeather),
+				Start:   start,
+				End:     start.Add(time.Hour * weatherStackForecastIntervalHours),
+			})
+
+			daily.WindSpeed = maxFloat(daily.WindSpeed, float64(hour.WindSpeed))
+		}
+
+		forecast.Daily = append(forecast.Daily, daily)
+	}
+
+	return &forecast, nil
+}
+
+// weatherStackObservedAt determines when the current weather was observed. Weatherstack only
+// supplies the UTC time of day, so the date is taken from the location's current time (or the
+// day before, should that time of day not have been reached yet). Nil is returned if this can't
+// be determined.
+func weatherStackObservedAt(res *weatherstack.CurrentSuccess) *time.Time {
+	if res.Location.LocaltimeEpoch == nil || res.Current.ObservationTime == "" {
+		return nil
+	}
+
+	timeOfDay, err := time.Parse("03:04 PM", res.Current.ObservationTime)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Unix(*res.Location.LocaltimeEpoch, 0).UTC()
+	observedAt := time.Date(now.Year(), now.Month(), now.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, time.UTC)
+
+	if observedAt.After(now) {
+		observedAt = observedAt.AddDate(0, 0, -1)
+	}
+
+	return &observedAt
+}
+
+// weatherStackToSummary translates Weatherstack weather to a [weather.Summary]. Weatherstack
+// does not supply wind gust speeds.
+func weatherStackToSummary(current *weatherstack.CurrentWeather) *weather.Summary {
+	summary := weather.Summary{
+		Temperature:   float64(current.Temperature),
+		WindSpeed:     float64(current.WindSpeed),
+		FeelsLike:     current.FeelsLike,
+		Humidity:      current.Humidity,
+		Pressure:      current.Pressure,
+		CloudCover:    current.CloudCover,
+		Visibility:    current.Visibility,
+		Precipitation: current.Precip,
+	}
+
+	if current.WindDegree != nil {
+		summary.SetWindDirection(*current.WindDegree)
+	}
+
+	if current.WeatherCode != nil {
+		summary.Condition = weather.NewCondition(weatherStackCondition(*current.WeatherCode))
+	}
+
+	return &summary
+}
+
+// classifyWeatherStackError wraps err in the class of [providerquery] error it belongs to (if
+// any), such that the queryer can tell a location Weatherstack doesn't know from Weatherstack
+// rejecting queries.
+func classifyWeatherStackError(err error) error {
+	switch {
+	case errors.Is(err, weatherstack.ErrUnknownLocation):
+		return fmt.Errorf("%w: %w", providerquery.ErrLocationNotFound, err)
+	case errors.Is(err, weatherstack.ErrInvalidQuery):
+		return fmt.Errorf("%w: %w", providerquery.ErrInvalidInput, err)
+	case errors.Is(err, weatherstack.ErrInvalidKey), errors.Is(err, weatherstack.ErrQuotaExceeded):
+		return fmt.Errorf("%w: %w", providerquery.ErrProviderRejected, err)
+	default:
+		return err
+	}
 }
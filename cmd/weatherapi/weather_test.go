@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"testing"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather/cmd/weatherapi/handlers"
 )
 
 func TestWeather(t *testing.T) {
@@ -20,9 +23,14 @@ func TestWeather(t *testing.T) {
 		name                    string
 		withOpenweatherHandler  http.HandlerFunc
 		withWeatherstackHandler http.HandlerFunc
+		withOpenMeteoHandler    http.HandlerFunc // Optional, Open-Meteo is unavailable if nil.
+		withNWSHandler          http.HandlerFunc // Optional, the location is outside the US if nil.
+		withMetNoHandler        http.HandlerFunc // Optional, Met Norway is unavailable if nil.
+		withJSONProviderHandler http.HandlerFunc // Optional, the JSON provider is unavailable if nil.
 		give                    *http.Request
 		expectedStatusCode      int
-		expectedBody            string
+		expectedBody            string // The expected body, excluding provenance.
+		expectedProvider        string
 	}{
 		{
 			name: "success_all",
@@ -45,6 +53,7 @@ func TestWeather(t *testing.T) {
 			give:               weatherRequest(context.Background(), t, serverURL, "Sydney"),
 			expectedStatusCode: http.StatusOK,
 			expectedBody:       "{\"wind_speed\":7.2,\"temperature_degrees\":4}\n",
+			expectedProvider:   "Openweather",
 		},
 		{
 			name: "success_openweather",
@@ -61,6 +70,7 @@ func TestWeather(t *testing.T) {
 			give:                    weatherRequest(context.Background(), t, serverURL, "Sydney"),
 			expectedStatusCode:      http.StatusOK,
 			expectedBody:            "{\"wind_speed\":18,\"temperature_degrees\":10}\n",
+			expectedProvider:        "Openweather",
 		},
 		{
 			name:                   "success_weatherstack",
@@ -75,6 +85,244 @@ func TestWeather(t *testing.T) {
 			give:               weatherRequest(context.Background(), t, serverURL, "Sydney"),
 			expectedStatusCode: http.StatusOK,
 			expectedBody:       "{\"wind_speed\":6,\"temperature_degrees\":12}\n",
+			expectedProvider:   "Weatherstack",
+		},
+		{
+			name: "success_other_city",
+			withOpenweatherHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"main": {
+					"temp": 14
+				},
+				"wind": {
+					"speed": 1
+				}
+			}`)),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			give:                    weatherRequest(context.Background(), t, serverURL, "Melbourne"),
+			expectedStatusCode:      http.StatusOK,
+			expectedBody:            "{\"wind_speed\":3.6,\"temperature_degrees\":14}\n",
+			expectedProvider:        "Openweather",
+		},
+		{
+			name: "success_openweather_detailed",
+			withOpenweatherHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"dt": 1605089410,
+				"weather": [{"id": 501, "main": "Rain", "description": "moderate rain"}],
+				"main": {
+					"temp": 11,
+					"feels_like": 9.5,
+					"pressure": 1012,
+					"humidity": 80
+				},
+				"visibility": 8000,
+				"wind": {
+					"speed": 5,
+					"deg": 200,
+					"gust": 10
+				},
+				"clouds": {
+					"all": 90
+				},
+				"rain": {
+					"1h": 2.5
+				}
+			}`)),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			give:                    weatherRequest(context.Background(), t, serverURL, "Sydney"),
+			expectedStatusCode:      http.StatusOK,
+			expectedBody: `{"wind_speed":18,"temperature_degrees":11,"feels_like_degrees":9.5,"humidity_percent":80,` +
+				`"pressure_hpa":1012,"cloud_cover_percent":90,"visibility_km":8,"wind_direction_degrees":200,` +
+				`"wind_direction_compass":"SSW","wind_gust_speed":36,"precipitation_mm":2.5,` +
+				`"condition":{"code":"rain","description":"Rain"},"observed_at":"2020-11-11T10:10:10Z"}` + "\n",
+			expectedProvider: "Openweather",
+		},
+		{
+			name:                   "success_weatherstack_detailed",
+			withOpenweatherHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"location": {
+					"localtime_epoch": 1605089700
+				},
+				"current": {
+					"observation_time": "10:10 AM",
+					"temperature": 13,
+					"weather_code": 116,
+					"weather_descriptions": ["Partly cloudy"],
+					"wind_speed": 15,
+					"wind_degree": 45,
+					"wind_dir": "NE",
+					"pressure": 1015,
+					"precip": 0,
+					"humidity": 60,
+					"cloudcover": 25,
+					"feelslike": 12,
+					"visibility": 10
+				}
+			}`)),
+			give:               weatherRequest(context.Background(), t, serverURL, "Sydney"),
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"wind_speed":15,"temperature_degrees":13,"feels_like_degrees":12,"humidity_percent":60,` +
+				`"pressure_hpa":1015,"cloud_cover_percent":25,"visibility_km":10,"wind_direction_degrees":45,` +
+				`"wind_direction_compass":"NE","precipitation_mm":0,` +
+				`"condition":{"code":"partly_cloudy","description":"Partly cloudy"},"observed_at":"2020-11-11T10:10:00Z"}` + "\n",
+			expectedProvider: "Weatherstack",
+		},
+		{
+			name:                   "success_coordinates",
+			withOpenweatherHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusOK, []byte(`
+			{
+				"current": {
+					"temperature": 9,
+					"wind_speed": 2
+				}
+			}`)),
+			give:               coordinatesWeatherRequest(context.Background(), t, serverURL, -37.81, 144.96),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"wind_speed\":2,\"temperature_degrees\":9}\n",
+			expectedProvider:   "Weatherstack",
+		},
+		{
+			name:                    "success_openmeteo",
+			withOpenweatherHandler:  stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withOpenMeteoHandler: func(rw http.ResponseWriter, req *http.Request) {
+				switch req.URL.Path {
+				case "/search":
+					stubHandler(t, http.StatusOK, []byte(`{"results":[{"name":"Sydney","latitude":-33.87,"longitude":151.21}]}`))(rw, req)
+				case "/forecast":
+					stubHandler(t, http.StatusOK, []byte(`{"current":{"temperature_2m":15.5,"wind_speed_10m":11}}`))(rw, req)
+				default:
+					rw.WriteHeader(http.StatusNotFound)
+				}
+			},
+			give:               weatherRequest(context.Background(), t, serverURL, "Sydney"),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"wind_speed\":11,\"temperature_degrees\":15.5}\n",
+			expectedProvider:   "Open-Meteo",
+		},
+		{
+			name:                    "success_nws",
+			withOpenweatherHandler:  stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withOpenMeteoHandler: func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/search" {
+					rw.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				stubHandler(t, http.StatusOK, []byte(`{"results":[{"name":"Topeka","latitude":39.04833,"longitude":-95.67804}]}`))(rw, req)
+			},
+			withNWSHandler: func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "weatherapi-component-test", req.Header.Get("User-Agent"), "NWS User-Agent")
+
+				switch req.URL.Path {
+				case "/points/39.0483,-95.678":
+					stubHandler(t, http.StatusOK, []byte(`{"properties":{"gridId":"TOP","gridX":65,"gridY":70}}`))(rw, req)
+				case "/gridpoints/TOP/65,70/stations":
+					stubHandler(t, http.StatusOK, []byte(`{"features":[{"properties":{"stationIdentifier":"KTOP"}}]}`))(rw, req)
+				case "/stations/KTOP/observations/latest":
+					stubHandler(t, http.StatusOK, []byte(`
+					{
+						"properties": {
+							"timestamp": "2020-11-11T10:10:00+00:00",
+							"textDescription": "Mostly Cloudy",
+							"temperature": {"unitCode": "wmoUnit:degC", "value": 8.3, "qualityControl": "V"},
+							"windSpeed": {"unitCode": "wmoUnit:m_s-1", "value": 5, "qualityControl": "V"},
+							"windDirection": {"unitCode": "wmoUnit:degree_(angle)", "value": 180, "qualityControl": "V"},
+							"seaLevelPressure": {"unitCode": "wmoUnit:Pa", "value": 101500, "qualityControl": "V"},
+							"visibility": {"unitCode": "wmoUnit:m", "value": 16090, "qualityControl": "C"},
+							"relativeHumidity": {"unitCode": "wmoUnit:percent", "value": 71, "qualityControl": "V"},
+							"windGust": {"unitCode": "wmoUnit:km_h-1", "value": null, "qualityControl": "Z"}
+						}
+					}`))(rw, req)
+				default:
+					rw.WriteHeader(http.StatusNotFound)
+				}
+			},
+			give:               weatherRequest(context.Background(), t, serverURL, "Topeka"),
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"wind_speed":18,"temperature_degrees":8.3,"humidity_percent":71,"pressure_hpa":1015,` +
+				`"visibility_km":16.09,"wind_direction_degrees":180,"wind_direction_compass":"S",` +
+				`"condition":{"code":"cloudy","description":"Cloudy"},"observed_at":"2020-11-11T10:10:00Z"}` + "\n",
+			expectedProvider: "NWS",
+		},
+		{
+			name:                    "success_metno",
+			withOpenweatherHandler:  stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withOpenMeteoHandler: func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/search" {
+					rw.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				stubHandler(t, http.StatusOK, []byte(`{"results":[{"name":"Oslo","latitude":59.91273,"longitude":10.74609}]}`))(rw, req)
+			},
+			withMetNoHandler: func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "weatherapi-component-test", req.Header.Get("User-Agent"), "Met Norway User-Agent")
+				assert.Equal(t, "/compact", req.URL.Path, "Met Norway path")
+				assert.Equal(t, "59.9127", req.URL.Query().Get("lat"), "Met Norway latitude")
+				assert.Equal(t, "10.7461", req.URL.Query().Get("lon"), "Met Norway longitude")
+
+				rw.Header().Set("Expires", time.Now().Add(time.Hour).Format(http.TimeFormat))
+				rw.Header().Set("Last-Modified", time.Now().Format(http.TimeFormat))
+				stubHandler(t, http.StatusOK, []byte(`
+				{
+					"properties": {
+						"meta": {"updated_at": "2020-11-11T09:30:00Z"},
+						"timeseries": [
+							{
+								"time": "2020-11-11T10:00:00Z",
+								"data": {
+									"instant": {
+										"details": {
+											"air_temperature": 4.2,
+											"wind_speed": 5,
+											"wind_from_direction": 225,
+											"relative_humidity": 80,
+											"air_pressure_at_sea_level": 1012.3,
+											"cloud_area_fraction": 100
+										}
+									},
+									"next_1_hours": {
+										"summary": {"symbol_code": "lightrain"},
+										"details": {"precipitation_amount": 0.3}
+									}
+								}
+							}
+						]
+					}
+				}`))(rw, req)
+			},
+			give:               weatherRequest(context.Background(), t, serverURL, "Oslo"),
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"wind_speed":18,"temperature_degrees":4.2,"humidity_percent":80,"pressure_hpa":1012.3,` +
+				`"cloud_cover_percent":100,"wind_direction_degrees":225,"wind_direction_compass":"SW","precipitation_mm":0.3,` +
+				`"condition":{"code":"rain","description":"Rain"},"observed_at":"2020-11-11T10:00:00Z"}` + "\n",
+			expectedProvider: "Met Norway",
+		},
+		{
+			name:                    "success_jsonprovider",
+			withOpenweatherHandler:  stubHandler(t, http.StatusServiceUnavailable, nil),
+			withWeatherstackHandler: stubHandler(t, http.StatusServiceUnavailable, nil),
+			withJSONProviderHandler: func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "SET_BY_TESTMAIN", req.Header.Get("X-Api-Key"), "JSON provider key")
+				assert.Equal(t, "/current", req.URL.Path, "JSON provider path")
+				assert.Equal(t, "Phoenix", req.URL.Query().Get("q"), "JSON provider city")
+
+				stubHandler(t, http.StatusOK, []byte(`{"current":{"temp_f":95,"wind":5,"weather":["Sunny"],"time":"2020-11-11T10:00:00Z"}}`))(rw, req)
+			},
+			give:               weatherRequest(context.Background(), t, serverURL, "Phoenix"),
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"wind_speed":18,"temperature_degrees":35,"condition":{"code":"clear","description":"Clear"},` +
+				`"observed_at":"2020-11-11T10:00:00Z"}` + "\n",
+			expectedProvider: "Example JSON",
 		},
 	} {
 		tc := tc
@@ -86,6 +334,22 @@ func TestWeather(t *testing.T) {
 			registerWeatherstackStub(t, requestID, tc.withWeatherstackHandler)
 			registerOpenweatherStub(t, requestID, tc.withOpenweatherHandler)
 
+			if tc.withOpenMeteoHandler != nil {
+				registerOpenMeteoStub(t, requestID, tc.withOpenMeteoHandler)
+			}
+
+			if tc.withNWSHandler != nil {
+				registerNWSStub(t, requestID, tc.withNWSHandler)
+			}
+
+			if tc.withMetNoHandler != nil {
+				registerMetNoStub(t, requestID, tc.withMetNoHandler)
+			}
+
+			if tc.withJSONProviderHandler != nil {
+				registerJSONProviderStub(t, requestID, tc.withJSONProviderHandler)
+			}
+
 			tc.give.Header.Add("X-Correlation-Id", requestID)
 
 			// Do
@@ -106,7 +370,17 @@ func TestWeather(t *testing.T) {
 			require.NoError(t, err, "read body error")
 
 			assert.Equal(t, tc.expectedStatusCode, res.StatusCode, "response status code")
-			assert.Equal(t, tc.expectedBody, string(actualBody), "response body")
+
+			var actualResponse handlers.WeatherResponse
+			require.NoError(t, json.Unmarshal(actualBody, &actualResponse), "unmarshal body error")
+
+			actualSummary, err := json.Marshal(actualResponse.Summary)
+			require.NoError(t, err, "marshal summary error")
+
+			assert.Equal(t, tc.expectedBody, string(actualSummary)+"\n", "response body")
+			assert.Equal(t, tc.expectedProvider, actualResponse.Provider, "response provider")
+			assert.False(t, actualResponse.Stale, "response stale")
+			assert.WithinDuration(t, time.Now(), actualResponse.FetchedAt, time.Minute, "response fetched at")
 
 			// Wait for cache, with +1 second as cache expiry header resolution
 			// is in seconds.
@@ -115,3 +389,37 @@ func TestWeather(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherLocationNotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	requestID := newRequestID(t)
+
+	registerOpenweatherStub(t, requestID, stubHandler(t, http.StatusNotFound, []byte(`{"cod":"404","message":"city not found"}`)))
+	registerWeatherstackStub(t, requestID, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Error("Weatherstack queried, though Openweather didn't find the city.")
+	}))
+
+	req := weatherRequest(context.Background(), t, serverURL, "Atlantis")
+	req.Header.Add("X-Correlation-Id", requestID)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "request error")
+
+	t.Cleanup(func() {
+		if err := res.Body.Close(); err != nil {
+			t.Errorf("failed to close body: %s", err)
+		}
+	})
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "response status code")
+	assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"), "response content type")
+
+	var problem handlers.ProblemResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&problem), "response body")
+
+	assert.Equal(t, handlers.ProblemCodeLocationNotFound, problem.Code, "problem code")
+	assert.Equal(t, requestID, problem.CorrelationID, "problem correlation ID")
+}
@@ -0,0 +1,20 @@
+package weather
+
+import "math"
+
+// compassPoints are the 16 points of the compass, clockwise from north.
+var compassPoints = [16]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// CompassPoint converts a direction in degrees to the nearest of the 16 points of
+// the compass, e.g 22.5 is "NNE".
+func CompassPoint(degrees float64) string {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+
+	return compassPoints[int(math.Round(degrees/22.5))%16]
+}
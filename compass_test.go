@@ -0,0 +1,29 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompassPoint(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		give     float64
+		expected string
+	}{
+		{give: 0, expected: "N"},
+		{give: 11.24, expected: "N"},
+		{give: 11.25, expected: "NNE"},
+		{give: 22.5, expected: "NNE"},
+		{give: 90, expected: "E"},
+		{give: 200, expected: "SSW"},
+		{give: 348.75, expected: "N"},
+		{give: 360, expected: "N"},
+		{give: -90, expected: "W"},
+		{give: 450, expected: "E"},
+	} {
+		assert.Equal(t, tc.expected, CompassPoint(tc.give), "CompassPoint(%v)", tc.give)
+	}
+}
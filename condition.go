@@ -0,0 +1,54 @@
+package weather
+
+// ConditionCode is a normalized weather condition, independent of provider.
+type ConditionCode string
+
+// All the supported ConditionCode values.
+const (
+	ConditionUnknown      ConditionCode = "unknown"
+	ConditionClear        ConditionCode = "clear"
+	ConditionPartlyCloudy ConditionCode = "partly_cloudy"
+	ConditionCloudy       ConditionCode = "cloudy"
+	ConditionFog          ConditionCode = "fog"
+	ConditionHaze         ConditionCode = "haze"
+	ConditionDrizzle      ConditionCode = "drizzle"
+	ConditionRain         ConditionCode = "rain"
+	ConditionSleet        ConditionCode = "sleet"
+	ConditionSnow         ConditionCode = "snow"
+	ConditionThunderstorm ConditionCode = "thunderstorm"
+)
+
+// conditionDescriptions are the human readable descriptions of each ConditionCode.
+var conditionDescriptions = map[ConditionCode]string{
+	ConditionUnknown:      "Unknown",
+	ConditionClear:        "Clear",
+	ConditionPartlyCloudy: "Partly cloudy",
+	ConditionCloudy:       "Cloudy",
+	ConditionFog:          "Fog",
+	ConditionHaze:         "Haze",
+	ConditionDrizzle:      "Drizzle",
+	ConditionRain:         "Rain",
+	ConditionSleet:        "Sleet",
+	ConditionSnow:         "Snow",
+	ConditionThunderstorm: "Thunderstorm",
+}
+
+// Description returns a human readable description of the condition.
+func (c ConditionCode) Description() string {
+	if d, ok := conditionDescriptions[c]; ok {
+		return d
+	}
+
+	return conditionDescriptions[ConditionUnknown]
+}
+
+// Condition is a normalized weather condition.
+type Condition struct {
+	Code        ConditionCode `json:"code"`
+	Description string        `json:"description"`
+}
+
+// NewCondition creates a new [Condition] for code.
+func NewCondition(code ConditionCode) *Condition {
+	return &Condition{Code: code, Description: code.Description()}
+}
@@ -0,0 +1,19 @@
+package weather
+
+import "time"
+
+// Forecast represents forecast weather datapoints for a location.
+type Forecast struct {
+	Daily  []ForecastEntry `json:"daily"`  // One entry per day, in the location's timezone.
+	Hourly []ForecastEntry `json:"hourly"` // Entries at the provider's hourly resolution (e.g every 3 hours).
+}
+
+// ForecastEntry represents forecast weather datapoints for a location over a period of time.
+type ForecastEntry struct {
+	Summary
+
+	Start          time.Time `json:"start"`                             // The start of the period (inclusive).
+	End            time.Time `json:"end"`                               // The end of the period (exclusive).
+	TemperatureMin *float64  `json:"temperature_min_degrees,omitempty"` // The minimum temperature in degrees celsius, if known.
+	TemperatureMax *float64  `json:"temperature_max_degrees,omitempty"` // The maximum temperature in degrees celsius, if known.
+}
@@ -0,0 +1,194 @@
+package jsonprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+)
+
+// tracerName is the name of the tracer used by the [Client], see [apiclient.WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/jsonprovider"
+
+// redactedKey replaces the key of a [Definition] in URLs that are logged or traced.
+const redactedKey = "*****"
+
+// Client is used to interact with the API of the provider a [Definition] defines.
+type Client struct {
+	client     *apiclient.Client
+	definition *Definition
+}
+
+// New creates a new [Client] for definition, which must be valid (see [Definition.Validate]).
+func New(definition *Definition, optionOverrides ...func(*apiclient.NewOptions)) *Client {
+	return &Client{
+		client:     apiclient.New("jsonprovider", tracerName, optionOverrides...),
+		definition: definition,
+	}
+}
+
+// Name returns the name of the provider.
+func (c *Client) Name() string {
+	return c.definition.Name
+}
+
+// CurrentByCityName returns the current weather for a city. If the definition has no URL to
+// query by city, the error is an [ErrUnsupported].
+func (c *Client) CurrentByCityName(ctx context.Context, cityName string) (*weather.Summary, error) {
+	if c.definition.URL == "" {
+		return nil, errors.Wrapf(ErrUnsupported, "jsonprovider: %s: city name", c.definition.Name)
+	}
+
+	if cityName == "" {
+		return nil, errors.New("jsonprovider: cityname is required")
+	}
+
+	doc, err := c.get(ctx, c.definition.URL, placeholderCity, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.definition.toSummary(doc)
+}
+
+// CurrentByCoordinates returns the current weather for a latitude/longitude (in decimal
+// degrees). If the definition has no URL to query by coordinates, the error is an
+// [ErrUnsupported].
+func (c *Client) CurrentByCoordinates(ctx context.Context, latitude, longitude float64) (*weather.Summary, error) {
+	if c.definition.CoordinatesURL == "" {
+		return nil, errors.Wrapf(ErrUnsupported, "jsonprovider: %s: coordinates", c.definition.Name)
+	}
+
+	doc, err := c.get(
+		ctx,
+		c.definition.CoordinatesURL,
+		placeholderLatitude, strconv.FormatFloat(latitude, 'f', -1, 64),
+		placeholderLongitude, strconv.FormatFloat(longitude, 'f', -1, 64),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.definition.toSummary(doc)
+}
+
+// escape escapes s for a URL, in either its path or query.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// get sends a GET request to the URL of tmpl, with its placeholders replaced by the (unescaped)
+// values of placeholderValues (pairs of placeholder & value), returning the decoded (JSON)
+// response body. An error response (see [ErrorRule]) is returned as an [*APIError].
+func (c *Client) get(ctx context.Context, tmpl string, placeholderValues ...string) (doc interface{}, err error) {
+	var replacements, redactedReplacements []string
+
+	for i := 0; i+1 < len(placeholderValues); i += 2 {
+		replacements = append(replacements, placeholderValues[i], escape(placeholderValues[i+1]))
+	}
+
+	redactedReplacements = append(redactedReplacements, replacements...)
+	replacements = append(replacements, placeholderKey, escape(c.definition.Key))
+	redactedReplacements = append(redactedReplacements, placeholderKey, redactedKey)
+
+	req := apiclient.Request{
+		URL:        strings.NewReplacer(replacements...).Replace(tmpl),
+		TracedURL:  strings.NewReplacer(redactedReplacements...).Replace(tmpl),
+		Header:     http.Header{"Accept": []string{"application/json"}},
+		Attributes: []attribute.KeyValue{attribute.String("provider", c.definition.Name)},
+	}
+
+	if i := strings.IndexByte(req.TracedURL, '?'); i >= 0 {
+		req.TracedURL = req.TracedURL[:i]
+	}
+
+	if auth := c.definition.Auth; auth != nil {
+		switch auth.In {
+		case AuthInQuery:
+			req.Query = url.Values{auth.Name: []string{auth.Prefix + c.definition.Key}}
+		case AuthInHeader:
+			req.Header.Set(auth.Name, auth.Prefix+c.definition.Key)
+		}
+	}
+
+	err = c.client.Get(ctx, &req, func(res *http.Response, body []byte) error {
+		decodeErr := json.Unmarshal(body, &doc)
+
+		if apiErr := c.definition.detectError(res.StatusCode, doc); apiErr != nil {
+			return apiErr
+		}
+
+		if decodeErr != nil {
+			return errors.Wrap(decodeErr, "jsonprovider: decode body")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// detectError returns the [*APIError] for a response with statusCode & (decoded) body doc, or nil
+// if the response isn't an error.
+func (d *Definition) detectError(statusCode int, doc interface{}) *APIError {
+	isErr := statusCode < 200 || statusCode > 299
+
+	if !isErr && d.Error != nil && d.Error.Path != "" {
+		v, ok := d.errorSelector.selectFrom(doc)
+		isErr = ok && (d.Error.Equals == nil || reflect.DeepEqual(v, d.Error.Equals))
+	}
+
+	if !isErr {
+		return nil
+	}
+
+	apiErr := &APIError{Provider: d.Name, StatusCode: statusCode}
+
+	if statusCode < 200 || statusCode > 299 {
+		apiErr.Code = strconv.Itoa(statusCode)
+	}
+
+	if d.Error == nil {
+		return apiErr
+	}
+
+	if d.Error.CodePath != "" {
+		if v, ok := d.codeSelector.selectFrom(doc); ok {
+			apiErr.Code = stringValue(v)
+		}
+	}
+
+	if d.Error.MessagePath != "" {
+		if v, ok := d.msgSelector.selectFrom(doc); ok {
+			apiErr.Message = stringValue(v)
+		}
+	}
+
+	for _, class := range []struct {
+		codes []string
+		err   error
+	}{
+		{d.Error.LocationNotFoundCodes, ErrLocationNotFound},
+		{d.Error.RejectedCodes, ErrRejected},
+	} {
+		for _, code := range class.codes {
+			if code == apiErr.Code {
+				apiErr.class = class.err
+			}
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,302 @@
+package jsonprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+	"github.com/byatesrae/weather/internal/platform/apiclient/apiclienttest"
+)
+
+// stubResponse creates an http response with status code & body.
+func stubResponse(statusCode int, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+// mustLoadDefinition loads the (single) definition of the JSON array s.
+func mustLoadDefinition(t *testing.T, s string) *Definition {
+	t.Helper()
+
+	definitions, err := Load(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return definitions[0]
+}
+
+// testDefinition is a definition of a provider returning an error with a successful status code.
+const testDefinition = `[{
+	"name": "Example",
+	"url": "https://api.example.com/v1/current?q={city}&key={key}",
+	"key": "abc 123",
+	"fields": {
+		"temperature_degrees": {"path": "$.current.temp_f", "unit": "fahrenheit"},
+		"wind_speed": {"path": "$.current.wind_mph", "unit": "mph"},
+		"humidity_percent": {"path": "$.current.humidity"},
+		"pressure_hpa": {"path": "$.current.pressure_mb"},
+		"wind_direction_degrees": {"path": "$.current.wind_degree"},
+		"cloud_cover_percent": {"path": "$.current.cloud", "unit": "fraction"},
+		"condition": {"path": "$.current.condition.text", "map": {"Light rain": "rain"}},
+		"observed_at": {"path": "$.current.last_updated_epoch"}
+	},
+	"error": {
+		"path": "$.error",
+		"code_path": "$.error.code",
+		"message_path": "$.error.message",
+		"location_not_found_codes": ["1006"],
+		"rejected_codes": ["2006"]
+	}
+}]`
+
+func TestClientCurrentByCityName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		giveResponse  *http.Response
+		expected      *weather.Summary
+		expectedErr   string
+		expectedClass error
+	}{
+		{
+			name:         "success",
+			giveResponse: stubResponse(http.StatusOK, `{"current":{"temp_f":50,"wind_mph":10,"humidity":82,"pressure_mb":"1013.25","wind_degree":225,"cloud":0.75,"condition":{"text":"Light rain"},"last_updated_epoch":1605089400}}`),
+			expected: func() *weather.Summary {
+				humidity, pressure, cloudCover := 82.0, 1013.25, 75.0
+				observedAt := time.Date(2020, 11, 11, 10, 10, 0, 0, time.UTC)
+
+				summary := &weather.Summary{
+					Temperature: 10,
+					WindSpeed:   16.09344,
+					Humidity:    &humidity,
+					Pressure:    &pressure,
+					CloudCover:  &cloudCover,
+					Condition:   weather.NewCondition(weather.ConditionRain),
+					ObservedAt:  &observedAt,
+				}
+				summary.SetWindDirection(225)
+
+				return summary
+			}(),
+		},
+		{
+			name:         "optional_fields_missing",
+			giveResponse: stubResponse(http.StatusOK, `{"current":{"temp_f":50,"wind_mph":0,"condition":{"text":"Blizzard"}}}`),
+			expected: &weather.Summary{
+				Temperature: 10,
+				Condition:   weather.NewCondition(weather.ConditionUnknown),
+			},
+		},
+		{
+			name:         "missing_temperature",
+			giveResponse: stubResponse(http.StatusOK, `{"current":{"wind_mph":10}}`),
+			expectedErr:  "jsonprovider: Example: response has no temperature_degrees",
+		},
+		{
+			name:         "invalid_value",
+			giveResponse: stubResponse(http.StatusOK, `{"current":{"temp_f":50,"wind_mph":"calm"}}`),
+			expectedErr:  `jsonprovider: Example: wind_speed: jsonprovider: "calm" is not a number`,
+		},
+		{
+			name:         "unexpected_response_type",
+			giveResponse: stubResponse(http.StatusOK, `ABCDEFG`),
+			expectedErr:  "jsonprovider: decode body: invalid character 'A' looking for beginning of value",
+		},
+		{
+			name:          "location_not_found",
+			giveResponse:  stubResponse(http.StatusOK, `{"error":{"code":1006,"message":"No matching location found."}}`),
+			expectedErr:   "jsonprovider: Example: error response (status code 200, code 1006): No matching location found.",
+			expectedClass: ErrLocationNotFound,
+		},
+		{
+			name:          "rejected",
+			giveResponse:  stubResponse(http.StatusForbidden, `{"error":{"code":2006,"message":"API key is invalid."}}`),
+			expectedErr:   "jsonprovider: Example: error response (status code 403, code 2006): API key is invalid.",
+			expectedClass: ErrRejected,
+		},
+		{
+			name:         "unsuccessful_status_code",
+			giveResponse: stubResponse(http.StatusBadGateway, `<html></html>`),
+			expectedErr:  "jsonprovider: Example: error response (status code 502, code 502)",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			client := New(mustLoadDefinition(t, testDefinition), apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "https://api.example.com/v1/current?q=New%20York&key=abc%20123", req.URL.String())
+
+					return tc.giveResponse, nil
+				},
+			}))
+
+			actual, err := client.CurrentByCityName(ctx, "New York")
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tc.expectedClass != nil {
+				assert.ErrorIs(t, err, tc.expectedClass)
+			}
+		})
+	}
+}
+
+func TestClientCurrentByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		giveDefinition string
+		assertRequest  func(t *testing.T, req *http.Request)
+		giveResponse   string
+		expectedErr    string
+	}{
+		{
+			name: "auth_in_query",
+			giveDefinition: `[{
+				"name": "Example",
+				"coordinates_url": "https://api.example.com/v1/current?lat={lat}&lon={lon}",
+				"key": "abc123",
+				"auth": {"in": "query", "name": "appid"},
+				"fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind", "unit": "m/s"}}
+			}]`,
+			assertRequest: func(t *testing.T, req *http.Request) {
+				assert.Equal(t, "-33.87", req.URL.Query().Get("lat"))
+				assert.Equal(t, "151.21", req.URL.Query().Get("lon"))
+				assert.Equal(t, "abc123", req.URL.Query().Get("appid"))
+			},
+			giveResponse: `{"temp":12.5,"wind":2}`,
+		},
+		{
+			name: "auth_in_header",
+			giveDefinition: `[{
+				"name": "Example",
+				"coordinates_url": "https://api.example.com/v1/current/{lat},{lon}",
+				"key": "abc123",
+				"auth": {"in": "header", "name": "Authorization", "prefix": "Bearer "},
+				"fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind", "unit": "m/s"}}
+			}]`,
+			assertRequest: func(t *testing.T, req *http.Request) {
+				assert.Equal(t, "/v1/current/-33.87,151.21", req.URL.Path)
+				assert.Equal(t, "Bearer abc123", req.Header.Get("Authorization"))
+				assert.Empty(t, req.URL.RawQuery)
+			},
+			giveResponse: `{"temp":12.5,"wind":2}`,
+		},
+		{
+			name: "error_equals",
+			giveDefinition: `[{
+				"name": "Example",
+				"coordinates_url": "https://api.example.com/v1/current?lat={lat}&lon={lon}",
+				"fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind", "unit": "m/s"}},
+				"error": {"path": "$.success", "equals": false, "message_path": "$.info"}
+			}]`,
+			assertRequest: func(t *testing.T, req *http.Request) {},
+			giveResponse:  `{"success":false,"info":"Test123"}`,
+			expectedErr:   "jsonprovider: Example: error response (status code 200): Test123",
+		},
+		{
+			name: "error_not_equals",
+			giveDefinition: `[{
+				"name": "Example",
+				"coordinates_url": "https://api.example.com/v1/current?lat={lat}&lon={lon}",
+				"fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind", "unit": "m/s"}},
+				"error": {"path": "$.success", "equals": false}
+			}]`,
+			assertRequest: func(t *testing.T, req *http.Request) {},
+			giveResponse:  `{"success":true,"temp":12.5,"wind":2}`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			client := New(mustLoadDefinition(t, tc.giveDefinition), apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					tc.assertRequest(t, req)
+
+					return stubResponse(http.StatusOK, tc.giveResponse), nil
+				},
+			}))
+
+			actual, err := client.CurrentByCoordinates(ctx, -33.87, 151.21)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, actual)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &weather.Summary{Temperature: 12.5, WindSpeed: 7.2}, actual)
+			}
+		})
+	}
+}
+
+func TestClientUnsupported(t *testing.T) {
+	t.Parallel()
+
+	client := New(mustLoadDefinition(t, testDefinition), apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{}))
+
+	_, err := client.CurrentByCoordinates(context.Background(), -33.87, 151.21)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestClientCurrentByCityNameTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New(mustLoadDefinition(t, testDefinition),
+		apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return nil, errors.New("intentional test error")
+			},
+		}),
+		apiclient.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.CurrentByCityName(context.Background(), "Sydney")
+	assert.EqualError(t, err, "jsonprovider: execute request: intentional test error")
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "jsonprovider GET", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://api.example.com/v1/current"))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("provider", "Example"))
+	}
+}
@@ -0,0 +1,414 @@
+package jsonprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/byatesrae/weather"
+)
+
+// Field names of a [Definition], being the JSON names of the [weather.Summary] fields.
+const (
+	FieldTemperature   = "temperature_degrees"
+	FieldWindSpeed     = "wind_speed"
+	FieldFeelsLike     = "feels_like_degrees"
+	FieldHumidity      = "humidity_percent"
+	FieldPressure      = "pressure_hpa"
+	FieldCloudCover    = "cloud_cover_percent"
+	FieldVisibility    = "visibility_km"
+	FieldWindDirection = "wind_direction_degrees"
+	FieldWindGust      = "wind_gust_speed"
+	FieldPrecipitation = "precipitation_mm"
+	FieldCondition     = "condition"
+	FieldObservedAt    = "observed_at"
+)
+
+// numericFields are the numeric fields of a [Definition] & the unit of the [weather.Summary]
+// field they are converted to.
+var numericFields = map[string]string{
+	FieldTemperature:   "celsius",
+	FieldWindSpeed:     "km/h",
+	FieldFeelsLike:     "celsius",
+	FieldHumidity:      "percent",
+	FieldPressure:      "hPa",
+	FieldCloudCover:    "percent",
+	FieldVisibility:    "km",
+	FieldWindDirection: "degrees",
+	FieldWindGust:      "km/h",
+	FieldPrecipitation: "mm",
+}
+
+// Time formats of the [FieldObservedAt] field.
+const (
+	TimeUnix      = "unix"    // Seconds since the epoch.
+	TimeUnixMilli = "unix_ms" // Milliseconds since the epoch.
+	TimeRFC3339   = "rfc3339"
+)
+
+// Placeholders of the URL templates of a [Definition].
+const (
+	placeholderCity      = "{city}"
+	placeholderLatitude  = "{lat}"
+	placeholderLongitude = "{lon}"
+	placeholderKey       = "{key}"
+)
+
+// placeholderPattern matches the placeholders of a URL template.
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// Where the key of a [Definition] is sent, see [AuthDefinition].
+const (
+	AuthInQuery  = "query"
+	AuthInHeader = "header"
+)
+
+// Definition defines a provider: how to query it & how to read the weather from its (JSON)
+// responses.
+type Definition struct {
+	// Name is the unique name of the provider.
+	Name string `json:"name"`
+
+	// URL is the template of the URL queried for a city, with the placeholders "{city}" & (if
+	// not sent with Auth) "{key}", e.g "https://api.example.com/current?q={city}". If empty,
+	// the provider can't be queried by city.
+	URL string `json:"url,omitempty"`
+
+	// CoordinatesURL is the template of the URL queried for coordinates, with the
+	// placeholders "{lat}", "{lon}" & (if not sent with Auth) "{key}". If empty, the provider
+	// can't be queried by coordinates.
+	CoordinatesURL string `json:"coordinates_url,omitempty"`
+
+	// Key is the API key of the provider, or KeyEnv the environment variable holding it.
+	Key    string `json:"key,omitempty"`
+	KeyEnv string `json:"key_env,omitempty"`
+
+	// Auth is where the key is sent, if not in the URL.
+	Auth *AuthDefinition `json:"auth,omitempty"`
+
+	// Fields are where the [weather.Summary] fields are in a response, keyed by the JSON name
+	// of the field (e.g "temperature_degrees"). The temperature & wind speed are required.
+	Fields map[string]FieldDefinition `json:"fields"`
+
+	// Error detects errors returned by the provider with a successful status code, & classifies
+	// errors. Without it, only an unsuccessful status code is an error.
+	Error *ErrorRule `json:"error,omitempty"`
+
+	selectors     map[string]selector // Parsed from Fields.
+	errorSelector selector            // Parsed from Error.Path.
+	codeSelector  selector            // Parsed from Error.CodePath.
+	msgSelector   selector            // Parsed from Error.MessagePath.
+}
+
+// AuthDefinition is where the key of a [Definition] is sent.
+type AuthDefinition struct {
+	In     string `json:"in"`               // Either "query" or "header".
+	Name   string `json:"name"`             // The name of the query parameter or header, e.g "appid".
+	Prefix string `json:"prefix,omitempty"` // Prepended to the key, e.g "Bearer ".
+}
+
+// FieldDefinition is where a [weather.Summary] field is in a response.
+type FieldDefinition struct {
+	// Path selects the value, e.g "$.current.weather[0].temp", see parseSelector.
+	Path string `json:"path"`
+
+	// Unit is the unit of the value, converted to the unit of the field. Numeric fields
+	// default to the unit of the field (e.g "celsius" for "temperature_degrees"). The
+	// observed time is one of "unix" (the default), "unix_ms" or "rfc3339".
+	Unit string `json:"unit,omitempty"`
+
+	// Map translates the values of the condition to [weather.ConditionCode]s, e.g "Clouds":
+	// "cloudy". Without it the value must be a condition code. Values not found are unknown.
+	Map map[string]string `json:"map,omitempty"`
+}
+
+// ErrorRule detects & classifies errors returned by a provider.
+type ErrorRule struct {
+	// Path selects a value present only in error responses, e.g "error".
+	Path string `json:"path,omitempty"`
+
+	// Equals is the value (if set) selected by Path in error responses, e.g false for
+	// "success".
+	Equals interface{} `json:"equals,omitempty"`
+
+	// CodePath & MessagePath select the code & message of an error. The code defaults to the
+	// response status code.
+	CodePath    string `json:"code_path,omitempty"`
+	MessagePath string `json:"message_path,omitempty"`
+
+	// Codes classifying an error as the location not being found ([ErrLocationNotFound]) or
+	// the provider rejecting every query ([ErrRejected]), e.g for an invalid key.
+	LocationNotFoundCodes []string `json:"location_not_found_codes,omitempty"`
+	RejectedCodes         []string `json:"rejected_codes,omitempty"`
+}
+
+// LoadOptions are the options for the [Load] function.
+type LoadOptions struct {
+	lookupEnv func(key string) (string, bool)
+}
+
+// WithLookupEnv sets the function used to look up environment variables (see
+// [Definition.KeyEnv]), [os.LookupEnv] by default.
+func WithLookupEnv(lookupEnv func(key string) (string, bool)) func(*LoadOptions) {
+	return func(o *LoadOptions) {
+		o.lookupEnv = lookupEnv
+	}
+}
+
+// Load reads a JSON array of [Definition]s from r, validating them.
+func Load(r io.Reader, optionOverrides ...func(*LoadOptions)) ([]*Definition, error) {
+	options := LoadOptions{lookupEnv: os.LookupEnv}
+
+	for _, optionOverride := range optionOverrides {
+		optionOverride(&options)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "jsonprovider: read definitions")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var definitions []*Definition
+	if err := decoder.Decode(&definitions); err != nil {
+		return nil, errors.Wrap(err, "jsonprovider: decode definitions")
+	}
+
+	names := make(map[string]bool, len(definitions))
+
+	for i, d := range definitions {
+		if d.KeyEnv != "" {
+			key, ok := options.lookupEnv(d.KeyEnv)
+			if !ok {
+				return nil, errors.Errorf("jsonprovider: definition %v (%q): key_env: environment variable %s is not set", i, d.Name, d.KeyEnv)
+			}
+
+			d.Key = key
+		}
+
+		if err := d.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "jsonprovider: definition %v (%q)", i, d.Name)
+		}
+
+		if names[d.Name] {
+			return nil, errors.Errorf("jsonprovider: definition %v: name %q is not unique", i, d.Name)
+		}
+
+		names[d.Name] = true
+	}
+
+	return definitions, nil
+}
+
+// LoadFile reads a JSON array of [Definition]s from the file at path, see [Load].
+func LoadFile(path string, optionOverrides ...func(*LoadOptions)) ([]*Definition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "jsonprovider: open definitions")
+	}
+	defer f.Close()
+
+	return Load(f, optionOverrides...)
+}
+
+// Validate returns an error if the definition is invalid, otherwise preparing it to be used by a
+// [Client]. [Load] validates the definitions it reads.
+func (d *Definition) Validate() error {
+	if d.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if d.URL == "" && d.CoordinatesURL == "" {
+		return errors.New("at least one of url & coordinates_url is required")
+	}
+
+	if d.Key != "" && d.Auth == nil &&
+		!strings.Contains(d.URL, placeholderKey) && !strings.Contains(d.CoordinatesURL, placeholderKey) {
+		return errors.New("key is set, but neither sent with auth nor in a url")
+	}
+
+	if err := d.validateURL("url", d.URL, placeholderCity); err != nil {
+		return err
+	}
+
+	if err := d.validateURL("coordinates_url", d.CoordinatesURL, placeholderLatitude, placeholderLongitude); err != nil {
+		return err
+	}
+
+	if d.Auth != nil {
+		if d.Auth.In != AuthInQuery && d.Auth.In != AuthInHeader {
+			return errors.Errorf("auth.in: %q is not one of %q or %q", d.Auth.In, AuthInQuery, AuthInHeader)
+		}
+
+		if d.Auth.Name == "" {
+			return errors.New("auth.name is required")
+		}
+
+		if d.Key == "" {
+			return errors.New("auth: key (or key_env) is required")
+		}
+	}
+
+	if err := d.validateFields(); err != nil {
+		return err
+	}
+
+	return d.validateError()
+}
+
+// validateURL returns an error if the URL template tmpl (the field name) is invalid, being
+// missing any of the required placeholders (if not empty) or having unknown placeholders.
+func (d *Definition) validateURL(name, tmpl string, required ...string) error {
+	if tmpl == "" {
+		return nil
+	}
+
+	for _, placeholder := range required {
+		if !strings.Contains(tmpl, placeholder) {
+			return errors.Errorf("%s: placeholder %s is required", name, placeholder)
+		}
+	}
+
+	allowed := append(required, placeholderKey)
+
+	for _, placeholder := range placeholderPattern.FindAllString(tmpl, -1) {
+		known := false
+
+		for _, a := range allowed {
+			known = known || placeholder == a
+		}
+
+		if !known {
+			return errors.Errorf("%s: unknown placeholder %s", name, placeholder)
+		}
+	}
+
+	if strings.Contains(tmpl, placeholderKey) && d.Key == "" {
+		return errors.Errorf("%s: key (or key_env) is required for placeholder %s", name, placeholderKey)
+	}
+
+	return nil
+}
+
+// validateFields returns an error if d.Fields are invalid, otherwise parsing their selectors.
+func (d *Definition) validateFields() error {
+	for _, required := range []string{FieldTemperature, FieldWindSpeed} {
+		if _, ok := d.Fields[required]; !ok {
+			return errors.Errorf("fields: %s is required", required)
+		}
+	}
+
+	d.selectors = make(map[string]selector, len(d.Fields))
+
+	// Sorted, such that errors are deterministic.
+	names := make([]string, 0, len(d.Fields))
+	for name := range d.Fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := d.Fields[name]
+
+		sel, err := parseSelector(field.Path)
+		if err != nil {
+			return errors.Wrapf(err, "fields: %s", name)
+		}
+
+		d.selectors[name] = sel
+
+		if err := validateFieldUnit(name, field); err != nil {
+			return errors.Wrapf(err, "fields: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// validateFieldUnit returns an error if field (the field name) has a unit it can't be converted
+// from, or a map it can't have.
+func validateFieldUnit(name string, field FieldDefinition) error {
+	if len(field.Map) > 0 && name != FieldCondition {
+		return errors.New("only the condition can have a map")
+	}
+
+	switch name {
+	case FieldCondition:
+		if field.Unit != "" {
+			return errors.New("the condition can't have a unit")
+		}
+
+		for value, code := range field.Map {
+			if !knownCondition(weather.ConditionCode(code)) {
+				return errors.Errorf("map: %q maps to unknown condition %q", value, code)
+			}
+		}
+	case FieldObservedAt:
+		if field.Unit != "" && field.Unit != TimeUnix && field.Unit != TimeUnixMilli && field.Unit != TimeRFC3339 {
+			return errors.Errorf("unit %q is not one of %q, %q or %q", field.Unit, TimeUnix, TimeUnixMilli, TimeRFC3339)
+		}
+	default:
+		to, ok := numericFields[name]
+		if !ok {
+			return errors.New("unknown field")
+		}
+
+		if field.Unit == "" {
+			return nil
+		}
+
+		from, ok := units[field.Unit]
+		if !ok {
+			return errors.Errorf("unknown unit %q", field.Unit)
+		}
+
+		if from.quantity != units[to].quantity {
+			return errors.Errorf("unit %q is not a unit of %s", field.Unit, units[to].quantity)
+		}
+	}
+
+	return nil
+}
+
+// validateError returns an error if d.Error is invalid, otherwise parsing its selectors.
+func (d *Definition) validateError() error {
+	if d.Error == nil {
+		return nil
+	}
+
+	for _, s := range []struct {
+		name string
+		path string
+		sel  *selector
+	}{
+		{"error.path", d.Error.Path, &d.errorSelector},
+		{"error.code_path", d.Error.CodePath, &d.codeSelector},
+		{"error.message_path", d.Error.MessagePath, &d.msgSelector},
+	} {
+		if s.path == "" {
+			continue
+		}
+
+		sel, err := parseSelector(s.path)
+		if err != nil {
+			return errors.Wrap(err, s.name)
+		}
+
+		*s.sel = sel
+	}
+
+	if d.Error.Equals != nil && d.Error.Path == "" {
+		return errors.New("error.equals: error.path is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package jsonprovider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	lookupEnv := WithLookupEnv(func(key string) (string, bool) {
+		if key == "EXAMPLE_API_KEY" {
+			return "abc123", true
+		}
+
+		return "", false
+	})
+
+	for _, tc := range []struct {
+		name        string
+		give        string
+		expectedErr string
+	}{
+		{
+			name: "success",
+			give: `[{
+				"name": "Example",
+				"url": "https://api.example.com/current?q={city}",
+				"coordinates_url": "https://api.example.com/current?lat={lat}&lon={lon}",
+				"key_env": "EXAMPLE_API_KEY",
+				"auth": {"in": "header", "name": "Authorization", "prefix": "Bearer "},
+				"fields": {
+					"temperature_degrees": {"path": "$.temp", "unit": "fahrenheit"},
+					"wind_speed": {"path": "$.wind", "unit": "mph"},
+					"condition": {"path": "$.weather[0].main", "map": {"Rain": "rain"}},
+					"observed_at": {"path": "$.time", "unit": "rfc3339"}
+				},
+				"error": {"path": "$.success", "equals": false, "code_path": "$.code"}
+			}]`,
+		},
+		{
+			name:        "invalid_json",
+			give:        `{`,
+			expectedErr: "jsonprovider: decode definitions: unexpected EOF",
+		},
+		{
+			name:        "unknown_field",
+			give:        `[{"name": "Example", "urls": []}]`,
+			expectedErr: `jsonprovider: decode definitions: json: unknown field "urls"`,
+		},
+		{
+			name:        "key_env_not_set",
+			give:        `[{"name": "Example", "key_env": "OTHER_API_KEY"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): key_env: environment variable OTHER_API_KEY is not set`,
+		},
+		{
+			name:        "no_name",
+			give:        `[{"url": "https://api.example.com/current?q={city}"}]`,
+			expectedErr: `jsonprovider: definition 0 (""): name is required`,
+		},
+		{
+			name:        "no_url",
+			give:        `[{"name": "Example"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): at least one of url & coordinates_url is required`,
+		},
+		{
+			name:        "key_unused",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "key": "abc123"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): key is set, but neither sent with auth nor in a url`,
+		},
+		{
+			name:        "url_without_city",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): url: placeholder {city} is required`,
+		},
+		{
+			name:        "url_unknown_placeholder",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}&lat={lat}"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): url: unknown placeholder {lat}`,
+		},
+		{
+			name:        "url_key_placeholder_without_key",
+			give:        `[{"name": "Example", "coordinates_url": "https://api.example.com/current?lat={lat}&lon={lon}&key={key}"}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): coordinates_url: key (or key_env) is required for placeholder {key}`,
+		},
+		{
+			name:        "auth_invalid_in",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "key": "abc123", "auth": {"in": "body", "name": "key"}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): auth.in: "body" is not one of "query" or "header"`,
+		},
+		{
+			name:        "required_field",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): fields: wind_speed is required`,
+		},
+		{
+			name:        "unknown_field_name",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind"}, "uv_index": {"path": "$.uv"}}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): fields: uv_index: unknown field`,
+		},
+		{
+			name:        "invalid_path",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp["}, "wind_speed": {"path": "$.wind"}}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): fields: temperature_degrees: jsonprovider: selector "$.temp[" has a malformed index`,
+		},
+		{
+			name:        "unit_of_other_quantity",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp", "unit": "mph"}, "wind_speed": {"path": "$.wind"}}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): fields: temperature_degrees: unit "mph" is not a unit of temperature`,
+		},
+		{
+			name:        "unknown_condition",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind"}, "condition": {"path": "$.weather", "map": {"Tornado": "tornado"}}}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): fields: condition: map: "Tornado" maps to unknown condition "tornado"`,
+		},
+		{
+			name:        "error_equals_without_path",
+			give:        `[{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind"}}, "error": {"equals": false}}]`,
+			expectedErr: `jsonprovider: definition 0 ("Example"): error.equals: error.path is required`,
+		},
+		{
+			name: "duplicate_name",
+			give: `[
+				{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind"}}},
+				{"name": "Example", "url": "https://api.example.com/current?q={city}", "fields": {"temperature_degrees": {"path": "$.temp"}, "wind_speed": {"path": "$.wind"}}}
+			]`,
+			expectedErr: `jsonprovider: definition 1: name "Example" is not unique`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Load(strings.NewReader(tc.give), lookupEnv)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, actual)
+			} else if assert.NoError(t, err) && assert.Len(t, actual, 1) {
+				assert.Equal(t, "abc123", actual[0].Key)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package jsonprovider provides a client to interact with a weather provider API returning JSON,
+// being configured by a [Definition] rather than code.
+//
+// A definition gives the URL templates to query by city & by coordinates, where the API key is
+// sent, where each [weather.Summary] field is found in a response (with a JSONPath-like
+// selector, see parseSelector) along with its unit, and how errors are detected. For example:
+//
+//	[
+//	  {
+//	    "name": "Example",
+//	    "url": "https://api.example.com/v1/current?q={city}",
+//	    "coordinates_url": "https://api.example.com/v1/current?lat={lat}&lon={lon}",
+//	    "key_env": "EXAMPLE_API_KEY",
+//	    "auth": {"in": "header", "name": "Authorization", "prefix": "Bearer "},
+//	    "fields": {
+//	      "temperature_degrees": {"path": "$.current.temp", "unit": "fahrenheit"},
+//	      "wind_speed": {"path": "$.current.wind.speed", "unit": "mph"},
+//	      "condition": {"path": "$.current.weather[0].main", "map": {"Clear": "clear", "Rain": "rain"}},
+//	      "observed_at": {"path": "$.current.dt", "unit": "unix"}
+//	    },
+//	    "error": {
+//	      "path": "$.error",
+//	      "code_path": "$.error.code",
+//	      "message_path": "$.error.message",
+//	      "location_not_found_codes": ["1006"],
+//	      "rejected_codes": ["401", "403", "429"]
+//	    }
+//	  }
+//	]
+//
+// The units supported are "celsius", "fahrenheit" & "kelvin" (temperatures), "km/h", "m/s", "mph"
+// & "knots" (speeds), "hPa", "mbar", "Pa", "kPa" & "inHg" (pressures), "mm", "cm", "m", "km", "in"
+// & "mi" (visibility & precipitation), "percent" & "fraction" (humidity & cloud cover) and
+// "degrees" (wind direction).
+package jsonprovider
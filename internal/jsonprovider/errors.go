@@ -0,0 +1,55 @@
+package jsonprovider
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of errors returned by the [Client], to be matched with errors.Is.
+var (
+	// ErrLocationNotFound is returned for an error with one of the
+	// [ErrorRule.LocationNotFoundCodes].
+	ErrLocationNotFound = errors.New("jsonprovider: location not found")
+
+	// ErrRejected is returned for an error with one of the [ErrorRule.RejectedCodes].
+	ErrRejected = errors.New("jsonprovider: rejected")
+
+	// ErrUnsupported is returned for a query the [Definition] has no URL for, e.g by
+	// coordinates without a [Definition.CoordinatesURL].
+	ErrUnsupported = errors.New("jsonprovider: query unsupported")
+)
+
+// APIError is an error returned by a provider, as detected by its [ErrorRule] (or an
+// unsuccessful status code).
+type APIError struct {
+	Provider   string // The name of the provider.
+	StatusCode int    // The response status code.
+	Code       string // The code of the error, defaulting to the status code.
+	Message    string // The message of the error, if any.
+
+	class error // One of the classes of error, or nil.
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("jsonprovider: %s: error response (status code %v", e.Provider, e.StatusCode)
+
+	if e.Code != "" {
+		msg += ", code " + e.Code
+	}
+
+	msg += ")"
+
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Is reports whether the error is of the class target, one of [ErrLocationNotFound] or
+// [ErrRejected].
+func (e *APIError) Is(target error) bool {
+	return e.class != nil && e.class == target
+}
@@ -0,0 +1,65 @@
+package jsonprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "location_not_found", give: &APIError{class: ErrLocationNotFound}, expected: ErrLocationNotFound},
+		{name: "rejected", give: &APIError{class: ErrRejected}, expected: ErrRejected},
+		{name: "unclassified", give: &APIError{StatusCode: http.StatusInternalServerError}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrLocationNotFound, ErrRejected, ErrUnsupported} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
+
+func TestAPIErrorError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected string
+	}{
+		{
+			name:     "status_code",
+			give:     &APIError{Provider: "Example", StatusCode: http.StatusBadGateway},
+			expected: "jsonprovider: Example: error response (status code 502)",
+		},
+		{
+			name:     "code_and_message",
+			give:     &APIError{Provider: "Example", StatusCode: http.StatusOK, Code: "1006", Message: "No matching location found."},
+			expected: "jsonprovider: Example: error response (status code 200, code 1006): No matching location found.",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.give.Error())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package jsonprovider
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// selectorStep is a step of a [selector]: a key of an object, or (if key is empty) an index of
+// an array.
+type selectorStep struct {
+	key   string
+	index int
+}
+
+// selector selects a value of a decoded JSON document, see parseSelector.
+type selector []selectorStep
+
+// parseSelector parses a JSONPath-like selector: keys separated by dots, each optionally followed
+// by array indexes, with an optional leading "$" for the root. For example
+// "$.weather[0].description" or "current.temp".
+func parseSelector(s string) (selector, error) {
+	path := strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	if path == "" {
+		if s == "$" {
+			return selector{}, nil
+		}
+
+		return nil, errors.New("jsonprovider: selector is empty")
+	}
+
+	var sel selector
+
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		indexes := ""
+
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			key, indexes = part[:i], part[i:]
+		}
+
+		if key == "" && (indexes == "" || len(sel) > 0) {
+			return nil, errors.Errorf("jsonprovider: selector %q has an empty key", s)
+		}
+
+		if key != "" {
+			sel = append(sel, selectorStep{key: key})
+		}
+
+		for indexes != "" {
+			end := strings.IndexByte(indexes, ']')
+			if indexes[0] != '[' || end < 0 {
+				return nil, errors.Errorf("jsonprovider: selector %q has a malformed index", s)
+			}
+
+			index, err := strconv.Atoi(indexes[1:end])
+			if err != nil || index < 0 {
+				return nil, errors.Errorf("jsonprovider: selector %q has an invalid index %q", s, indexes[1:end])
+			}
+
+			sel = append(sel, selectorStep{index: index})
+			indexes = indexes[end+1:]
+		}
+	}
+
+	return sel, nil
+}
+
+// selectFrom returns the value selected from doc (as decoded by encoding/json into an
+// interface{}), reporting whether there is one. A JSON null is treated as no value.
+func (sel selector) selectFrom(doc interface{}) (interface{}, bool) {
+	v := doc
+
+	for _, step := range sel {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			if step.key == "" {
+				return nil, false
+			}
+
+			v = node[step.key]
+		case []interface{}:
+			if step.key != "" || step.index >= len(node) {
+				return nil, false
+			}
+
+			v = node[step.index]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, v != nil
+}
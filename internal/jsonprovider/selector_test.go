@@ -0,0 +1,87 @@
+package jsonprovider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        string
+		expected    selector
+		expectedErr string
+	}{
+		{name: "root", give: "$", expected: selector{}},
+		{name: "key", give: "temp", expected: selector{{key: "temp"}}},
+		{name: "keys_with_root", give: "$.current.temp", expected: selector{{key: "current"}, {key: "temp"}}},
+		{name: "index", give: "$.weather[0].main", expected: selector{{key: "weather"}, {index: 0}, {key: "main"}}},
+		{name: "indexes", give: "$.data[1][2]", expected: selector{{key: "data"}, {index: 1}, {index: 2}}},
+		{name: "root_index", give: "$[0].temp", expected: selector{{index: 0}, {key: "temp"}}},
+		{name: "empty", give: "", expectedErr: "jsonprovider: selector is empty"},
+		{name: "empty_key", give: "$.current..temp", expectedErr: `jsonprovider: selector "$.current..temp" has an empty key`},
+		{name: "malformed_index", give: "$.weather[0", expectedErr: `jsonprovider: selector "$.weather[0" has a malformed index`},
+		{name: "invalid_index", give: "$.weather[-1]", expectedErr: `jsonprovider: selector "$.weather[-1]" has an invalid index "-1"`},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parseSelector(tc.give)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSelectorSelectFrom(t *testing.T) {
+	t.Parallel()
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"current":{"temp":12.5,"gust":null},"weather":[{"main":"Rain"}]}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		give       string
+		expected   interface{}
+		expectedOk bool
+	}{
+		{name: "number", give: "$.current.temp", expected: 12.5, expectedOk: true},
+		{name: "array_element", give: "$.weather[0].main", expected: "Rain", expectedOk: true},
+		{name: "null", give: "$.current.gust"},
+		{name: "missing_key", give: "$.current.humidity"},
+		{name: "index_out_of_range", give: "$.weather[1].main"},
+		{name: "index_of_object", give: "$.current[0]"},
+		{name: "key_of_array", give: "$.weather.main"},
+		{name: "key_of_number", give: "$.current.temp.value"},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sel, err := parseSelector(tc.give)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, ok := sel.selectFrom(doc)
+
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
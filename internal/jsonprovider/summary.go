@@ -0,0 +1,152 @@
+package jsonprovider
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/byatesrae/weather"
+)
+
+// toSummary reads a [weather.Summary] from the (decoded) body of a successful response.
+func (d *Definition) toSummary(doc interface{}) (*weather.Summary, error) {
+	summary := weather.Summary{}
+
+	for name, field := range d.Fields {
+		v, ok := d.selectors[name].selectFrom(doc)
+		if !ok {
+			if name == FieldTemperature || name == FieldWindSpeed {
+				return nil, errors.Errorf("jsonprovider: %s: response has no %s", d.Name, name)
+			}
+
+			continue
+		}
+
+		var err error
+
+		switch name {
+		case FieldCondition:
+			summary.Condition = toCondition(v, field.Map)
+		case FieldObservedAt:
+			var observedAt time.Time
+			if observedAt, err = toTime(v, field.Unit); err == nil {
+				summary.ObservedAt = &observedAt
+			}
+		default:
+			var f float64
+			if f, err = toFloat(v); err == nil {
+				setNumericField(&summary, name, convertField(f, name, field.Unit))
+			}
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "jsonprovider: %s: %s", d.Name, name)
+		}
+	}
+
+	return &summary, nil
+}
+
+// convertField converts v from unit to the unit of the numeric field name. An empty unit is the
+// unit of the field.
+func convertField(v float64, name, unit string) float64 {
+	to := numericFields[name]
+	if unit == "" {
+		unit = to
+	}
+
+	return convert(v, unit, to)
+}
+
+// setNumericField sets the numeric field name of summary to v.
+func setNumericField(summary *weather.Summary, name string, v float64) {
+	switch name {
+	case FieldTemperature:
+		summary.Temperature = v
+	case FieldWindSpeed:
+		summary.WindSpeed = v
+	case FieldFeelsLike:
+		summary.FeelsLike = &v
+	case FieldHumidity:
+		summary.Humidity = &v
+	case FieldPressure:
+		summary.Pressure = &v
+	case FieldCloudCover:
+		summary.CloudCover = &v
+	case FieldVisibility:
+		summary.Visibility = &v
+	case FieldWindDirection:
+		summary.SetWindDirection(v)
+	case FieldWindGust:
+		summary.WindGust = &v
+	case FieldPrecipitation:
+		summary.Precipitation = &v
+	}
+}
+
+// toCondition returns the condition of v, translated with conditionMap if not empty. A value that
+// isn't a known condition is unknown.
+func toCondition(v interface{}, conditionMap map[string]string) *weather.Condition {
+	code := weather.ConditionCode(stringValue(v))
+
+	if len(conditionMap) > 0 {
+		code = weather.ConditionCode(conditionMap[string(code)])
+	}
+
+	if !knownCondition(code) {
+		code = weather.ConditionUnknown
+	}
+
+	return weather.NewCondition(code)
+}
+
+// knownCondition reports whether code is one of the supported [weather.ConditionCode]s.
+func knownCondition(code weather.ConditionCode) bool {
+	return code == weather.ConditionUnknown || code.Description() != weather.ConditionUnknown.Description()
+}
+
+// toTime returns v as a time (in UTC), being in the format unit (one of the time units, defaulting
+// to "unix").
+func toTime(v interface{}, unit string) (time.Time, error) {
+	if unit == TimeRFC3339 {
+		s, ok := v.(string)
+		if !ok {
+			return time.Time{}, errors.Errorf("%v is not a string", v)
+		}
+
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return time.Time{}, errors.Wrap(err, "parse time")
+		}
+
+		return t.UTC(), nil
+	}
+
+	f, err := toFloat(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if unit == TimeUnixMilli {
+		return time.UnixMilli(int64(f)).UTC(), nil
+	}
+
+	sec, frac := math.Modf(f)
+
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+}
+
+// stringValue returns v (as decoded by encoding/json) as a string, e.g 1006 for the number 1006.
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,90 @@
+package jsonprovider
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// quantity is a kind of measurement.
+type quantity string
+
+// All the quantities of [weather.Summary] fields.
+const (
+	quantityTemperature quantity = "temperature"
+	quantitySpeed       quantity = "speed"
+	quantityPressure    quantity = "pressure"
+	quantityLength      quantity = "length"
+	quantityProportion  quantity = "proportion"
+	quantityAngle       quantity = "angle"
+)
+
+// unit is a unit of a quantity, with the conversion of its values to the quantity's base unit:
+// base = value*factor + offset. Base units are chosen such that common conversions are exact.
+type unit struct {
+	quantity quantity
+	factor   float64
+	offset   float64
+}
+
+// units are the units a field can be configured with, keyed by name.
+var units = map[string]unit{
+	"celsius":    {quantity: quantityTemperature, factor: 1},
+	"fahrenheit": {quantity: quantityTemperature, factor: 5.0 / 9.0, offset: -160.0 / 9.0},
+	"kelvin":     {quantity: quantityTemperature, factor: 1, offset: -273.15},
+
+	"km/h":  {quantity: quantitySpeed, factor: 1000}, // base m/h
+	"m/s":   {quantity: quantitySpeed, factor: 3600},
+	"mph":   {quantity: quantitySpeed, factor: 1609.344},
+	"knots": {quantity: quantitySpeed, factor: 1852},
+
+	"hPa":  {quantity: quantityPressure, factor: 100}, // base Pa
+	"mbar": {quantity: quantityPressure, factor: 100},
+	"Pa":   {quantity: quantityPressure, factor: 1},
+	"kPa":  {quantity: quantityPressure, factor: 1000},
+	"inHg": {quantity: quantityPressure, factor: 3386.389},
+
+	"mm": {quantity: quantityLength, factor: 1}, // base mm
+	"cm": {quantity: quantityLength, factor: 10},
+	"m":  {quantity: quantityLength, factor: 1000},
+	"km": {quantity: quantityLength, factor: 1000000},
+	"in": {quantity: quantityLength, factor: 25.4},
+	"mi": {quantity: quantityLength, factor: 1609344},
+
+	"percent":  {quantity: quantityProportion, factor: 1},
+	"fraction": {quantity: quantityProportion, factor: 100}, // 0 to 1
+
+	"degrees": {quantity: quantityAngle, factor: 1},
+}
+
+// convert converts v from the unit named from to the unit named to, which must be units of the
+// same quantity.
+func convert(v float64, from, to string) float64 {
+	if from == to {
+		return v
+	}
+
+	f, t := units[from], units[to]
+
+	return (v*f.factor + f.offset - t.offset) / t.factor
+}
+
+// toFloat returns v (as decoded by encoding/json) as a number, being either a JSON number or a
+// string holding one. A string holding a non-finite number (e.g "NaN" or "Inf") isn't a number,
+// as it couldn't be encoded back to JSON.
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, errors.Errorf("jsonprovider: %q is not a number", n)
+		}
+
+		return f, nil
+	default:
+		return 0, errors.Errorf("jsonprovider: %v is not a number", v)
+	}
+}
@@ -0,0 +1,73 @@
+package jsonprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     float64
+		giveFrom string
+		giveTo   string
+		expected float64
+	}{
+		{name: "identity", give: 12.5, giveFrom: "celsius", giveTo: "celsius", expected: 12.5},
+		{name: "fahrenheit", give: 212, giveFrom: "fahrenheit", giveTo: "celsius", expected: 100},
+		{name: "kelvin", give: 273.15, giveFrom: "kelvin", giveTo: "celsius", expected: 0},
+		{name: "metres_per_second", give: 10, giveFrom: "m/s", giveTo: "km/h", expected: 36},
+		{name: "miles_per_hour", give: 10, giveFrom: "mph", giveTo: "km/h", expected: 16.09344},
+		{name: "knots", give: 10, giveFrom: "knots", giveTo: "km/h", expected: 18.52},
+		{name: "pascals", give: 101325, giveFrom: "Pa", giveTo: "hPa", expected: 1013.25},
+		{name: "metres", give: 10000, giveFrom: "m", giveTo: "km", expected: 10},
+		{name: "inches", give: 1, giveFrom: "in", giveTo: "mm", expected: 25.4},
+		{name: "fraction", give: 0.75, giveFrom: "fraction", giveTo: "percent", expected: 75},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, tc.expected, convert(tc.give, tc.giveFrom, tc.giveTo), 1e-9)
+		})
+	}
+}
+
+func TestToFloat(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        interface{}
+		expected    float64
+		expectedErr string
+	}{
+		{name: "number", give: 12.5, expected: 12.5},
+		{name: "string", give: "12.5", expected: 12.5},
+		{name: "invalid_string", give: "N/A", expectedErr: `jsonprovider: "N/A" is not a number`},
+		{name: "nan_string", give: "NaN", expectedErr: `jsonprovider: "NaN" is not a number`},
+		{name: "infinity_string", give: "-Infinity", expectedErr: `jsonprovider: "-Infinity" is not a number`},
+		{name: "inf_string", give: "Inf", expectedErr: `jsonprovider: "Inf" is not a number`},
+		{name: "bool", give: true, expectedErr: "jsonprovider: true is not a number"},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := toFloat(tc.give)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,53 +1,337 @@
-// Package memorycache contains a very simple in-memory cache.
+// Package memorycache contains a bounded in-memory cache.
 package memorycache
 
 import (
+	"container/list"
 	"context"
+	"fmt"
+	"hash/fnv"
 	"sync"
 	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
 )
 
 // cacheEntry represents a value cached.
 type cacheEntry struct {
+	key    interface{}
 	val    interface{}
 	expiry time.Time
 }
 
-// MemoryCache is a very simple in-memory cache that is safe for concurrent access.
-type MemoryCache struct {
-	values map[interface{}]*cacheEntry
+// shard is a portion of the cache, with its own lock & least recently used order.
+type shard struct {
+	values     map[interface{}]*list.Element // Elements hold *cacheEntry.
+	recency    *list.List                    // Most recently used at the front.
+	maxEntries int                           // <= 0 is unbounded.
 
 	m sync.Mutex
 }
 
+// NewOptions are the options for the [New] function.
+type NewOptions struct {
+	maxEntries      int
+	shards          int
+	expiryGrace     time.Duration
+	janitorInterval time.Duration
+	metrics         *Metrics
+	now             func() time.Time
+
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+}
+
+// WithMaxEntries sets the maximum number of entries cached, past which the least recently
+// used entries are evicted. The maximum is spread across shards (see [WithShards]), so is
+// approximate. A maximum <= 0 is unbounded.
+func WithMaxEntries(maxEntries int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// WithShards sets the number of shards entries are spread across, each with its own lock to
+// reduce contention.
+func WithShards(shards int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.shards = shards
+	}
+}
+
+// WithExpiryGrace sets how long entries are kept after their expiry (e.g. to be served stale),
+// before being removed.
+func WithExpiryGrace(expiryGrace time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.expiryGrace = expiryGrace
+	}
+}
+
+// WithJanitorInterval sets how often the janitor removes entries past their expiry grace, see
+// [MemoryCache.RunJanitor].
+func WithJanitorInterval(janitorInterval time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.janitorInterval = janitorInterval
+	}
+}
+
+// WithMetrics sets the metrics captured by the cache, see [NewMetrics].
+func WithMetrics(metrics *Metrics) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
+// the context.
+func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.getLoggerFromContext = getLoggerFromContext
+	}
+}
+
+// withNow sets the function used to get the current time in the New function.
+func withNow(now func() time.Time) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.now = now
+	}
+}
+
+// MemoryCache is an in-memory cache that is safe for concurrent access. It's bounded by a
+// maximum number of entries, evicting the least recently used entries, and entries are
+// removed once an expiry grace period has passed since their expiry.
+//
+// Keys implementing [fmt.Stringer] are stored by their string form, such that they match the
+// keys restored from a snapshot (see [MemoryCache.LoadSnapshot]).
+type MemoryCache struct {
+	shards          []*shard
+	expiryGrace     time.Duration
+	janitorInterval time.Duration
+	metrics         *Metrics
+	now             func() time.Time
+
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+}
+
 // New creates a new [MemoryCache].
-func New() *MemoryCache {
+func New(overrides ...func(o *NewOptions)) *MemoryCache {
+	noopLogger := nooplogr.New()
+
+	options := NewOptions{
+		maxEntries:      10000,
+		shards:          16,
+		expiryGrace:     time.Hour,
+		janitorInterval: time.Minute,
+		now:             time.Now,
+		getLoggerFromContext: func(ctx context.Context) logr.Logger {
+			return noopLogger
+		},
+	}
+
+	for _, override := range overrides {
+		override(&options)
+	}
+
+	shardCount := options.shards
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	if options.maxEntries > 0 && shardCount > options.maxEntries {
+		shardCount = options.maxEntries
+	}
+
+	shardMaxEntries := 0
+	if options.maxEntries > 0 {
+		shardMaxEntries = (options.maxEntries + shardCount - 1) / shardCount
+	}
+
+	shards := make([]*shard, shardCount)
+	for i := range shards {
+		shards[i] = &shard{
+			values:     make(map[interface{}]*list.Element),
+			recency:    list.New(),
+			maxEntries: shardMaxEntries,
+		}
+	}
+
 	return &MemoryCache{
-		values: make(map[interface{}]*cacheEntry),
+		shards:          shards,
+		expiryGrace:     options.expiryGrace,
+		janitorInterval: options.janitorInterval,
+		metrics:         options.metrics,
+		now:             options.now,
+
+		getLoggerFromContext: options.getLoggerFromContext,
 	}
 }
 
-// Get retrieves a value from cache as well as the time it expires.
+// Get retrieves a value from cache as well as the time it expires. Entries past their expiry
+// grace are not retrieved.
 func (m *MemoryCache) Get(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
-	m.m.Lock()
-	defer m.m.Unlock()
+	key = normalizeKey(key)
+	s := m.shard(key)
 
-	entry, ok := m.values[key]
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	element, ok := s.values[key]
 	if !ok {
+		m.metrics.recordMiss(ctx)
+
 		return nil, time.Time{}, nil
 	}
 
+	entry := element.Value.(*cacheEntry) // Should never panic
+
+	if m.isPastGrace(entry) {
+		s.remove(element)
+		m.metrics.recordEviction(ctx, evictionReasonExpired)
+		m.metrics.recordMiss(ctx)
+
+		return nil, time.Time{}, nil
+	}
+
+	s.recency.MoveToFront(element)
+	m.metrics.recordHit(ctx)
+
 	return entry.val, entry.expiry, nil
 }
 
-// Set will set a value to be cached as well as an expiry.
-// Cache entries are not automatically evicted based on their expiry.
+// Set will set a value to be cached as well as an expiry. Should the cache be full, the least
+// recently used entry is evicted.
 func (m *MemoryCache) Set(ctx context.Context, key, val interface{}, expiry time.Time) error {
-	m.m.Lock()
-	defer m.m.Unlock()
+	key = normalizeKey(key)
+	s := m.shard(key)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if element, ok := s.values[key]; ok {
+		entry := element.Value.(*cacheEntry) // Should never panic
+		entry.val, entry.expiry = val, expiry
+
+		s.recency.MoveToFront(element)
+
+		return nil
+	}
 
-	entry := cacheEntry{val: val, expiry: expiry}
-	m.values[key] = &entry
+	s.values[key] = s.recency.PushFront(&cacheEntry{key: key, val: val, expiry: expiry})
+
+	for s.maxEntries > 0 && s.recency.Len() > s.maxEntries {
+		s.remove(s.recency.Back())
+		m.metrics.recordEviction(ctx, evictionReasonCapacity)
+	}
 
 	return nil
 }
+
+// Delete removes the value cached for key, if any.
+func (m *MemoryCache) Delete(ctx context.Context, key interface{}) error {
+	key = normalizeKey(key)
+	s := m.shard(key)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if element, ok := s.values[key]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// Purge removes all cached values.
+func (m *MemoryCache) Purge(ctx context.Context) error {
+	for _, s := range m.shards {
+		s.m.Lock()
+		s.values = make(map[interface{}]*list.Element)
+		s.recency.Init()
+		s.m.Unlock()
+	}
+
+	return nil
+}
+
+// Len returns the number of cached values, including those past expiry not yet removed.
+func (m *MemoryCache) Len() int {
+	n := 0
+
+	for _, s := range m.shards {
+		s.m.Lock()
+		n += s.recency.Len()
+		s.m.Unlock()
+	}
+
+	return n
+}
+
+// RunJanitor removes entries past their expiry grace (see [WithExpiryGrace]) every janitor
+// interval (see [WithJanitorInterval]) until ctx is done.
+func (m *MemoryCache) RunJanitor(ctx context.Context) {
+	ticker := time.NewTicker(m.janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.removePastGrace(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// removePastGrace removes all entries past their expiry grace.
+func (m *MemoryCache) removePastGrace(ctx context.Context) {
+	for _, s := range m.shards {
+		s.m.Lock()
+
+		for _, element := range s.values {
+			if m.isPastGrace(element.Value.(*cacheEntry)) { // Should never panic
+				s.remove(element)
+				m.metrics.recordEviction(ctx, evictionReasonExpired)
+			}
+		}
+
+		s.m.Unlock()
+	}
+}
+
+// isPastGrace reports whether entry is past its expiry grace. Entries without an expiry never
+// are.
+func (m *MemoryCache) isPastGrace(entry *cacheEntry) bool {
+	return !entry.expiry.IsZero() && m.now().After(entry.expiry.Add(m.expiryGrace))
+}
+
+// shard returns the shard key belongs to.
+func (m *MemoryCache) shard(key interface{}) *shard {
+	if len(m.shards) == 1 {
+		return m.shards[0]
+	}
+
+	h := fnv.New32a()
+
+	if k, ok := key.(string); ok {
+		h.Write([]byte(k))
+	} else {
+		fmt.Fprintf(h, "%#v", key)
+	}
+
+	return m.shards[h.Sum32()%uint32(len(m.shards))]
+}
+
+// normalizeKey returns the form key is stored by.
+func normalizeKey(key interface{}) interface{} {
+	if k, ok := key.(fmt.Stringer); ok {
+		return k.String()
+	}
+
+	return key
+}
+
+// remove removes element from the shard. The shard must be locked.
+func (s *shard) remove(element *list.Element) {
+	s.recency.Remove(element)
+	delete(s.values, element.Value.(*cacheEntry).key) // Should never panic
+}
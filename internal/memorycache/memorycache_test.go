@@ -2,6 +2,8 @@ package memorycache
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -41,3 +43,154 @@ func TestMemoryCacheGet(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestMemoryCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		give     func(ctx context.Context, m *MemoryCache)
+		expected map[string]interface{} // The value of each key afterwards (nil if evicted).
+	}{
+		{
+			name: "least_recently_used",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(time.Minute))
+				m.Set(ctx, "b", 2, now.Add(time.Minute))
+				m.Get(ctx, "a")
+				m.Set(ctx, "c", 3, now.Add(time.Minute))
+			},
+			expected: map[string]interface{}{"a": 1, "b": nil, "c": 3},
+		},
+		{
+			name: "updated",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(time.Minute))
+				m.Set(ctx, "b", 2, now.Add(time.Minute))
+				m.Set(ctx, "a", 4, now.Add(time.Minute))
+				m.Set(ctx, "c", 3, now.Add(time.Minute))
+			},
+			expected: map[string]interface{}{"a": 4, "b": nil, "c": 3},
+		},
+		{
+			name: "past_expiry_grace",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(-time.Second*30))
+				m.Set(ctx, "b", 2, now.Add(-time.Minute*2))
+			},
+			expected: map[string]interface{}{"a": 1, "b": nil},
+		},
+		{
+			name: "deleted",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(time.Minute))
+				m.Set(ctx, "b", 2, now.Add(time.Minute))
+				m.Delete(ctx, "a")
+			},
+			expected: map[string]interface{}{"a": nil, "b": 2},
+		},
+		{
+			name: "purged",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(time.Minute))
+				m.Set(ctx, "b", 2, now.Add(time.Minute))
+				m.Purge(ctx)
+			},
+			expected: map[string]interface{}{"a": nil, "b": nil},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			m := New(
+				WithMaxEntries(2),
+				WithShards(1),
+				WithExpiryGrace(time.Minute),
+				withNow(func() time.Time { return now }),
+			)
+
+			tc.give(ctx, m)
+
+			for key, expected := range tc.expected {
+				actual, _, err := m.Get(ctx, key)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, actual, "value of %s", key)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheRunJanitor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	m := New(
+		WithExpiryGrace(time.Minute),
+		WithJanitorInterval(time.Millisecond*10),
+		withNow(func() time.Time { return now }),
+	)
+
+	m.Set(ctx, "a", 1, now.Add(-time.Second*30))
+	m.Set(ctx, "b", 2, now.Add(-time.Minute*2))
+	m.Set(ctx, "c", 3, time.Time{})
+	assert.Equal(t, 3, m.Len())
+
+	done := make(chan struct{})
+	go func() {
+		m.RunJanitor(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return m.Len() == 2 }, time.Second, time.Millisecond*10)
+
+	cancel()
+	<-done
+
+	val, _, _ := m.Get(context.Background(), "a")
+	assert.Equal(t, 1, val)
+}
+
+func TestMemoryCacheConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	m := New(WithMaxEntries(100), WithShards(4))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				key := fmt.Sprintf("%v-%v", i, j%200)
+
+				m.Set(ctx, key, j, time.Now().Add(time.Minute))
+				m.Get(ctx, key)
+
+				if j%100 == 0 {
+					m.Delete(ctx, key)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, m.Len(), 100)
+}
@@ -0,0 +1,105 @@
+package memorycache
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+const (
+	// hitCountName is the name of the metric used to record the number of cache hits.
+	hitCountName = "memorycache_hit_count"
+
+	// hitCountDesc is the description of the metric used to record the number of cache hits.
+	hitCountDesc = "The number of memory cache gets that found a value."
+
+	// missCountName is the name of the metric used to record the number of cache misses.
+	missCountName = "memorycache_miss_count"
+
+	// missCountDesc is the description of the metric used to record the number of cache
+	// misses.
+	missCountDesc = "The number of memory cache gets that found no value."
+
+	// evictionCountName is the name of the metric used to record the number of cache
+	// evictions.
+	evictionCountName = "memorycache_eviction_count"
+
+	// evictionCountDesc is the description of the metric used to record the number of cache
+	// evictions.
+	evictionCountDesc = "The number of memory cache entries evicted."
+
+	// reasonAttributeKey will be the key used to attach to metrics the reason an entry was
+	// evicted.
+	reasonAttributeKey = "reason"
+)
+
+// The reasons an entry is evicted.
+const (
+	evictionReasonCapacity = "capacity" // The cache was full & the entry least recently used.
+	evictionReasonExpired  = "expired"  // The entry was past its expiry grace.
+)
+
+// Metrics captures otel metrics for a [MemoryCache], see [WithMetrics]. Metrics include:
+//   - memorycache_hit_count
+//   - memorycache_miss_count
+//   - memorycache_eviction_count
+type Metrics struct {
+	hitCount      syncint64.Counter
+	missCount     syncint64.Counter
+	evictionCount syncint64.Counter
+}
+
+// NewMetrics creates the instruments used to capture [MemoryCache] metrics with meter.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	hitCount, err := meter.SyncInt64().Counter(hitCountName, instrument.WithDescription(hitCountDesc))
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", hitCountName, err)
+	}
+
+	missCount, err := meter.SyncInt64().Counter(missCountName, instrument.WithDescription(missCountDesc))
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", missCountName, err)
+	}
+
+	evictionCount, err := meter.SyncInt64().Counter(evictionCountName, instrument.WithDescription(evictionCountDesc))
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", evictionCountName, err)
+	}
+
+	return &Metrics{
+		hitCount:      hitCount,
+		missCount:     missCount,
+		evictionCount: evictionCount,
+	}, nil
+}
+
+// recordHit records a get that found a value.
+func (m *Metrics) recordHit(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.hitCount.Add(ctx, 1)
+}
+
+// recordMiss records a get that found no value.
+func (m *Metrics) recordMiss(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.missCount.Add(ctx, 1)
+}
+
+// recordEviction records an entry being evicted for reason.
+func (m *Metrics) recordEviction(ctx context.Context, reason string) {
+	if m == nil {
+		return
+	}
+
+	m.evictionCount.Add(ctx, 1, attribute.String(reasonAttributeKey, reason))
+}
@@ -0,0 +1,100 @@
+package memorycache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	selector "go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	metricController := controller.New(
+		processor.NewFactory(
+			selector.NewWithInexpensiveDistribution(),
+			aggregation.CumulativeTemporalitySelector(),
+			processor.WithMemory(true),
+		),
+		controller.WithCollectPeriod(0),
+	)
+
+	metrics, err := NewMetrics(metricController.Meter("Test123"))
+	require.NoError(t, err, "create metrics")
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	m := New(
+		WithMaxEntries(1),
+		WithExpiryGrace(time.Minute),
+		WithMetrics(metrics),
+		withNow(func() time.Time { return now }),
+	)
+
+	ctx := context.Background()
+
+	// Do
+	m.Set(ctx, "a", 1, now.Add(-time.Hour))
+	m.Get(ctx, "a") // Miss, evicted as expired.
+	m.Set(ctx, "b", 2, now.Add(time.Minute))
+	m.Get(ctx, "b")                          // Hit.
+	m.Set(ctx, "c", 3, now.Add(time.Minute)) // Evicts b.
+	m.Get(ctx, "b")                          // Miss.
+
+	// Assert
+	require.NoError(t, metricController.Collect(context.Background()), "collect metrics")
+
+	assert.Equal(
+		t,
+		map[string]int64{
+			`memorycache_hit_count{}`:                     1,
+			`memorycache_miss_count{}`:                    2,
+			`memorycache_eviction_count{reason=capacity}`: 1,
+			`memorycache_eviction_count{reason=expired}`:  1,
+		},
+		collectInt64Records(t, metricController),
+	)
+}
+
+// collectInt64Records returns the value of each int64 sum record in metricController, keyed by
+// the record's name & attributes.
+func collectInt64Records(t *testing.T, metricController *controller.Controller) map[string]int64 {
+	t.Helper()
+
+	records := make(map[string]int64)
+
+	err := metricController.ForEach(func(_ instrumentation.Library, exportReader export.Reader) error {
+		return exportReader.ForEach(
+			aggregation.CumulativeTemporalitySelector(),
+			func(record export.Record) error {
+				attributes := record.Attributes()
+				key := record.Descriptor().Name() + "{" + attributes.Encoded(attribute.DefaultEncoder()) + "}"
+
+				sum, ok := record.Aggregation().(aggregation.Sum)
+				if !ok {
+					t.Fatalf("unsupported aggregator: %s", record.Aggregation().Kind())
+				}
+
+				num, err := sum.Sum()
+				require.NoError(t, err, "get sum")
+
+				records[key] = num.AsInt64()
+
+				return nil
+			},
+		)
+	})
+	require.NoError(t, err, "iterate metric records")
+
+	return records
+}
@@ -0,0 +1,221 @@
+package memorycache
+
+import (
+	"bufio"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// snapshotVersion is the version of the snapshot format written, snapshots of other versions
+// are not read.
+const snapshotVersion = 1
+
+// snapshotHeader is the first line of a snapshot.
+type snapshotHeader struct {
+	Version int `json:"version"`
+}
+
+// snapshotEntry is a line of a snapshot, following the header.
+type snapshotEntry struct {
+	Key    string    `json:"key"`
+	Value  []byte    `json:"value"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// WriteSnapshot writes the entries cached to w, as JSON lines following a versioned header.
+// Only entries with a string (or [fmt.Stringer]) key and a []byte (or
+// [encoding.BinaryMarshaler]) value are written, others are skipped. Returns the number of
+// entries written.
+func (m *MemoryCache) WriteSnapshot(w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion}); err != nil {
+		return 0, errors.Wrap(err, "memorycache: write snapshot header")
+	}
+
+	n := 0
+
+	for _, entry := range m.snapshotEntries() {
+		if err := encoder.Encode(entry); err != nil {
+			return n, errors.Wrap(err, "memorycache: write snapshot entry")
+		}
+
+		n++
+	}
+
+	if err := bw.Flush(); err != nil {
+		return n, errors.Wrap(err, "memorycache: write snapshot")
+	}
+
+	return n, nil
+}
+
+// snapshotEntries returns the entries that can be written to a snapshot. Each shard's entries
+// are returned from least to most recently used, such that restoring them in order (each
+// becoming the most recently used) keeps their recency.
+func (m *MemoryCache) snapshotEntries() []snapshotEntry {
+	var entries []snapshotEntry
+
+	for _, s := range m.shards {
+		s.m.Lock()
+
+		for element := s.recency.Back(); element != nil; element = element.Prev() {
+			entry := element.Value.(*cacheEntry) // Should never panic
+
+			key, ok := entry.key.(string)
+			if !ok || m.isPastGrace(entry) {
+				continue
+			}
+
+			var value []byte
+
+			switch v := entry.val.(type) {
+			case []byte:
+				value = v
+			case encoding.BinaryMarshaler:
+				data, err := v.MarshalBinary()
+				if err != nil {
+					continue
+				}
+
+				value = data
+			default:
+				continue
+			}
+
+			entries = append(entries, snapshotEntry{Key: key, Value: value, Expiry: entry.expiry})
+		}
+
+		s.m.Unlock()
+	}
+
+	return entries
+}
+
+// ReadSnapshot restores the entries of a snapshot read from r, as written by
+// [MemoryCache.WriteSnapshot]. Values are restored as []byte. Entries past their expiry grace,
+// or more than maxStaleness past their expiry (if maxStaleness > 0), are skipped. Returns the
+// number of entries restored.
+func (m *MemoryCache) ReadSnapshot(ctx context.Context, r io.Reader, maxStaleness time.Duration) (int, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return 0, errors.Wrap(err, "memorycache: read snapshot header")
+	}
+
+	if header.Version != snapshotVersion {
+		return 0, fmt.Errorf("memorycache: read snapshot: unsupported version %d", header.Version)
+	}
+
+	now := m.now()
+	n := 0
+
+	for {
+		var entry snapshotEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, errors.Wrap(err, "memorycache: read snapshot entry")
+		}
+
+		if !entry.Expiry.IsZero() {
+			if now.After(entry.Expiry.Add(m.expiryGrace)) {
+				continue
+			}
+
+			if maxStaleness > 0 && now.After(entry.Expiry.Add(maxStaleness)) {
+				continue
+			}
+		}
+
+		if err := m.Set(ctx, entry.Key, entry.Value, entry.Expiry); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+}
+
+// SaveSnapshot writes a snapshot (see [MemoryCache.WriteSnapshot]) to the file at path. The
+// file is replaced atomically, such that a crash mid-write leaves the previous snapshot intact.
+func (m *MemoryCache) SaveSnapshot(path string) (int, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, errors.Wrap(err, "memorycache: create snapshot file")
+	}
+
+	defer os.Remove(f.Name()) // No-op once renamed.
+
+	n, err := m.WriteSnapshot(f)
+	if err != nil {
+		f.Close()
+
+		return 0, err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+
+		return 0, errors.Wrap(err, "memorycache: sync snapshot file")
+	}
+
+	if err := f.Close(); err != nil {
+		return 0, errors.Wrap(err, "memorycache: close snapshot file")
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return 0, errors.Wrap(err, "memorycache: replace snapshot file")
+	}
+
+	return n, nil
+}
+
+// LoadSnapshot restores the entries of the snapshot file at path (see
+// [MemoryCache.ReadSnapshot]). A missing file restores nothing, rather than erroring.
+func (m *MemoryCache) LoadSnapshot(ctx context.Context, path string, maxStaleness time.Duration) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Wrap(err, "memorycache: open snapshot file")
+	}
+
+	defer f.Close()
+
+	return m.ReadSnapshot(ctx, f, maxStaleness)
+}
+
+// RunSnapshots saves a snapshot to the file at path (see [MemoryCache.SaveSnapshot]) every
+// interval until ctx is done.
+func (m *MemoryCache) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	logger := m.getLoggerFromContext(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n, err := m.SaveSnapshot(path)
+			if err != nil {
+				logger.Error(err, "Failed to save snapshot.", "path", path)
+
+				continue
+			}
+
+			logger.V(1).Info("Saved snapshot.", "path", path, "entries", n)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
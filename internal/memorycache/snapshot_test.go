@@ -0,0 +1,194 @@
+package memorycache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStringer is a key implementing fmt.Stringer.
+type testStringer string
+
+func (s testStringer) String() string { return "stringer:" + string(s) }
+
+// testMarshaler is a value implementing encoding.BinaryMarshaler.
+type testMarshaler string
+
+func (m testMarshaler) MarshalBinary() ([]byte, error) { return []byte(m), nil }
+
+func TestMemoryCacheSnapshot(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name         string
+		give         func(ctx context.Context, m *MemoryCache)
+		maxStaleness time.Duration
+		expected     map[interface{}][]byte // The value of each key restored (nil if not).
+	}{
+		{
+			name: "restored",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", []byte("1"), now.Add(time.Minute))
+				m.Set(ctx, testStringer("b"), testMarshaler("2"), now.Add(time.Minute))
+				m.Set(ctx, "c", []byte("3"), time.Time{})
+			},
+			expected: map[interface{}][]byte{"a": []byte("1"), testStringer("b"): []byte("2"), "c": []byte("3")},
+		},
+		{
+			name: "unserializable_skipped",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", 1, now.Add(time.Minute))
+				m.Set(ctx, 2, []byte("2"), now.Add(time.Minute))
+			},
+			expected: map[interface{}][]byte{"a": nil, 2: nil},
+		},
+		{
+			name: "past_max_staleness_skipped",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", []byte("1"), now.Add(-time.Second*30))
+				m.Set(ctx, "b", []byte("2"), now.Add(-time.Second*90))
+			},
+			maxStaleness: time.Minute,
+			expected:     map[interface{}][]byte{"a": []byte("1"), "b": nil},
+		},
+		{
+			name: "past_expiry_grace_skipped",
+			give: func(ctx context.Context, m *MemoryCache) {
+				m.Set(ctx, "a", []byte("1"), now.Add(-time.Minute*90))
+			},
+			expected: map[interface{}][]byte{"a": nil},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			// Written with a long expiry grace, such that all entries are snapshot.
+			from := New(WithExpiryGrace(time.Hour*24), withNow(func() time.Time { return now }))
+			tc.give(ctx, from)
+
+			var buf bytes.Buffer
+			_, err := from.WriteSnapshot(&buf)
+			require.NoError(t, err)
+
+			to := New(WithExpiryGrace(time.Hour), withNow(func() time.Time { return now }))
+			_, err = to.ReadSnapshot(ctx, &buf, tc.maxStaleness)
+			require.NoError(t, err)
+
+			for key, expected := range tc.expected {
+				actual, _, err := to.Get(ctx, key)
+				assert.NoError(t, err)
+
+				if expected == nil {
+					assert.Nil(t, actual, "key %v", key)
+				} else {
+					assert.Equal(t, expected, actual, "key %v", key)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryCacheSnapshotRecency(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	expiry := time.Now().Add(time.Minute)
+
+	from := New(WithShards(1))
+	from.Set(ctx, "a", []byte("1"), expiry)
+	from.Set(ctx, "b", []byte("2"), expiry)
+	from.Set(ctx, "c", []byte("3"), expiry)
+	from.Get(ctx, "a")
+
+	var buf bytes.Buffer
+	_, err := from.WriteSnapshot(&buf)
+	require.NoError(t, err)
+
+	// Restored into a smaller cache, the most recently used entries ("a" & "c") are kept, and
+	// keep their recency: "c" is evicted before "a".
+	to := New(WithMaxEntries(2), WithShards(1))
+	_, err = to.ReadSnapshot(ctx, &buf, 0)
+	require.NoError(t, err)
+
+	to.Set(ctx, "d", []byte("4"), expiry)
+
+	for key, expected := range map[string][]byte{"a": []byte("1"), "b": nil, "c": nil, "d": []byte("4")} {
+		actual, _, err := to.Get(ctx, key)
+		assert.NoError(t, err)
+
+		if expected == nil {
+			assert.Nil(t, actual, "key %v", key)
+		} else {
+			assert.Equal(t, expected, actual, "key %v", key)
+		}
+	}
+}
+
+func TestMemoryCacheReadSnapshotUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	_, err := m.ReadSnapshot(context.Background(), bytes.NewBufferString(`{"version":2}`+"\n"), 0)
+	assert.EqualError(t, err, "memorycache: read snapshot: unsupported version 2")
+}
+
+func TestMemoryCacheSaveLoadSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	path := filepath.Join(t.TempDir(), "snapshot.jsonl")
+	expiry := time.Now().Add(time.Minute).Round(0)
+
+	t.Run("missing_file", func(t *testing.T) {
+		n, err := New().LoadSnapshot(ctx, path, 0)
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("saved_and_loaded", func(t *testing.T) {
+		from := New()
+		from.Set(ctx, "a", []byte("1"), expiry)
+
+		n, err := from.SaveSnapshot(path)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		// Saving again replaces the snapshot, leaving no temporary files behind.
+		from.Set(ctx, "b", []byte("2"), expiry)
+
+		n, err = from.SaveSnapshot(path)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		files, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		to := New()
+		n, err = to.LoadSnapshot(ctx, path, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		actualValue, actualExpiry, err := to.Get(ctx, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("2"), actualValue)
+		assert.True(t, expiry.Equal(actualExpiry))
+	})
+}
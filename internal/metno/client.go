@@ -0,0 +1,198 @@
+package metno
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+)
+
+// tracerName is the name of the tracer used by the [Client], see [apiclient.WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/metno"
+
+// maxCacheEntries bounds the response cache. Once full, expired entries are removed & if none
+// have expired, an arbitrary entry is.
+const maxCacheEntries = 1000
+
+// Client is used to interact with the Met Norway Locationforecast API. Responses are cached
+// until they expire, then revalidated with a conditional request.
+type Client struct {
+	client      *apiclient.Client
+	endpointURL string
+	userAgent   string
+	now         func() time.Time
+
+	cacheMu sync.Mutex
+	cache   map[string]cacheEntry
+}
+
+// New creates a new [Client] querying endpointURL (e.g
+// "https://api.met.no/weatherapi/locationforecast/2.0"). userAgent identifies the application
+// to Met Norway, who ask that it includes a contact, e.g "myweatherapp.com
+// contact@myweatherapp.com".
+func New(endpointURL, userAgent string, optionOverrides ...func(*apiclient.NewOptions)) *Client {
+	return &Client{
+		client:      apiclient.New("metno", tracerName, optionOverrides...),
+		endpointURL: endpointURL,
+		userAgent:   userAgent,
+		now:         time.Now,
+		cache:       make(map[string]cacheEntry),
+	}
+}
+
+// cacheEntry is a cached response, along with the validators used to revalidate it.
+type cacheEntry struct {
+	forecast     *Forecast
+	lastModified string    // The Last-Modified header of the response, sent as If-Modified-Since.
+	expires      time.Time // When the response must be revalidated, from the Expires header.
+}
+
+// validators are the caching headers of a response.
+type validators struct {
+	lastModified string
+	expires      time.Time
+}
+
+// getForecast returns the forecast at path with query, from the cache while it hasn't
+// expired. Once expired, the cached forecast is revalidated with a conditional request, being
+// reused if the API responds that it hasn't been modified.
+func (c *Client) getForecast(ctx context.Context, path string, query url.Values) (*Forecast, error) {
+	key := path + "?" + query.Encode()
+
+	entry, cached := c.cachedEntry(key)
+	if cached && c.now().Before(entry.expires) {
+		return entry.forecast, nil
+	}
+
+	var apiResponse locationforecastResponse
+
+	v, notModified, err := c.get(ctx, path, query, entry.lastModified, &apiResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if notModified {
+		if !cached {
+			return nil, errors.New("metno: not modified response to an unconditional request")
+		}
+
+		entry.expires = v.expires
+		if v.lastModified != "" {
+			entry.lastModified = v.lastModified
+		}
+	} else {
+		entry = cacheEntry{forecast: apiResponse.toForecast(), lastModified: v.lastModified, expires: v.expires}
+	}
+
+	c.cacheEntry(key, entry)
+
+	return entry.forecast, nil
+}
+
+// get sends a GET request to the endpoint at path with query, decoding a successful response
+// body into apiResponse. If ifModifiedSince is set the request is conditional, with notModified
+// reporting whether the API responded that the resource hasn't been modified (leaving
+// apiResponse as is). An unsuccessful response is returned as an [*APIError].
+func (c *Client) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	ifModifiedSince string,
+	apiResponse interface{},
+) (v validators, notModified bool, err error) {
+	req := apiclient.Request{
+		Route:      path,
+		URL:        fmt.Sprintf("%s/%s", c.endpointURL, path),
+		Query:      query,
+		Header:     http.Header{"User-Agent": []string{c.userAgent}},
+		Attributes: []attribute.KeyValue{attribute.Bool("http.conditional", ifModifiedSince != "")},
+	}
+
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+
+	err = c.client.Get(ctx, &req, func(res *http.Response, body []byte) error {
+		v = c.responseValidators(res.Header)
+
+		switch res.StatusCode {
+		case http.StatusNotModified:
+			notModified = true
+
+			return nil
+		case http.StatusOK, http.StatusNonAuthoritativeInfo: // 203 is returned by deprecated versions of the API.
+		default:
+			return &APIError{StatusCode: res.StatusCode}
+		}
+
+		if err := json.Unmarshal(body, apiResponse); err != nil {
+			return errors.Wrap(err, "metno: decode body")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return validators{}, false, err
+	}
+
+	return v, notModified, nil
+}
+
+// responseValidators returns the validators of a response with header. A missing or invalid
+// Expires header is treated as the response having expired already.
+func (c *Client) responseValidators(header http.Header) validators {
+	v := validators{lastModified: header.Get("Last-Modified")}
+
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		expires = c.now()
+	}
+
+	v.expires = expires
+
+	return v
+}
+
+// cachedEntry returns the cache entry for key, if any (including an expired entry).
+func (c *Client) cachedEntry(key string) (cacheEntry, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	entry, ok := c.cache[key]
+
+	return entry, ok
+}
+
+// cacheEntry caches entry for key, making room if the cache is full.
+func (c *Client) cacheEntry(key string, entry cacheEntry) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if _, ok := c.cache[key]; !ok && len(c.cache) >= maxCacheEntries {
+		now := c.now()
+
+		for k, e := range c.cache {
+			if !now.Before(e.expires) {
+				delete(c.cache, k)
+			}
+		}
+
+		if len(c.cache) >= maxCacheEntries {
+			for k := range c.cache {
+				delete(c.cache, k)
+
+				break
+			}
+		}
+	}
+
+	c.cache[key] = entry
+}
@@ -0,0 +1,200 @@
+package metno
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+	"github.com/byatesrae/weather/internal/platform/apiclient/apiclienttest"
+)
+
+// stubResponse creates an http response with status code, header & body.
+func stubResponse(statusCode int, header http.Header, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Header: header, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+// stubForecastBody is the body of a "Locationforecast" response with a single time step, having
+// a temperature of temperature.
+func stubForecastBody(temperature string) string {
+	return `{"type":"Feature","properties":{"meta":{"updated_at":"2020-11-11T09:30:00Z","units":{"air_temperature":"celsius","wind_speed":"m/s"}},` +
+		`"timeseries":[{"time":"2020-11-11T10:00:00Z","data":{"instant":{"details":{"air_temperature":` + temperature + `,"wind_speed":3}}}}]}}`
+}
+
+func TestClientConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC)
+	lastModified := "Wed, 11 Nov 2020 09:30:00 GMT"
+
+	// withResponses creates a client answering requests with the given responses in turn,
+	// checking the If-Modified-Since header of each request.
+	withResponses := func(t *testing.T, expectedIfModifiedSince []string, responses ...*http.Response) (*Client, *apiclienttest.HTTPClientMock) {
+		t.Helper()
+
+		httpClient := &apiclienttest.HTTPClientMock{}
+		httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			i := len(httpClient.DoCalls()) - 1
+			require.Less(t, i, len(responses), "unexpected request")
+
+			assert.Equal(t, "Test123", req.Header.Get("User-Agent"))
+			assert.Equal(t, expectedIfModifiedSince[i], req.Header.Get("If-Modified-Since"), "If-Modified-Since of request %v", i)
+
+			return responses[i], nil
+		}
+
+		client := New("", "Test123", apiclient.NewWithHTTPClient(httpClient))
+		client.now = func() time.Time { return now }
+
+		return client, httpClient
+	}
+
+	header := func(expires time.Time) http.Header {
+		return http.Header{
+			"Expires":       []string{expires.Format(http.TimeFormat)},
+			"Last-Modified": []string{lastModified},
+		}
+	}
+
+	t.Run("cached_until_expires", func(t *testing.T) {
+		t.Parallel()
+
+		client, httpClient := withResponses(t, []string{""}, stubResponse(http.StatusOK, header(now.Add(time.Minute)), stubForecastBody("9")))
+
+		for i := 0; i < 2; i++ {
+			actual, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+			require.NoError(t, err)
+			assert.Equal(t, 9.0, *actual.Data.Instant.Details.AirTemperature)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 1)
+	})
+
+	t.Run("not_modified_reused", func(t *testing.T) {
+		t.Parallel()
+
+		client, httpClient := withResponses(t, []string{"", lastModified, ""},
+			stubResponse(http.StatusOK, header(now), stubForecastBody("9")),
+			stubResponse(http.StatusNotModified, header(now.Add(time.Minute)), ""),
+		)
+
+		for i := 0; i < 3; i++ {
+			actual, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+			require.NoError(t, err)
+			assert.Equal(t, 9.0, *actual.Data.Instant.Details.AirTemperature)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 2, "revalidated once, then cached until the new expiry")
+	})
+
+	t.Run("modified_replaced", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := withResponses(t, []string{"", lastModified},
+			stubResponse(http.StatusOK, header(now), stubForecastBody("9")),
+			stubResponse(http.StatusOK, header(now.Add(time.Minute)), stubForecastBody("10")),
+		)
+
+		_, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+		require.NoError(t, err)
+
+		actual, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, *actual.Data.Instant.Details.AirTemperature)
+	})
+
+	t.Run("missing_expires_revalidated", func(t *testing.T) {
+		t.Parallel()
+
+		client, httpClient := withResponses(t, []string{"", lastModified},
+			stubResponse(http.StatusOK, http.Header{"Last-Modified": []string{lastModified}}, stubForecastBody("9")),
+			stubResponse(http.StatusNotModified, nil, ""),
+		)
+
+		for i := 0; i < 2; i++ {
+			_, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 2)
+	})
+
+	t.Run("locations_cached_separately", func(t *testing.T) {
+		t.Parallel()
+
+		client, httpClient := withResponses(t, []string{"", ""},
+			stubResponse(http.StatusOK, header(now.Add(time.Minute)), stubForecastBody("9")),
+			stubResponse(http.StatusOK, header(now.Add(time.Minute)), stubForecastBody("10")),
+		)
+
+		_, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+		require.NoError(t, err)
+
+		actual, err := client.CurrentByCoordinates(context.Background(), 60.3913, 5.3221)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, *actual.Data.Instant.Details.AirTemperature)
+		assert.Len(t, httpClient.DoCalls(), 2)
+	})
+
+	t.Run("not_modified_unconditional", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := withResponses(t, []string{""}, stubResponse(http.StatusNotModified, nil, ""))
+
+		_, err := client.CurrentByCoordinates(context.Background(), 59.9139, 10.7522)
+		assert.EqualError(t, err, "metno: not modified response to an unconditional request")
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("", "Test123")
+
+		for i := 0; i < maxCacheEntries+10; i++ {
+			client.cacheEntry(formatCoordinate(float64(i)), cacheEntry{forecast: &Forecast{}, expires: time.Now().Add(time.Hour)})
+		}
+
+		assert.Len(t, client.cache, maxCacheEntries)
+	})
+}
+
+func TestClientCompactByCoordinatesTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New("https://example.com", "Test123",
+		apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			},
+		}),
+		apiclient.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.CompactByCoordinates(context.Background(), 59.9139, 10.7522)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "metno GET /compact", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/compact"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Bool("http.conditional", false))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusBadGateway))
+	}
+}
@@ -0,0 +1,11 @@
+// Package metno provides a client to interact with the Norwegian Meteorological Institute's
+// [Locationforecast API] (Met Norway). The API needs no key, but does need a User-Agent
+// identifying the application.
+//
+// Met Norway's [terms of service] require clients to cache responses, honouring the Expires
+// header & revalidating with If-Modified-Since (using the Last-Modified header of the cached
+// response) rather than requesting a forecast again. The [Client] does this itself.
+//
+// [Locationforecast API]: https://api.met.no/weatherapi/locationforecast/2.0/documentation
+// [terms of service]: https://api.met.no/doc/TermsOfService
+package metno
@@ -0,0 +1,44 @@
+package metno
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of errors returned by the [Client], to be matched with errors.Is.
+var (
+	ErrInvalidQuery = errors.New("metno: invalid query")
+	ErrRateLimited  = errors.New("metno: rate limited")
+
+	// ErrForbidden is returned when the API refuses the client, e.g for a missing or
+	// blocked User-Agent.
+	ErrForbidden = errors.New("metno: forbidden")
+)
+
+// APIError is an unsuccessful response from the Met Norway API. The API doesn't describe errors
+// in a structured way, so only the status code is kept.
+type APIError struct {
+	StatusCode int // The response status code.
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("metno: unexpected response status code %v", e.StatusCode)
+}
+
+// Is reports whether the error is of the class target, one of [ErrInvalidQuery],
+// [ErrRateLimited] or [ErrForbidden].
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidQuery:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	default:
+		return false
+	}
+}
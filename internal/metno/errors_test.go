@@ -0,0 +1,38 @@
+package metno
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "bad_request", give: &APIError{StatusCode: http.StatusBadRequest}, expected: ErrInvalidQuery},
+		{name: "unprocessable_entity", give: &APIError{StatusCode: http.StatusUnprocessableEntity}, expected: ErrInvalidQuery},
+		{name: "rate_limited", give: &APIError{StatusCode: http.StatusTooManyRequests}, expected: ErrRateLimited},
+		{name: "forbidden", give: &APIError{StatusCode: http.StatusForbidden}, expected: ErrForbidden},
+		{name: "server_error", give: &APIError{StatusCode: http.StatusInternalServerError}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrInvalidQuery, ErrRateLimited, ErrForbidden} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package metno
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Forecast is a forecast from the Met Norway API "Locationforecast" endpoints.
+type Forecast struct {
+	UpdatedAt  time.Time         // When the forecast was last updated.
+	Units      map[string]string // The unit of each detail, keyed by name, e.g "air_temperature": "celsius".
+	Timeseries []TimeStep        // The forecast time steps, in chronological order.
+}
+
+// TimeStep is the forecast for a point in time.
+type TimeStep struct {
+	Time time.Time    `json:"time"`
+	Data TimeStepData `json:"data"`
+}
+
+// TimeStepData is the forecast of a [TimeStep].
+//
+// Pointer fields are optional and are nil when not included in the response.
+type TimeStepData struct {
+	Instant struct {
+		Details InstantDetails `json:"details"`
+	} `json:"instant"` // The forecast for the time of the time step.
+	Next1Hours *Period `json:"next_1_hours,omitempty"` // The forecast for the hour following the time step.
+	Next6Hours *Period `json:"next_6_hours,omitempty"` // The forecast for the 6 hours following the time step.
+}
+
+// InstantDetails are the forecast values for the time of a [TimeStep], in the units of
+// [Forecast.Units].
+//
+// Pointer fields are optional and are nil when not included in the response.
+type InstantDetails struct {
+	AirTemperature        *float64 `json:"air_temperature,omitempty"`           // In degrees celsius.
+	WindSpeed             *float64 `json:"wind_speed,omitempty"`                // In m/s.
+	WindSpeedOfGust       *float64 `json:"wind_speed_of_gust,omitempty"`        // In m/s, "complete" forecasts only.
+	WindFromDirection     *float64 `json:"wind_from_direction,omitempty"`       // In degrees (meteorological).
+	RelativeHumidity      *float64 `json:"relative_humidity,omitempty"`         // As a percentage.
+	AirPressureAtSeaLevel *float64 `json:"air_pressure_at_sea_level,omitempty"` // In hPa.
+	CloudAreaFraction     *float64 `json:"cloud_area_fraction,omitempty"`       // As a percentage.
+}
+
+// Period is the forecast for a period following a [TimeStep].
+type Period struct {
+	Summary struct {
+		SymbolCode string `json:"symbol_code"` // e.g "partlycloudy_day", see https://github.com/metno/weathericons.
+	} `json:"summary"`
+	Details struct {
+		PrecipitationAmount *float64 `json:"precipitation_amount,omitempty"` // In mm.
+	} `json:"details"`
+}
+
+// locationforecastResponse is a (GeoJSON) response from the Met Norway API "Locationforecast"
+// endpoints.
+type locationforecastResponse struct {
+	Properties struct {
+		Meta struct {
+			UpdatedAt time.Time         `json:"updated_at"`
+			Units     map[string]string `json:"units"`
+		} `json:"meta"`
+		Timeseries []TimeStep `json:"timeseries"`
+	} `json:"properties"`
+}
+
+// toForecast translates the response to a [Forecast].
+func (r *locationforecastResponse) toForecast() *Forecast {
+	return &Forecast{
+		UpdatedAt:  r.Properties.Meta.UpdatedAt,
+		Units:      r.Properties.Meta.Units,
+		Timeseries: r.Properties.Timeseries,
+	}
+}
+
+// Nowcast returns the time step of the forecast for now, being the latest time step at or
+// before now (or the first, if they are all after now).
+func (f *Forecast) Nowcast(now time.Time) (*TimeStep, error) {
+	if len(f.Timeseries) == 0 {
+		return nil, errors.New("metno: forecast has no timeseries")
+	}
+
+	nowcast := &f.Timeseries[0]
+
+	for i := range f.Timeseries {
+		if f.Timeseries[i].Time.After(now) {
+			break
+		}
+
+		nowcast = &f.Timeseries[i]
+	}
+
+	if nowcast.Data.Instant.Details.AirTemperature == nil {
+		return nil, errors.New("metno: nowcast has no data.instant.details.air_temperature")
+	}
+
+	if nowcast.Data.Instant.Details.WindSpeed == nil {
+		return nil, errors.New("metno: nowcast has no data.instant.details.wind_speed")
+	}
+
+	return nowcast, nil
+}
+
+// formatCoordinate formats a latitude/longitude (in decimal degrees) to at most 4 decimal places,
+// as Met Norway asks (requests with more are throttled, being uncacheable).
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// CompactByCoordinates returns the "compact" forecast for a latitude/longitude (in decimal
+// degrees).
+func (c *Client) CompactByCoordinates(ctx context.Context, latitude, longitude float64) (*Forecast, error) {
+	query := url.Values{
+		"lat": []string{formatCoordinate(latitude)},
+		"lon": []string{formatCoordinate(longitude)},
+	}
+
+	return c.getForecast(ctx, "compact", query)
+}
+
+// CurrentByCoordinates returns the nowcast (see [Forecast.Nowcast]) of the "compact" forecast for
+// a latitude/longitude (in decimal degrees).
+func (c *Client) CurrentByCoordinates(ctx context.Context, latitude, longitude float64) (*TimeStep, error) {
+	forecast, err := c.CompactByCoordinates(ctx, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	return forecast.Nowcast(c.now())
+}
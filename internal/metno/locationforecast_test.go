@@ -0,0 +1,172 @@
+package metno
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+	"github.com/byatesrae/weather/internal/platform/apiclient/apiclienttest"
+)
+
+func TestClientCompactByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	value := func(v float64) *float64 { return &v }
+
+	for _, tc := range []struct {
+		name        string
+		withClient  *Client
+		expected    *Forecast
+		expectedErr string
+	}{
+		{
+			name: "success",
+			withClient: New("", "Test123", apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/compact", req.URL.Path)
+					assert.Equal(t, "59.9139", req.URL.Query().Get("lat"))
+					assert.Equal(t, "10.7522", req.URL.Query().Get("lon"))
+
+					return stubResponse(http.StatusOK, nil, stubForecastBody("9")), nil
+				},
+			})),
+			expected: &Forecast{
+				UpdatedAt: time.Date(2020, 11, 11, 9, 30, 0, 0, time.UTC),
+				Units:     map[string]string{"air_temperature": "celsius", "wind_speed": "m/s"},
+				Timeseries: []TimeStep{
+					{
+						Time: time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC),
+						Data: TimeStepData{Instant: struct {
+							Details InstantDetails `json:"details"`
+						}{Details: InstantDetails{AirTemperature: value(9), WindSpeed: value(3)}}},
+					},
+				},
+			},
+		},
+		{
+			name: "deprecated",
+			withClient: New("", "Test123", apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusNonAuthoritativeInfo, nil, `{"properties":{}}`), nil
+				},
+			})),
+			expected: &Forecast{},
+		},
+		{
+			name: "unexpected_response_type",
+			withClient: New("", "Test123", apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, nil, `"ABCDEFG"`), nil
+				},
+			})),
+			expectedErr: "metno: decode body: json: cannot unmarshal string into Go value of type metno.locationforecastResponse",
+		},
+		{
+			name: "api_error",
+			withClient: New("", "Test123", apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusForbidden, nil, `<html>Forbidden</html>`), nil
+				},
+			})),
+			expectedErr: "metno: unexpected response status code 403",
+		},
+		{
+			name: "http_client_error",
+			withClient: New("", "Test123", apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("intentional test error")
+				},
+			})),
+			expectedErr: "metno: execute request: intentional test error",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, err := tc.withClient.CompactByCoordinates(ctx, 59.913868, 10.752245)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestForecastNowcast(t *testing.T) {
+	t.Parallel()
+
+	value := func(v float64) *float64 { return &v }
+
+	step := func(hour int, temperature, windSpeed *float64) TimeStep {
+		s := TimeStep{Time: time.Date(2020, 11, 11, hour, 0, 0, 0, time.UTC)}
+		s.Data.Instant.Details = InstantDetails{AirTemperature: temperature, WindSpeed: windSpeed}
+
+		return s
+	}
+
+	forecast := &Forecast{Timeseries: []TimeStep{
+		step(10, value(9), value(3)),
+		step(11, value(10), value(4)),
+		step(12, nil, value(5)),
+	}}
+
+	for _, tc := range []struct {
+		name        string
+		withGive    *Forecast
+		giveNow     time.Time
+		expected    *TimeStep
+		expectedErr string
+	}{
+		{name: "latest_before_now", withGive: forecast, giveNow: time.Date(2020, 11, 11, 11, 40, 0, 0, time.UTC), expected: &forecast.Timeseries[1]},
+		{name: "at_now", withGive: forecast, giveNow: time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC), expected: &forecast.Timeseries[0]},
+		{name: "all_after_now", withGive: forecast, giveNow: time.Date(2020, 11, 11, 9, 0, 0, 0, time.UTC), expected: &forecast.Timeseries[0]},
+		{
+			name:        "missing_temperature",
+			withGive:    forecast,
+			giveNow:     time.Date(2020, 11, 11, 12, 0, 0, 0, time.UTC),
+			expectedErr: "metno: nowcast has no data.instant.details.air_temperature",
+		},
+		{
+			name:        "missing_wind_speed",
+			withGive:    &Forecast{Timeseries: []TimeStep{step(10, value(9), nil)}},
+			giveNow:     time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC),
+			expectedErr: "metno: nowcast has no data.instant.details.wind_speed",
+		},
+		{
+			name:        "no_timeseries",
+			withGive:    &Forecast{},
+			giveNow:     time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC),
+			expectedErr: "metno: forecast has no timeseries",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tc.withGive.Nowcast(tc.giveNow)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package nws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+)
+
+// tracerName is the name of the tracer used by the [Client], see [apiclient.WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/nws"
+
+// Client is used to interact with the NWS API.
+type Client struct {
+	client         *apiclient.Client
+	endpointURL    string
+	userAgent      string
+	pointsCacheTTL time.Duration
+	now            func() time.Time
+
+	pointsMu sync.Mutex
+	points   map[string]pointsCacheEntry
+}
+
+// New creates a new [Client] querying endpointURL (e.g "https://api.weather.gov"). userAgent
+// identifies the application to the NWS, who ask that it includes a contact, e.g
+// "(myweatherapp.com, contact@myweatherapp.com)". pointsCacheTTL is how long the result of a
+// points lookup is cached for (see [Client.Point]); the NWS grid & stations rarely change, so
+// e.g 24 hours. A value <= 0 disables the cache.
+func New(endpointURL, userAgent string, pointsCacheTTL time.Duration, optionOverrides ...func(*apiclient.NewOptions)) *Client {
+	return &Client{
+		client:         apiclient.New("nws", tracerName, optionOverrides...),
+		endpointURL:    endpointURL,
+		userAgent:      userAgent,
+		pointsCacheTTL: pointsCacheTTL,
+		now:            time.Now,
+		points:         make(map[string]pointsCacheEntry),
+	}
+}
+
+// get sends a GET request to the endpoint at path with query, decoding a successful (GeoJSON)
+// response body into apiResponse. route is path with its parameters left as placeholders
+// (e.g "points/{point}"), used to name the span. An unsuccessful response is returned as an
+// [*APIError].
+func (c *Client) get(
+	ctx context.Context,
+	route string,
+	path string,
+	query url.Values,
+	apiResponse interface{},
+) error {
+	req := apiclient.Request{
+		Route: route,
+		URL:   fmt.Sprintf("%s/%s", c.endpointURL, path),
+		Query: query,
+		Header: http.Header{
+			"User-Agent": []string{c.userAgent},
+			"Accept":     []string{"application/geo+json"},
+		},
+	}
+
+	return c.client.Get(ctx, &req, func(res *http.Response, body []byte) error {
+		if res.StatusCode != http.StatusOK {
+			return newAPIError(res.StatusCode, body)
+		}
+
+		if body == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(body, apiResponse); err != nil {
+			return errors.Wrap(err, "nws: decode body")
+		}
+
+		return nil
+	})
+}
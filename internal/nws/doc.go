@@ -0,0 +1,10 @@
+// Package nws provides a client to interact with the [National Weather Service API] (NWS),
+// covering locations in the US only. The API needs no key, but does need a User-Agent
+// identifying the application.
+//
+// Weather is found in two steps: a latitude/longitude is resolved to the NWS forecast grid
+// & its nearest observation stations (the "points" lookup, which is cached), then the latest
+// observation of those stations is fetched.
+//
+// [National Weather Service API]: https://www.weather.gov/documentation/services-web-api
+package nws
@@ -0,0 +1,85 @@
+package nws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// problemTypeInvalidPoint is the (suffix of the) problem type of a points lookup outside the
+// area the NWS covers.
+const problemTypeInvalidPoint = "/InvalidPoint"
+
+// Classes of errors returned by the [Client], to be matched with errors.Is.
+var (
+	ErrInvalidQuery = errors.New("nws: invalid query")
+	ErrRateLimited  = errors.New("nws: rate limited")
+
+	// ErrPointUnsupported is returned for a latitude/longitude outside the area the NWS
+	// covers (the US & its territories).
+	ErrPointUnsupported = errors.New("nws: point unsupported")
+
+	// ErrObservationUnavailable is returned when none of the stations near a point have a
+	// recent, usable observation.
+	ErrObservationUnavailable = errors.New("nws: observation unavailable")
+)
+
+// APIError is an unsuccessful response from the NWS API, being an RFC 7807 problem.
+type APIError struct {
+	StatusCode int    // The response status code.
+	Type       string // The "type" of the problem, e.g "https://api.weather.gov/problems/InvalidPoint".
+	Title      string // The "title" of the problem, e.g "Data Unavailable For Requested Point".
+	Detail     string // The "detail" of the problem.
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	switch {
+	case e.Detail != "":
+		return fmt.Sprintf("nws: unexpected response status code %v: %s", e.StatusCode, e.Detail)
+	case e.Title != "":
+		return fmt.Sprintf("nws: unexpected response status code %v: %s", e.StatusCode, e.Title)
+	default:
+		return fmt.Sprintf("nws: unexpected response status code %v", e.StatusCode)
+	}
+}
+
+// Is reports whether the error is of the class target, one of [ErrInvalidQuery],
+// [ErrRateLimited] or [ErrPointUnsupported].
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidQuery:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrPointUnsupported:
+		return e.StatusCode == http.StatusNotFound && strings.HasSuffix(e.Type, problemTypeInvalidPoint)
+	default:
+		return false
+	}
+}
+
+// apiErrorResponse is the body of an unsuccessful response from the NWS API.
+type apiErrorResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// newAPIError creates an [APIError] for an unsuccessful response with the given status code &
+// body (which may not be in the expected form).
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var errorResponse apiErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiErr.Type = errorResponse.Type
+		apiErr.Title = errorResponse.Title
+		apiErr.Detail = errorResponse.Detail
+	}
+
+	return apiErr
+}
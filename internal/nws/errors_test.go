@@ -0,0 +1,98 @@
+package nws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "invalid_query", give: &APIError{StatusCode: http.StatusBadRequest}, expected: ErrInvalidQuery},
+		{name: "rate_limited", give: &APIError{StatusCode: http.StatusTooManyRequests}, expected: ErrRateLimited},
+		{
+			name:     "point_unsupported",
+			give:     &APIError{StatusCode: http.StatusNotFound, Type: "https://api.weather.gov/problems/InvalidPoint"},
+			expected: ErrPointUnsupported,
+		},
+		{name: "not_found", give: &APIError{StatusCode: http.StatusNotFound, Type: "https://api.weather.gov/problems/NotFound"}},
+		{name: "server_error", give: &APIError{StatusCode: http.StatusInternalServerError}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrInvalidQuery, ErrRateLimited, ErrPointUnsupported} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		giveStatusCode int
+		giveBody       []byte
+		expected       *APIError
+		expectedMsg    string
+	}{
+		{
+			name:           "problem",
+			giveStatusCode: http.StatusNotFound,
+			giveBody:       []byte(`{"correlationId":"1a2b","title":"Data Unavailable For Requested Point","type":"https://api.weather.gov/problems/InvalidPoint","status":404,"detail":"Unable to provide data for requested point 51.5,-0.12","instance":"https://api.weather.gov/requests/1a2b"}`),
+			expected: &APIError{
+				StatusCode: http.StatusNotFound,
+				Type:       "https://api.weather.gov/problems/InvalidPoint",
+				Title:      "Data Unavailable For Requested Point",
+				Detail:     "Unable to provide data for requested point 51.5,-0.12",
+			},
+			expectedMsg: "nws: unexpected response status code 404: Unable to provide data for requested point 51.5,-0.12",
+		},
+		{
+			name:           "title_only",
+			giveStatusCode: http.StatusInternalServerError,
+			giveBody:       []byte(`{"title":"Unexpected Problem"}`),
+			expected:       &APIError{StatusCode: http.StatusInternalServerError, Title: "Unexpected Problem"},
+			expectedMsg:    "nws: unexpected response status code 500: Unexpected Problem",
+		},
+		{
+			name:           "no_body",
+			giveStatusCode: http.StatusBadGateway,
+			expected:       &APIError{StatusCode: http.StatusBadGateway},
+			expectedMsg:    "nws: unexpected response status code 502",
+		},
+		{
+			name:           "unexpected_body",
+			giveStatusCode: http.StatusBadGateway,
+			giveBody:       []byte(`<html></html>`),
+			expected:       &APIError{StatusCode: http.StatusBadGateway},
+			expectedMsg:    "nws: unexpected response status code 502",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := newAPIError(tc.giveStatusCode, tc.giveBody)
+
+			assert.Equal(t, tc.expected, actual)
+			assert.EqualError(t, actual, tc.expectedMsg)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package nws
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Observation is the weather observed by a station, from the NWS API "Latest Observation"
+// endpoint.
+type Observation struct {
+	Station               string            `json:"station"`         // The URL of the station.
+	Timestamp             time.Time         `json:"timestamp"`       // When the observation was made.
+	TextDescription       string            `json:"textDescription"` // e.g "Mostly Cloudy".
+	Temperature           QuantitativeValue `json:"temperature"`
+	Dewpoint              QuantitativeValue `json:"dewpoint"`
+	WindDirection         QuantitativeValue `json:"windDirection"`
+	WindSpeed             QuantitativeValue `json:"windSpeed"`
+	WindGust              QuantitativeValue `json:"windGust"`
+	BarometricPressure    QuantitativeValue `json:"barometricPressure"`
+	SeaLevelPressure      QuantitativeValue `json:"seaLevelPressure"`
+	Visibility            QuantitativeValue `json:"visibility"`
+	PrecipitationLastHour QuantitativeValue `json:"precipitationLastHour"`
+	RelativeHumidity      QuantitativeValue `json:"relativeHumidity"`
+	WindChill             QuantitativeValue `json:"windChill"`
+	HeatIndex             QuantitativeValue `json:"heatIndex"`
+}
+
+// usable reports whether the observation has the measurements a weather summary can't go
+// without.
+func (o *Observation) usable() bool {
+	return o.Temperature.Value != nil && o.Temperature.QualityControl != qualityControlRejected &&
+		o.WindSpeed.Value != nil && o.WindSpeed.QualityControl != qualityControlRejected
+}
+
+// observationResponse is a (GeoJSON) response from the NWS API "Latest Observation" endpoint.
+type observationResponse struct {
+	Properties Observation `json:"properties"`
+}
+
+// LatestObservation returns the latest observation of the station with the identifier
+// stationID (e.g "KTOP").
+func (c *Client) LatestObservation(ctx context.Context, stationID string) (*Observation, error) {
+	if stationID == "" {
+		return nil, errors.New("nws: stationID is required")
+	}
+
+	var apiResponse observationResponse
+	if err := c.get(ctx, "stations/{stationId}/observations/latest", "stations/"+stationID+"/observations/latest", nil, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return &apiResponse.Properties, nil
+}
+
+// CurrentByCoordinates returns the latest usable observation (having a temperature & wind
+// speed) of the stations nearest a latitude/longitude (in decimal degrees), trying each in turn.
+// If the NWS doesn't cover the point, the error is an [ErrPointUnsupported]. Otherwise the error
+// is that of the last station tried, being an [ErrObservationUnavailable] if it answered without
+// a usable observation.
+func (c *Client) CurrentByCoordinates(ctx context.Context, latitude, longitude float64) (*Observation, error) {
+	point, err := c.Point(ctx, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := c.client.Logger(ctx)
+
+	var lastErr error
+
+	for _, stationID := range point.Stations {
+		observation, err := c.LatestObservation(ctx, stationID)
+
+		var apiErr *APIError
+
+		switch {
+		case err == nil && observation.usable():
+			return observation, nil
+		case err == nil:
+			logger.V(1).Info("Latest observation is missing measurements, trying the next station.", "station", stationID)
+
+			lastErr = errors.Wrapf(ErrObservationUnavailable, "nws: stations %v", point.Stations)
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			logger.V(1).Info("Station has no latest observation, trying the next station.", "station", stationID)
+
+			lastErr = errors.Wrapf(ErrObservationUnavailable, "nws: stations %v", point.Stations)
+		case ctx.Err() != nil:
+			return nil, err
+		default:
+			logger.Error(err, "Failed to get latest observation, trying the next station.", "station", stationID)
+
+			lastErr = err
+		}
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,156 @@
+package nws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+	"github.com/byatesrae/weather/internal/platform/apiclient/apiclienttest"
+)
+
+// observationBody is the body of a "Latest Observation" response, with a temperature of
+// temperature & a wind speed of windSpeed ("null" for no measurement).
+func observationBody(temperature, windSpeed string) string {
+	return `{"type":"Feature","properties":{"station":"https://api.weather.gov/stations/KMYZ","timestamp":"2020-11-11T04:15:00-06:00","textDescription":"Cloudy",` +
+		`"temperature":{"unitCode":"wmoUnit:degC","value":` + temperature + `,"qualityControl":"V"},` +
+		`"windSpeed":{"unitCode":"wmoUnit:km_h-1","value":` + windSpeed + `,"qualityControl":"V"}}}`
+}
+
+func TestClientCurrentByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	value := func(v float64) *float64 { return &v }
+
+	expected := &Observation{
+		Station:         "https://api.weather.gov/stations/KMYZ",
+		Timestamp:       time.Date(2020, 11, 11, 4, 15, 0, 0, time.FixedZone("", -6*60*60)),
+		TextDescription: "Cloudy",
+		Temperature:     QuantitativeValue{Value: value(12.5), UnitCode: "wmoUnit:degC", QualityControl: "V"},
+		WindSpeed:       QuantitativeValue{Value: value(7.2), UnitCode: "wmoUnit:km_h-1", QualityControl: "V"},
+	}
+
+	// withStations creates a client for which the point's stations (KMYZ, then KCNK) answer
+	// the latest observation with the given responses.
+	withStations := func(kmyz, kcnk func() (*http.Response, error)) *Client {
+		points := stubPointsDo(t)
+
+		return New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				switch req.URL.Path {
+				case "/stations/KMYZ/observations/latest":
+					return kmyz()
+				case "/stations/KCNK/observations/latest":
+					return kcnk()
+				default:
+					return points(req)
+				}
+			},
+		}))
+	}
+
+	respond := func(statusCode int, body string) func() (*http.Response, error) {
+		return func() (*http.Response, error) {
+			return stubResponse(statusCode, body), nil
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		withClient  *Client
+		expected    *Observation
+		expectedErr string
+	}{
+		{
+			name:       "success",
+			withClient: withStations(respond(http.StatusOK, observationBody("12.5", "7.2")), nil),
+			expected:   expected,
+		},
+		{
+			name:       "success_next_station_missing_temperature",
+			withClient: withStations(respond(http.StatusOK, observationBody("null", "7.2")), respond(http.StatusOK, observationBody("12.5", "7.2"))),
+			expected:   expected,
+		},
+		{
+			name:       "success_next_station_error",
+			withClient: withStations(respond(http.StatusInternalServerError, ``), respond(http.StatusOK, observationBody("12.5", "7.2"))),
+			expected:   expected,
+		},
+		{
+			name:        "observation_unavailable",
+			withClient:  withStations(respond(http.StatusOK, observationBody("12.5", "null")), respond(http.StatusNotFound, `{"title":"Not Found"}`)),
+			expectedErr: "nws: stations [KMYZ KCNK]: nws: observation unavailable",
+		},
+		{
+			name:        "station_errors",
+			withClient:  withStations(respond(http.StatusOK, observationBody("12.5", "null")), respond(http.StatusBadGateway, ``)),
+			expectedErr: "nws: unexpected response status code 502",
+		},
+		{
+			name: "point_error",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("intentional test error")
+				},
+			})),
+			expectedErr: "nws: execute request: intentional test error",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, err := tc.withClient.CurrentByCoordinates(ctx, 39.7456, -97.0892)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientLatestObservationTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New("https://example.com", "Test123", time.Hour*24,
+		apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			},
+		}),
+		apiclient.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.LatestObservation(context.Background(), "KMYZ")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "nws GET /stations/{stationId}/observations/latest", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/stations/KMYZ/observations/latest"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusBadGateway))
+	}
+}
@@ -0,0 +1,177 @@
+package nws
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxStations is the number of observation stations (nearest first) kept for a point,
+	// being tried in turn for a usable observation.
+	maxStations = 3
+
+	// maxPointsCacheEntries bounds the points cache. Once full, expired entries are removed &
+	// if none have expired, an arbitrary entry is.
+	maxPointsCacheEntries = 1000
+)
+
+// Point is the NWS forecast grid & the observation stations for a latitude/longitude.
+type Point struct {
+	GridID   string   // The forecast office the grid belongs to, e.g "TOP".
+	GridX    int      // The x coordinate of the grid square.
+	GridY    int      // The y coordinate of the grid square.
+	TimeZone string   // e.g "America/Chicago".
+	Stations []string // The identifiers of the nearest observation stations, nearest first.
+}
+
+// pointResponse is a (GeoJSON) response from the NWS API "Point" endpoint.
+type pointResponse struct {
+	Properties struct {
+		GridID   string `json:"gridId"`
+		GridX    int    `json:"gridX"`
+		GridY    int    `json:"gridY"`
+		TimeZone string `json:"timeZone"`
+	} `json:"properties"`
+}
+
+// stationsResponse is a (GeoJSON) response from the NWS API "Gridpoint Stations" endpoint.
+type stationsResponse struct {
+	Features []struct {
+		Properties struct {
+			StationIdentifier string `json:"stationIdentifier"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// pointsCacheEntry is a cached points lookup, being either a point or (for a point the NWS
+// doesn't cover) an error.
+type pointsCacheEntry struct {
+	point  *Point
+	err    error
+	expiry time.Time
+}
+
+// formatPoint formats a latitude/longitude (in decimal degrees) as the NWS expects, to at most
+// 4 decimal places (the API redirects requests with more).
+func formatPoint(latitude, longitude float64) string {
+	round := func(v float64) string {
+		return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+	}
+
+	return round(latitude) + "," + round(longitude)
+}
+
+// Point returns the [Point] for a latitude/longitude (in decimal degrees). If the NWS doesn't
+// cover it, the error is an [ErrPointUnsupported]. Lookups (including those of unsupported
+// points) are cached, see [New].
+func (c *Client) Point(ctx context.Context, latitude, longitude float64) (*Point, error) {
+	key := formatPoint(latitude, longitude)
+
+	if entry, ok := c.cachedPoint(key); ok {
+		return entry.point, entry.err
+	}
+
+	point, err := c.lookupPoint(ctx, key)
+	if err == nil || errors.Is(err, ErrPointUnsupported) {
+		c.cachePoint(key, pointsCacheEntry{point: point, err: err})
+	}
+
+	return point, err
+}
+
+// lookupPoint requests the [Point] for key (see formatPoint) from the API.
+func (c *Client) lookupPoint(ctx context.Context, key string) (*Point, error) {
+	var pointRes pointResponse
+	if err := c.get(ctx, "points/{point}", "points/"+key, nil, &pointRes); err != nil {
+		return nil, err
+	}
+
+	if pointRes.Properties.GridID == "" {
+		return nil, errors.New("nws: response has no properties.gridId")
+	}
+
+	point := Point{
+		GridID:   pointRes.Properties.GridID,
+		GridX:    pointRes.Properties.GridX,
+		GridY:    pointRes.Properties.GridY,
+		TimeZone: pointRes.Properties.TimeZone,
+	}
+
+	var stationsRes stationsResponse
+	if err := c.get(
+		ctx,
+		"gridpoints/{wfo}/{x},{y}/stations",
+		fmt.Sprintf("gridpoints/%s/%d,%d/stations", point.GridID, point.GridX, point.GridY),
+		url.Values{"limit": []string{strconv.Itoa(maxStations)}},
+		&stationsRes,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, feature := range stationsRes.Features {
+		if len(point.Stations) == maxStations {
+			break
+		}
+
+		if id := feature.Properties.StationIdentifier; id != "" {
+			point.Stations = append(point.Stations, id)
+		}
+	}
+
+	if len(point.Stations) == 0 {
+		return nil, errors.Wrapf(ErrObservationUnavailable, "nws: point %s has no observation stations", key)
+	}
+
+	return &point, nil
+}
+
+// cachedPoint returns the unexpired cache entry for key, if any.
+func (c *Client) cachedPoint(key string) (pointsCacheEntry, bool) {
+	c.pointsMu.Lock()
+	defer c.pointsMu.Unlock()
+
+	entry, ok := c.points[key]
+	if !ok || !c.now().Before(entry.expiry) {
+		return pointsCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// cachePoint caches entry for key (unless the cache is disabled), making room if the cache is
+// full.
+func (c *Client) cachePoint(key string, entry pointsCacheEntry) {
+	if c.pointsCacheTTL <= 0 {
+		return
+	}
+
+	c.pointsMu.Lock()
+	defer c.pointsMu.Unlock()
+
+	now := c.now()
+	entry.expiry = now.Add(c.pointsCacheTTL)
+
+	if _, ok := c.points[key]; !ok && len(c.points) >= maxPointsCacheEntries {
+		for k, e := range c.points {
+			if !now.Before(e.expiry) {
+				delete(c.points, k)
+			}
+		}
+
+		if len(c.points) >= maxPointsCacheEntries {
+			for k := range c.points {
+				delete(c.points, k)
+
+				break
+			}
+		}
+	}
+
+	c.points[key] = entry
+}
@@ -0,0 +1,232 @@
+package nws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+	"github.com/byatesrae/weather/internal/platform/apiclient/apiclienttest"
+)
+
+// stubResponse creates an http response with status code & body.
+func stubResponse(statusCode int, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+// stubPointsDo is the Do func of an apiclienttest.HTTPClientMock answering a points lookup of 39.7456,-97.0892.
+func stubPointsDo(t *testing.T) func(req *http.Request) (*http.Response, error) {
+	t.Helper()
+
+	return func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Test123", req.Header.Get("User-Agent"))
+		assert.Equal(t, "application/geo+json", req.Header.Get("Accept"))
+
+		switch req.URL.Path {
+		case "/points/39.7456,-97.0892":
+			return stubResponse(http.StatusOK, `{"type":"Feature","geometry":{"type":"Point","coordinates":[-97.0892,39.7456]},"properties":{"gridId":"TOP","gridX":32,"gridY":81,"timeZone":"America/Chicago"}}`), nil
+		case "/gridpoints/TOP/32,81/stations":
+			assert.Equal(t, "3", req.URL.Query().Get("limit"))
+
+			return stubResponse(http.StatusOK, `{"type":"FeatureCollection","features":[{"properties":{"stationIdentifier":"KMYZ"}},{"properties":{"stationIdentifier":"KCNK"}}]}`), nil
+		default:
+			return nil, errors.New("unexpected request")
+		}
+	}
+}
+
+func TestClientPoint(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		withClient  *Client
+		expected    *Point
+		expectedErr string
+	}{
+		{
+			name:       "success",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{DoFunc: stubPointsDo(t)})),
+			expected: &Point{
+				GridID:   "TOP",
+				GridX:    32,
+				GridY:    81,
+				TimeZone: "America/Chicago",
+				Stations: []string{"KMYZ", "KCNK"},
+			},
+		},
+		{
+			name: "point_unsupported",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusNotFound, `{"title":"Data Unavailable For Requested Point","type":"https://api.weather.gov/problems/InvalidPoint","status":404,"detail":"Unable to provide data for requested point 39.7456,-97.0892"}`), nil
+				},
+			})),
+			expectedErr: "nws: unexpected response status code 404: Unable to provide data for requested point 39.7456,-97.0892",
+		},
+		{
+			name: "missing_grid",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `{"type":"Feature","properties":{}}`), nil
+				},
+			})),
+			expectedErr: "nws: response has no properties.gridId",
+		},
+		{
+			name: "no_stations",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					if req.URL.Path == "/gridpoints/TOP/32,81/stations" {
+						return stubResponse(http.StatusOK, `{"type":"FeatureCollection","features":[]}`), nil
+					}
+
+					return stubResponse(http.StatusOK, `{"properties":{"gridId":"TOP","gridX":32,"gridY":81}}`), nil
+				},
+			})),
+			expectedErr: "nws: point 39.7456,-97.0892 has no observation stations: nws: observation unavailable",
+		},
+		{
+			name: "unexpected_response_type",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `"ABCDEFG"`), nil
+				},
+			})),
+			expectedErr: "nws: decode body: json: cannot unmarshal string into Go value of type nws.pointResponse",
+		},
+		{
+			name: "http_client_error",
+			withClient: New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(&apiclienttest.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("intentional test error")
+				},
+			})),
+			expectedErr: "nws: execute request: intentional test error",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, err := tc.withClient.Point(ctx, 39.74561, -97.08923)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientPointCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cached_until_expiry", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &apiclienttest.HTTPClientMock{DoFunc: stubPointsDo(t)}
+		now := time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC)
+
+		client := New("", "Test123", time.Hour, apiclient.NewWithHTTPClient(httpClient))
+		client.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Point(context.Background(), 39.7456, -97.0892)
+			assert.NoError(t, err)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 2, "lookup is cached")
+
+		now = now.Add(time.Hour)
+
+		_, err := client.Point(context.Background(), 39.7456, -97.0892)
+		assert.NoError(t, err)
+		assert.Len(t, httpClient.DoCalls(), 4, "lookup is repeated after expiry")
+	})
+
+	t.Run("point_unsupported_cached", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(http.StatusNotFound, `{"type":"https://api.weather.gov/problems/InvalidPoint","status":404}`), nil
+			},
+		}
+
+		client := New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(httpClient))
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Point(context.Background(), 51.5072, -0.1276)
+			assert.ErrorIs(t, err, ErrPointUnsupported)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 1)
+	})
+
+	t.Run("failure_not_cached", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &apiclienttest.HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(http.StatusInternalServerError, ``), nil
+			},
+		}
+
+		client := New("", "Test123", time.Hour*24, apiclient.NewWithHTTPClient(httpClient))
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Point(context.Background(), 39.7456, -97.0892)
+			assert.Error(t, err)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 2)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &apiclienttest.HTTPClientMock{DoFunc: stubPointsDo(t)}
+
+		client := New("", "Test123", 0, apiclient.NewWithHTTPClient(httpClient))
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Point(context.Background(), 39.7456, -97.0892)
+			assert.NoError(t, err)
+		}
+
+		assert.Len(t, httpClient.DoCalls(), 4)
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("", "Test123", time.Hour*24)
+
+		for i := 0; i < maxPointsCacheEntries+10; i++ {
+			client.cachePoint(formatPoint(float64(i), 0), pointsCacheEntry{point: &Point{}})
+		}
+
+		assert.Len(t, client.points, maxPointsCacheEntries)
+	})
+}
+
+func TestFormatPoint(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "39.7456,-97.0892", formatPoint(39.745612, -97.089249))
+	assert.Equal(t, "39.7,-97", formatPoint(39.7, -97))
+}
@@ -0,0 +1,106 @@
+package nws
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Units a [QuantitativeValue] can be converted to, see [QuantitativeValue.In]. They are WMO
+// unit codes (see https://codes.wmo.int/common/unit).
+const (
+	UnitCelsius           = "wmoUnit:degC"
+	UnitKilometresPerHour = "wmoUnit:km_h-1"
+	UnitHectopascals      = "wmoUnit:hPa"
+	UnitKilometres        = "wmoUnit:km"
+	UnitMillimetres       = "wmoUnit:mm"
+	UnitPercent           = "wmoUnit:percent"
+	UnitDegrees           = "wmoUnit:degree_(angle)"
+)
+
+// qualityControlRejected is the quality control flag of a value rejected as erroneous.
+const qualityControlRejected = "X"
+
+// unit is a unit of a quantity, with the conversion of its values to the quantity's base unit:
+// base = value*factor + offset. Base units are chosen such that common conversions are exact.
+type unit struct {
+	quantity string
+	factor   float64
+	offset   float64
+}
+
+// units are the units supported, keyed by WMO unit code (without its namespace prefix).
+var units = map[string]unit{
+	"degC": {quantity: "temperature", factor: 1},
+	"degF": {quantity: "temperature", factor: 5.0 / 9.0, offset: -160.0 / 9.0},
+	"K":    {quantity: "temperature", factor: 1, offset: -273.15},
+
+	"km_h-1": {quantity: "speed", factor: 1000}, // base m/h
+	"m_s-1":  {quantity: "speed", factor: 3600},
+	"kt":     {quantity: "speed", factor: 1852},
+	"mi_h-1": {quantity: "speed", factor: 1609.344},
+
+	"Pa":  {quantity: "pressure", factor: 1},
+	"hPa": {quantity: "pressure", factor: 100},
+
+	"mm": {quantity: "length", factor: 1},
+	"cm": {quantity: "length", factor: 10},
+	"m":  {quantity: "length", factor: 1000},
+	"km": {quantity: "length", factor: 1000000},
+
+	"percent": {quantity: "proportion", factor: 1},
+
+	"degree_(angle)": {quantity: "angle", factor: 1},
+}
+
+// lookupUnit returns the unit for a unit code, which may be prefixed by a namespace (e.g
+// "wmoUnit:degC" or "unit:degC").
+func lookupUnit(unitCode string) (unit, bool) {
+	if i := strings.LastIndex(unitCode, ":"); i >= 0 {
+		unitCode = unitCode[i+1:]
+	}
+
+	u, ok := units[unitCode]
+
+	return u, ok
+}
+
+// QuantitativeValue is a measurement & its unit, as found in the NWS API.
+type QuantitativeValue struct {
+	Value          *float64 `json:"value"`                    // Nil if there is no measurement.
+	UnitCode       string   `json:"unitCode"`                 // e.g "wmoUnit:degC".
+	QualityControl string   `json:"qualityControl,omitempty"` // e.g "V" for verified, see https://madis.ncep.noaa.gov/madis_sfc_qc_notes.shtml.
+}
+
+// In returns the value converted to unitCode (e.g [UnitCelsius]), or nil if there is no
+// measurement (including a measurement rejected by quality control). An error is returned if
+// either unit is unknown or they measure different quantities.
+func (v QuantitativeValue) In(unitCode string) (*float64, error) {
+	if v.Value == nil || v.QualityControl == qualityControlRejected {
+		return nil, nil
+	}
+
+	from, ok := lookupUnit(v.UnitCode)
+	if !ok {
+		return nil, errors.Errorf("nws: unknown unit %q", v.UnitCode)
+	}
+
+	to, ok := lookupUnit(unitCode)
+	if !ok {
+		return nil, errors.Errorf("nws: unknown unit %q", unitCode)
+	}
+
+	if from.quantity != to.quantity {
+		return nil, errors.Errorf("nws: can't convert %s (%s) to %s (%s)", v.UnitCode, from.quantity, unitCode, to.quantity)
+	}
+
+	if from == to {
+		converted := *v.Value
+
+		return &converted, nil
+	}
+
+	converted := (*v.Value*from.factor + from.offset - to.offset) / to.factor
+
+	return &converted, nil
+}
@@ -0,0 +1,73 @@
+package nws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantitativeValueIn(t *testing.T) {
+	t.Parallel()
+
+	value := func(v float64) *float64 { return &v }
+
+	for _, tc := range []struct {
+		name        string
+		give        QuantitativeValue
+		giveUnit    string
+		expected    *float64
+		expectedErr string
+	}{
+		{name: "same_unit", give: QuantitativeValue{Value: value(21.5), UnitCode: "wmoUnit:degC"}, giveUnit: UnitCelsius, expected: value(21.5)},
+		{name: "legacy_prefix", give: QuantitativeValue{Value: value(21.5), UnitCode: "unit:degC"}, giveUnit: UnitCelsius, expected: value(21.5)},
+		{name: "fahrenheit", give: QuantitativeValue{Value: value(212), UnitCode: "wmoUnit:degF"}, giveUnit: UnitCelsius, expected: value(100)},
+		{name: "kelvin", give: QuantitativeValue{Value: value(273.15), UnitCode: "wmoUnit:K"}, giveUnit: UnitCelsius, expected: value(0)},
+		{name: "metres_per_second", give: QuantitativeValue{Value: value(10), UnitCode: "wmoUnit:m_s-1"}, giveUnit: UnitKilometresPerHour, expected: value(36)},
+		{name: "knots", give: QuantitativeValue{Value: value(10), UnitCode: "wmoUnit:kt"}, giveUnit: UnitKilometresPerHour, expected: value(18.52)},
+		{name: "pascals", give: QuantitativeValue{Value: value(101325), UnitCode: "wmoUnit:Pa"}, giveUnit: UnitHectopascals, expected: value(1013.25)},
+		{name: "metres", give: QuantitativeValue{Value: value(16090), UnitCode: "wmoUnit:m"}, giveUnit: UnitKilometres, expected: value(16.09)},
+		{name: "millimetres", give: QuantitativeValue{Value: value(0.0025), UnitCode: "wmoUnit:m"}, giveUnit: UnitMillimetres, expected: value(2.5)},
+		{name: "percent", give: QuantitativeValue{Value: value(65.2), UnitCode: "wmoUnit:percent"}, giveUnit: UnitPercent, expected: value(65.2)},
+		{name: "degrees", give: QuantitativeValue{Value: value(270), UnitCode: "wmoUnit:degree_(angle)"}, giveUnit: UnitDegrees, expected: value(270)},
+		{name: "no_value", give: QuantitativeValue{UnitCode: "wmoUnit:degC", QualityControl: "Z"}, giveUnit: UnitCelsius},
+		{name: "rejected_value", give: QuantitativeValue{Value: value(99), UnitCode: "wmoUnit:degC", QualityControl: "X"}, giveUnit: UnitCelsius},
+		{
+			name:        "unknown_unit",
+			give:        QuantitativeValue{Value: value(1), UnitCode: "wmoUnit:furlong"},
+			giveUnit:    UnitKilometres,
+			expectedErr: `nws: unknown unit "wmoUnit:furlong"`,
+		},
+		{
+			name:        "unknown_target_unit",
+			give:        QuantitativeValue{Value: value(1), UnitCode: "wmoUnit:m"},
+			giveUnit:    "wmoUnit:furlong",
+			expectedErr: `nws: unknown unit "wmoUnit:furlong"`,
+		},
+		{
+			name:        "different_quantities",
+			give:        QuantitativeValue{Value: value(1), UnitCode: "wmoUnit:m"},
+			giveUnit:    UnitCelsius,
+			expectedErr: "nws: can't convert wmoUnit:m (length) to wmoUnit:degC (temperature)",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tc.give.In(tc.giveUnit)
+
+			if tc.expected == nil {
+				assert.Nil(t, actual)
+			} else if assert.NotNil(t, actual) {
+				assert.InDelta(t, *tc.expected, *actual, 1e-9)
+			}
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
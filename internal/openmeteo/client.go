@@ -0,0 +1,135 @@
+package openmeteo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather/internal/platform/apiclient"
+)
+
+// tracerName is the name of the tracer used by the [Client], see [WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/openmeteo"
+
+// NewOptions are the options for the [New] function.
+type NewOptions struct {
+	client               HTTPClient
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracerProvider       trace.TracerProvider
+}
+
+// NewWithHTTPClient sets the HTTPClient in the [New] function.
+func NewWithHTTPClient(client HTTPClient) func(*NewOptions) {
+	return func(o *NewOptions) {
+		o.client = client
+	}
+}
+
+// WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
+// the context.
+func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.getLoggerFromContext = getLoggerFromContext
+	}
+}
+
+// WithTracerProvider sets the provider of the tracer used to trace requests to the API.
+func WithTracerProvider(tracerProvider trace.TracerProvider) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// Client is used to interact with the Open-Meteo API.
+type Client struct {
+	client               *apiclient.Client
+	endpointURL          string
+	geocodingEndpointURL string
+}
+
+// New creates a new [Client] querying weather from endpointURL (e.g
+// "https://api.open-meteo.com/v1") and resolving city names with geocodingEndpointURL (e.g
+// "https://geocoding-api.open-meteo.com/v1").
+func New(endpointURL, geocodingEndpointURL string, optionOverrides ...func(*NewOptions)) *Client {
+	var options NewOptions
+
+	for _, optionOverride := range optionOverrides {
+		optionOverride(&options)
+	}
+
+	// Options left unset keep the defaults of apiclient.New.
+	var apiClientOptions []func(*apiclient.NewOptions)
+
+	if options.client != nil {
+		apiClientOptions = append(apiClientOptions, apiclient.NewWithHTTPClient(options.client))
+	}
+
+	if options.getLoggerFromContext != nil {
+		apiClientOptions = append(apiClientOptions, apiclient.WithGetLoggerFromContext(options.getLoggerFromContext))
+	}
+
+	if options.tracerProvider != nil {
+		apiClientOptions = append(apiClientOptions, apiclient.WithTracerProvider(options.tracerProvider))
+	}
+
+	return &Client{
+		client:               apiclient.New("openmeteo", tracerName, apiClientOptions...),
+		endpointURL:          endpointURL,
+		geocodingEndpointURL: geocodingEndpointURL,
+	}
+}
+
+// requiredFields are the fields of a response that must be present, being decoded from the
+// response body & validated by get.
+type requiredFields interface {
+	validate() error
+}
+
+// get sends a GET request to the endpoint at path of baseURL with query, decoding a successful
+// response body into apiResponse & required (if not nil). An unsuccessful response is returned
+// as an [*APIError].
+func (c *Client) get(
+	ctx context.Context,
+	baseURL string,
+	path string,
+	query url.Values,
+	apiResponse interface{},
+	required requiredFields,
+) error {
+	req := apiclient.Request{
+		Route: path,
+		URL:   fmt.Sprintf("%s/%s", baseURL, path),
+		Query: query,
+	}
+
+	return c.client.Get(ctx, &req, func(res *http.Response, body []byte) error {
+		if res.StatusCode != http.StatusOK {
+			return newAPIError(res.StatusCode, body)
+		}
+
+		if body != nil {
+			if err := json.Unmarshal(body, apiResponse); err != nil {
+				return errors.Wrap(err, "openmeteo: decode body")
+			}
+
+			if required != nil {
+				if err := json.Unmarshal(body, required); err != nil {
+					return errors.Wrap(err, "openmeteo: decode body")
+				}
+			}
+		}
+
+		// Without a body, every required field is missing.
+		if required != nil {
+			return required.validate()
+		}
+
+		return nil
+	})
+}
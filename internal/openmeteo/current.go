@@ -0,0 +1,109 @@
+package openmeteo
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// currentVariables are the variables requested from the "Forecast" endpoint for the current
+// weather. See https://open-meteo.com/en/docs#current.
+var currentVariables = []string{
+	"temperature_2m",
+	"wind_speed_10m",
+	"wind_direction_10m",
+	"wind_gusts_10m",
+	"apparent_temperature",
+	"relative_humidity_2m",
+	"pressure_msl",
+	"cloud_cover",
+	"precipitation",
+	"weather_code",
+}
+
+// CurrentSuccess is a successful response from the Open-Meteo API "Forecast" endpoint,
+// requesting the current weather.
+type CurrentSuccess struct {
+	Latitude         float64        `json:"latitude"`  // The latitude of the grid cell used, in decimal degrees.
+	Longitude        float64        `json:"longitude"` // The longitude of the grid cell used, in decimal degrees.
+	UTCOffsetSeconds int            `json:"utc_offset_seconds"`
+	Current          CurrentWeather `json:"current"`
+}
+
+// CurrentWeather is part of a successful response from the Open-Meteo API "Forecast" endpoint.
+//
+// Pointer fields are optional and are nil when not included in the response.
+type CurrentWeather struct {
+	Time                int64    `json:"time"`                           // When the data is valid for, unix time in seconds.
+	Temperature         float64  `json:"temperature_2m"`                 // The temperature (at 2m) in degrees celsius.
+	WindSpeed           float64  `json:"wind_speed_10m"`                 // The windspeed (at 10m) in km/h.
+	WindDirection       *float64 `json:"wind_direction_10m,omitempty"`   // The direction the wind is blowing from in degrees (meteorological).
+	WindGusts           *float64 `json:"wind_gusts_10m,omitempty"`       // The wind gust speed (at 10m) in km/h.
+	ApparentTemperature *float64 `json:"apparent_temperature,omitempty"` // The apparent temperature in degrees celsius.
+	RelativeHumidity    *float64 `json:"relative_humidity_2m,omitempty"` // The relative humidity (at 2m) as a percentage.
+	PressureMSL         *float64 `json:"pressure_msl,omitempty"`         // The atmospheric pressure (at sea level) in hPa.
+	CloudCover          *float64 `json:"cloud_cover,omitempty"`          // The cloud cover as a percentage.
+	Precipitation       *float64 `json:"precipitation,omitempty"`        // The precipitation of the preceding hour in mm.
+	WeatherCode         *int     `json:"weather_code,omitempty"`         // The WMO weather interpretation code, see https://open-meteo.com/en/docs.
+}
+
+// currentRequiredFields are the fields of a response from the "Forecast" endpoint that must be
+// present. Without them, the weather would be zeroes.
+type currentRequiredFields struct {
+	Current *struct {
+		Temperature *float64 `json:"temperature_2m"`
+		WindSpeed   *float64 `json:"wind_speed_10m"`
+	} `json:"current"`
+}
+
+// validate returns an error if a required field is missing.
+func (f *currentRequiredFields) validate() error {
+	if f.Current == nil {
+		return errors.New("openmeteo: response has no current weather")
+	}
+
+	if f.Current.Temperature == nil {
+		return errors.New("openmeteo: response has no current.temperature_2m")
+	}
+
+	if f.Current.WindSpeed == nil {
+		return errors.New("openmeteo: response has no current.wind_speed_10m")
+	}
+
+	return nil
+}
+
+// CurrentByCityName returns the current weather for a city, resolving it to a location with
+// [Client.LocationByCityName] first.
+func (c *Client) CurrentByCityName(ctx context.Context, cityName string) (*CurrentSuccess, error) {
+	location, err := c.LocationByCityName(ctx, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CurrentByCoordinates(ctx, location.Latitude, location.Longitude)
+}
+
+// CurrentByCoordinates returns the current weather for a latitude/longitude (in decimal
+// degrees).
+func (c *Client) CurrentByCoordinates(ctx context.Context, latitude, longitude float64) (*CurrentSuccess, error) {
+	query := url.Values{
+		"latitude":           []string{strconv.FormatFloat(latitude, 'f', -1, 64)},
+		"longitude":          []string{strconv.FormatFloat(longitude, 'f', -1, 64)},
+		"current":            []string{strings.Join(currentVariables, ",")},
+		"temperature_unit":   []string{"celsius"},
+		"wind_speed_unit":    []string{"kmh"},
+		"precipitation_unit": []string{"mm"},
+		"timeformat":         []string{"unixtime"},
+	}
+
+	var apiResponse CurrentSuccess
+	if err := c.get(ctx, c.endpointURL, "forecast", query, &apiResponse, &currentRequiredFields{}); err != nil {
+		return nil, err
+	}
+
+	return &apiResponse, nil
+}
@@ -0,0 +1,192 @@
+package openmeteo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stubResponse creates an http response with status code & body.
+func stubResponse(statusCode int, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+func TestClientCurrentByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		withClient  *Client
+		giveContext context.Context
+		expected    *CurrentSuccess
+		expectedErr string
+	}{
+		{
+			name: "success",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/forecast", req.URL.Path)
+					assert.Equal(t, "-33.87", req.URL.Query().Get("latitude"))
+					assert.Equal(t, "151.21", req.URL.Query().Get("longitude"))
+					assert.Equal(t, "unixtime", req.URL.Query().Get("timeformat"))
+					assert.Contains(t, req.URL.Query().Get("current"), "temperature_2m")
+
+					return stubResponse(http.StatusOK, `{"latitude":-33.875,"longitude":151.25,"current":{"time":1605089400,"temperature_2m":12.5,"wind_speed_10m":7.2}}`), nil
+				},
+			})),
+			giveContext: context.Background(),
+			expected: &CurrentSuccess{
+				Latitude:  -33.875,
+				Longitude: 151.25,
+				Current:   CurrentWeather{Time: 1605089400, Temperature: 12.5, WindSpeed: 7.2},
+			},
+		},
+		{
+			name: "unexpected_response_type",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `"ABCDEFG"`), nil
+				},
+			})),
+			giveContext: context.Background(),
+			expectedErr: "openmeteo: decode body: json: cannot unmarshal string into Go value of type openmeteo.CurrentSuccess",
+		},
+		{
+			name: "missing_current",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `{"latitude":-33.875,"longitude":151.25}`), nil
+				},
+			})),
+			giveContext: context.Background(),
+			expectedErr: "openmeteo: response has no current weather",
+		},
+		{
+			name: "missing_temperature",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `{"current":{"time":1605089400,"wind_speed_10m":7.2}}`), nil
+				},
+			})),
+			giveContext: context.Background(),
+			expectedErr: "openmeteo: response has no current.temperature_2m",
+		},
+		{
+			name: "api_error",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusBadRequest, `{"error":true,"reason":"Test123"}`), nil
+				},
+			})),
+			giveContext: context.Background(),
+			expectedErr: "openmeteo: unexpected response status code 400: Test123",
+		},
+		{
+			name: "http_client_error",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("intentional test error")
+				},
+			})),
+			giveContext: context.Background(),
+			expectedErr: "openmeteo: execute request: intentional test error",
+		},
+		{
+			name:        "nil_context",
+			withClient:  New("", "", NewWithHTTPClient(&HTTPClientMock{})),
+			giveContext: nil,
+			expectedErr: "openmeteo: create request: net/http: nil Context",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := tc.giveContext
+			if tc.giveContext != nil {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(tc.giveContext)
+				t.Cleanup(cancel)
+			}
+
+			actual, err := tc.withClient.CurrentByCoordinates(ctx, -33.87, 151.21)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientCurrentByCityName(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := New("https://api.example.com/v1", "https://geocoding.example.com/v1", NewWithHTTPClient(&HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Host + req.URL.Path {
+			case "geocoding.example.com/v1/search":
+				assert.Equal(t, "Sydney", req.URL.Query().Get("name"))
+
+				return stubResponse(http.StatusOK, `{"results":[{"id":2147714,"name":"Sydney","latitude":-33.86785,"longitude":151.20732}]}`), nil
+			case "api.example.com/v1/forecast":
+				assert.Equal(t, "-33.86785", req.URL.Query().Get("latitude"))
+				assert.Equal(t, "151.20732", req.URL.Query().Get("longitude"))
+
+				return stubResponse(http.StatusOK, `{"current":{"time":1605089400,"temperature_2m":12.5,"wind_speed_10m":7.2}}`), nil
+			default:
+				return nil, errors.New("unexpected request")
+			}
+		},
+	}))
+
+	actual, err := client.CurrentByCityName(ctx, "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, &CurrentSuccess{Current: CurrentWeather{Time: 1605089400, Temperature: 12.5, WindSpeed: 7.2}}, actual)
+}
+
+func TestClientCurrentByCoordinatesTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New("https://example.com", "",
+		NewWithHTTPClient(&HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			},
+		}),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.CurrentByCoordinates(context.Background(), -33.87, 151.21)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "openmeteo GET /forecast", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/forecast"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusBadGateway))
+	}
+}
@@ -0,0 +1,8 @@
+// Package openmeteo provides a client to interact with the [Open-Meteo API], including its
+// [geocoding API] used to resolve city names. The API needs no key.
+//
+// [Open-Meteo API]: https://open-meteo.com/en/docs
+// [geocoding API]: https://open-meteo.com/en/docs/geocoding-api
+package openmeteo
+
+//go:generate moq -out moq_test.go . HTTPClient
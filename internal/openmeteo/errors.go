@@ -0,0 +1,65 @@
+package openmeteo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of errors returned by the [Client], to be matched with errors.Is.
+var (
+	ErrInvalidQuery = errors.New("openmeteo: invalid query")
+	ErrRateLimited  = errors.New("openmeteo: rate limited")
+
+	// ErrCityNotFound is returned when the geocoding API has no match for a city name.
+	ErrCityNotFound = errors.New("openmeteo: city not found")
+)
+
+// APIError is an unsuccessful response from the Open-Meteo API.
+type APIError struct {
+	StatusCode int    // The response status code.
+	Reason     string // The "reason" of the response body, e.g "Latitude must be in range of -90 to 90°.".
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("openmeteo: unexpected response status code %v", e.StatusCode)
+	}
+
+	return fmt.Sprintf("openmeteo: unexpected response status code %v: %s", e.StatusCode, e.Reason)
+}
+
+// Is reports whether the error is of the class target, one of [ErrInvalidQuery] or
+// [ErrRateLimited].
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidQuery:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// apiErrorResponse is the body of an unsuccessful response from the Open-Meteo API.
+type apiErrorResponse struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// newAPIError creates an [APIError] for an unsuccessful response with the given status code &
+// body (which may not be in the expected form).
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var errorResponse apiErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiErr.Reason = errorResponse.Reason
+	}
+
+	return apiErr
+}
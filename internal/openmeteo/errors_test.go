@@ -0,0 +1,73 @@
+package openmeteo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "invalid_query", give: &APIError{StatusCode: http.StatusBadRequest}, expected: ErrInvalidQuery},
+		{name: "rate_limited", give: &APIError{StatusCode: http.StatusTooManyRequests}, expected: ErrRateLimited},
+		{name: "server_error", give: &APIError{StatusCode: http.StatusInternalServerError}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrInvalidQuery, ErrRateLimited} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		giveStatusCode int
+		giveBody       []byte
+		expected       *APIError
+	}{
+		{
+			name:           "reason",
+			giveStatusCode: http.StatusBadRequest,
+			giveBody:       []byte(`{"error":true,"reason":"Test123"}`),
+			expected:       &APIError{StatusCode: http.StatusBadRequest, Reason: "Test123"},
+		},
+		{
+			name:           "no_body",
+			giveStatusCode: http.StatusBadGateway,
+			expected:       &APIError{StatusCode: http.StatusBadGateway},
+		},
+		{
+			name:           "unexpected_body",
+			giveStatusCode: http.StatusBadGateway,
+			giveBody:       []byte(`<html></html>`),
+			expected:       &APIError{StatusCode: http.StatusBadGateway},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, newAPIError(tc.giveStatusCode, tc.giveBody))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package openmeteo
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Location is a result from the Open-Meteo geocoding API "Search" endpoint.
+type Location struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`  // In decimal degrees.
+	Longitude float64 `json:"longitude"` // In decimal degrees.
+	Country   string  `json:"country,omitempty"`
+	Timezone  string  `json:"timezone,omitempty"` // e.g "Australia/Sydney".
+}
+
+// searchResponse is a response from the Open-Meteo geocoding API "Search" endpoint. Results is
+// omitted when nothing matches.
+type searchResponse struct {
+	Results []Location `json:"results"`
+}
+
+// LocationByCityName returns the location best matching a city name. If there is no match, the
+// error is an [ErrCityNotFound].
+func (c *Client) LocationByCityName(ctx context.Context, cityName string) (*Location, error) {
+	if cityName == "" {
+		return nil, errors.New("openmeteo: cityname is required")
+	}
+
+	query := url.Values{
+		"name":     []string{cityName},
+		"count":    []string{"1"},
+		"language": []string{"en"},
+		"format":   []string{"json"},
+	}
+
+	var apiResponse searchResponse
+	if err := c.get(ctx, c.geocodingEndpointURL, "search", query, &apiResponse, nil); err != nil {
+		return nil, err
+	}
+
+	if len(apiResponse.Results) == 0 {
+		return nil, errors.Wrapf(ErrCityNotFound, "openmeteo: search %q", cityName)
+	}
+
+	return &apiResponse.Results[0], nil
+}
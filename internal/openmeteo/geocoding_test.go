@@ -0,0 +1,91 @@
+package openmeteo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientLocationByCityName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		withClient   *Client
+		giveCityName string
+		expected     *Location
+		expectedErr  string
+	}{
+		{
+			name: "success",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/search", req.URL.Path)
+					assert.Equal(t, "Sydney", req.URL.Query().Get("name"))
+					assert.Equal(t, "1", req.URL.Query().Get("count"))
+
+					return stubResponse(http.StatusOK, `{"results":[{"id":2147714,"name":"Sydney","latitude":-33.86785,"longitude":151.20732,"country":"Australia","timezone":"Australia/Sydney"}]}`), nil
+				},
+			})),
+			giveCityName: "Sydney",
+			expected: &Location{
+				ID:        2147714,
+				Name:      "Sydney",
+				Latitude:  -33.86785,
+				Longitude: 151.20732,
+				Country:   "Australia",
+				Timezone:  "Australia/Sydney",
+			},
+		},
+		{
+			name: "not_found",
+			withClient: New("", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return stubResponse(http.StatusOK, `{"generationtime_ms":0.5}`), nil
+				},
+			})),
+			giveCityName: "Atlantis",
+			expectedErr:  "openmeteo: search \"Atlantis\": openmeteo: city not found",
+		},
+		{
+			name:         "missing_city_name",
+			withClient:   New("", "", NewWithHTTPClient(&HTTPClientMock{})),
+			giveCityName: "",
+			expectedErr:  "openmeteo: cityname is required",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, err := tc.withClient.LocationByCityName(ctx, tc.giveCityName)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("not_found_is_classified", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("", "", NewWithHTTPClient(&HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return stubResponse(http.StatusOK, `{}`), nil
+			},
+		}))
+
+		_, err := client.CurrentByCityName(context.Background(), "Atlantis")
+		assert.ErrorIs(t, err, ErrCityNotFound)
+	})
+}
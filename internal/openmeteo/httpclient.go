@@ -0,0 +1,8 @@
+package openmeteo
+
+import "net/http"
+
+// HTTPClient is an HTTP client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
@@ -2,18 +2,35 @@ package openweather
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/byatesrae/weather/internal/platform/nooplogr"
 )
 
+// tracerName is the name of the tracer used by the [Client], see [WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/openweather"
+
+// maxBodySize is the maximum size of a response body read from the API. Its responses are a
+// few KB, so a larger body is an error rather than something worth buffering.
+const maxBodySize = 1 << 20
+
 // NewOptions are the options for the [New] function.
 type NewOptions struct {
 	client               HTTPClient
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracerProvider       trace.TracerProvider
 }
 
 // NewWithHTTPClient sets the HTTPClient in the [New] function.
@@ -31,12 +48,24 @@ func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) log
 	}
 }
 
+// WithTracerProvider sets the provider of the tracer used to trace requests to the API.
+func WithTracerProvider(tracerProvider trace.TracerProvider) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
 // Client is used to interact with the Openweather API.
 type Client struct {
 	client               HTTPClient
 	endpointURL          string
 	apiKey               string
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracer               trace.Tracer
+
+	// Requests are skipped until this time, as asked by the API (see [APIError.RetryAfter]).
+	skippedUntil time.Time
+	m            sync.Mutex
 }
 
 // New creates a new [Client].
@@ -48,6 +77,7 @@ func New(endpointURL, apiKey string, optionOverrides ...func(*NewOptions)) *Clie
 		getLoggerFromContext: func(ctx context.Context) logr.Logger {
 			return noopLogger
 		},
+		tracerProvider: trace.NewNoopTracerProvider(),
 	}
 
 	for _, optionOverride := range optionOverrides {
@@ -59,5 +89,144 @@ func New(endpointURL, apiKey string, optionOverrides ...func(*NewOptions)) *Clie
 		apiKey:               apiKey,
 		endpointURL:          endpointURL,
 		getLoggerFromContext: options.getLoggerFromContext,
+		tracer:               options.tracerProvider.Tracer(tracerName),
+	}
+}
+
+// requiredFields are the fields of a response that must be present, being decoded from the
+// response body & validated by get.
+type requiredFields interface {
+	validate() error
+}
+
+// get sends a GET request to the endpoint at path with query (merged with the parameters
+// common to all requests), decoding a successful response body into apiResponse & required
+// (if not nil). An unsuccessful response is returned as an [*APIError].
+func (c *Client) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	apiResponse interface{},
+	required requiredFields,
+) (err error) {
+	logger := c.getLoggerFromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", c.endpointURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "openweather: create request")
+	}
+
+	// The API asked for requests to stop for a while (e.g. having been rate limited).
+	if remaining := c.skipped(); remaining > 0 {
+		return errors.Wrapf(&APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: remaining}, "openweather: skipping request for another %v", remaining)
+	}
+
+	q := req.URL.Query()
+	q.Add("appid", c.apiKey)
+	q.Add("units", "metric")
+
+	for k, v := range query {
+		q[k] = v
+	}
+
+	req.URL.RawQuery = q.Encode()
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		"openweather GET /"+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(http.MethodGet),
+			semconv.HTTPURLKey.String(endpoint), // Without the query, which holds credentials.
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}()
+
+	req = req.WithContext(ctx)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "openweather: execute request")
 	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+
+	var body []byte
+
+	if res.Body != nil {
+		defer func() {
+			err := res.Body.Close()
+			if err != nil {
+				logger.Error(err, "Error closing response body.")
+			}
+		}()
+
+		// One byte past the limit is read to tell a body at the limit from one over it.
+		if body, err = io.ReadAll(io.LimitReader(res.Body, maxBodySize+1)); err != nil {
+			return errors.Wrap(err, "openweather: read body")
+		}
+
+		if len(body) > maxBodySize {
+			return errors.Errorf("openweather: read body: larger than %d bytes", maxBodySize)
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		apiErr := newAPIError(res.StatusCode, res.Header, body, time.Now())
+		if apiErr.RetryAfter > 0 {
+			c.skipUntil(time.Now().Add(apiErr.RetryAfter))
+		}
+
+		return apiErr
+	}
+
+	if body != nil {
+		if err := json.Unmarshal(body, apiResponse); err != nil {
+			return errors.Wrap(err, "openweather: decode body")
+		}
+
+		if required != nil {
+			if err := json.Unmarshal(body, required); err != nil {
+				return errors.Wrap(err, "openweather: decode body")
+			}
+		}
+	}
+
+	// Without a body, every required field is missing.
+	if required != nil {
+		return required.validate()
+	}
+
+	return nil
+}
+
+// skipUntil skips requests to the API until t, as asked by the API (see [APIError.RetryAfter]).
+func (c *Client) skipUntil(t time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if t.After(c.skippedUntil) {
+		c.skippedUntil = t
+	}
+}
+
+// skipped returns how much longer requests to the API are skipped for (0 if they aren't).
+func (c *Client) skipped() time.Duration {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if remaining := time.Until(c.skippedUntil); remaining > 0 {
+		return remaining
+	}
+
+	return 0
 }
@@ -0,0 +1,113 @@
+package openweather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of [APIError], to be matched with errors.Is.
+var (
+	ErrInvalidKey   = errors.New("openweather: invalid api key")
+	ErrCityNotFound = errors.New("openweather: city not found")
+	ErrRateLimited  = errors.New("openweather: rate limited")
+	ErrInvalidQuery = errors.New("openweather: invalid query")
+)
+
+// APIError is an unsuccessful response from the Openweather API.
+type APIError struct {
+	StatusCode int           // The response status code.
+	Code       string        // The "cod" of the response body, usually the status code.
+	Message    string        // The "message" of the response body, e.g "city not found".
+	RetryAfter time.Duration // How long to wait before trying again, from the "Retry-After" header (0 if not set).
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("openweather: unexpected response status code %v", e.StatusCode)
+	}
+
+	return fmt.Sprintf("openweather: unexpected response status code %v: %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether the error is of the class target, one of [ErrInvalidKey],
+// [ErrCityNotFound], [ErrRateLimited] or [ErrInvalidQuery].
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidKey:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrCityNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrInvalidQuery:
+		return e.StatusCode == http.StatusBadRequest
+	default:
+		return false
+	}
+}
+
+// apiErrorResponse is the body of an unsuccessful response from the Openweather API.
+type apiErrorResponse struct {
+	Code    json.RawMessage `json:"cod"` // Either a number or a string.
+	Message string          `json:"message"`
+}
+
+// newAPIError creates an [APIError] for an unsuccessful response with the given status code,
+// header & body (which may not be in the expected form) received at now.
+func newAPIError(statusCode int, header http.Header, body []byte, now time.Time) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		RetryAfter: parseRetryAfter(header.Get("Retry-After"), now),
+	}
+
+	var errorResponse apiErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiErr.Code = strings.Trim(string(errorResponse.Code), `"`)
+		apiErr.Message = errorResponse.Message
+	}
+
+	return apiErr
+}
+
+// maxRetryAfter is the longest "Retry-After" honoured. A longer (e.g mistaken) value would
+// otherwise stop the client querying the API for however long it says.
+const maxRetryAfter = time.Minute * 5
+
+// parseRetryAfter parses a "Retry-After" header value, being either a number of seconds or an
+// HTTP date, into the duration to wait from now, at most maxRetryAfter. An empty or invalid
+// value is 0.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		// Compared in seconds, as a large value would overflow a time.Duration.
+		if seconds > int(maxRetryAfter/time.Second) {
+			return maxRetryAfter
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		if wait := date.Sub(now); wait < maxRetryAfter {
+			return wait
+		}
+
+		return maxRetryAfter
+	}
+
+	return 0
+}
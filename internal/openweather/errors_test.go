@@ -0,0 +1,69 @@
+package openweather
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "invalid_key", give: &APIError{StatusCode: http.StatusUnauthorized}, expected: ErrInvalidKey},
+		{name: "city_not_found", give: &APIError{StatusCode: http.StatusNotFound}, expected: ErrCityNotFound},
+		{name: "rate_limited", give: &APIError{StatusCode: http.StatusTooManyRequests}, expected: ErrRateLimited},
+		{name: "invalid_query", give: &APIError{StatusCode: http.StatusBadRequest}, expected: ErrInvalidQuery},
+		{name: "server_error", give: &APIError{StatusCode: http.StatusInternalServerError}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrInvalidKey, ErrCityNotFound, ErrRateLimited, ErrInvalidQuery} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		give     string
+		expected time.Duration
+	}{
+		{name: "empty", give: "", expected: 0},
+		{name: "seconds", give: "120", expected: time.Minute * 2},
+		{name: "negative_seconds", give: "-1", expected: 0},
+		{name: "seconds_over_max", give: "86400", expected: maxRetryAfter},
+		{name: "seconds_overflow", give: "9223372036854775807", expected: maxRetryAfter},
+		{name: "date", give: "Wed, 11 Nov 2020 10:11:10 GMT", expected: time.Minute},
+		{name: "date_over_max", give: "Thu, 12 Nov 2020 10:10:10 GMT", expected: maxRetryAfter},
+		{name: "past_date", give: "Wed, 11 Nov 2020 10:09:10 GMT", expected: 0},
+		{name: "invalid", give: "Test123", expected: 0},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, parseRetryAfter(tc.give, now))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package openweather
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ForecastSuccess is a successful response from the Openweather API "5 day / 3 hour Forecast"
+// endpoint.
+type ForecastSuccess struct {
+	List []ForecastItem `json:"list"`
+	City ForecastCity   `json:"city"`
+}
+
+// ForecastItem is part of a successful response from the Openweather API "5 day / 3 hour Forecast"
+// endpoint. It is the forecast for a 3 hour period.
+type ForecastItem struct {
+	Time       int64                 `json:"dt"` // The start of the period, unix time in seconds.
+	Main       WeatherMain           `json:"main"`
+	Wind       WeatherWind           `json:"wind"`
+	Weather    []WeatherCondition    `json:"weather"`
+	Clouds     *WeatherClouds        `json:"clouds,omitempty"`
+	Visibility *float64              `json:"visibility,omitempty"` // The visibility in metres.
+	Rain       *WeatherPrecipitation `json:"rain,omitempty"`
+	Snow       *WeatherPrecipitation `json:"snow,omitempty"`
+}
+
+// ForecastCity is part of a successful response from the Openweather API "5 day / 3 hour Forecast"
+// endpoint.
+type ForecastCity struct {
+	Name     string `json:"name"`
+	Timezone int    `json:"timezone"` // The city's shift from UTC in seconds.
+}
+
+// ForecastByCityName returns a 5 day forecast (in 3 hour periods) for a city. count limits the
+// number of periods returned, zero means no limit.
+func (c *Client) ForecastByCityName(ctx context.Context, cityName string, count int) (*ForecastSuccess, error) {
+	if cityName == "" {
+		return nil, errors.New("openweather: cityname is required")
+	}
+
+	query := url.Values{"q": []string{cityName}}
+	if count > 0 {
+		query.Set("cnt", strconv.Itoa(count))
+	}
+
+	var apiResponse ForecastSuccess
+	if err := c.get(ctx, "forecast", query, &apiResponse, nil); err != nil {
+		return nil, err
+	}
+
+	return &apiResponse, nil
+}
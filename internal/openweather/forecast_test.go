@@ -0,0 +1,86 @@
+package openweather
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceForecastByCityName(t *testing.T) {
+	t.Parallel()
+
+	dummyResult := ForecastSuccess{
+		List: []ForecastItem{
+			{Time: 1605052800, Main: WeatherMain{Temperature: 123}, Wind: WeatherWind{WindSpeed: 456}},
+		},
+		City: ForecastCity{Name: "Sydney", Timezone: 36000},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		giveCityName  string
+		giveCount     int
+		expectedCount string
+		expected      *ForecastSuccess
+		expectedErr   string
+	}{
+		{
+			name:          "success",
+			giveCityName:  "Sydney",
+			giveCount:     8,
+			expectedCount: "8",
+			expected:      &dummyResult,
+		},
+		{
+			name:          "success_no_count",
+			giveCityName:  "Sydney",
+			giveCount:     0,
+			expectedCount: "",
+			expected:      &dummyResult,
+		},
+		{
+			name:         "missing_city_name",
+			giveCityName: "",
+			expectedErr:  "openweather: cityname is required",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			client := New("http://openweather", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/forecast", req.URL.Path)
+					assert.Equal(t, tc.giveCityName, req.URL.Query().Get("q"))
+					assert.Equal(t, tc.expectedCount, req.URL.Query().Get("cnt"))
+
+					payload, err := json.Marshal(dummyResult)
+					assert.NoError(t, err)
+
+					r := io.NopCloser(bytes.NewReader(payload))
+
+					return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+				},
+			}))
+
+			actual, err := client.ForecastByCityName(ctx, tc.giveCityName, tc.giveCount)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,70 +2,113 @@ package openweather
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
 
 // WeatherSuccess is a successful response from the Openweather API "Weather" endpoint.
+//
+// Pointer fields are optional and are nil when not included in the response.
 type WeatherSuccess struct {
-	Main WeatherMain `json:"main"`
-	Wind WeatherWind `json:"wind"`
+	Time       *int64                `json:"dt,omitempty"` // When the data was calculated, unix time in seconds.
+	Main       WeatherMain           `json:"main"`
+	Wind       WeatherWind           `json:"wind"`
+	Weather    []WeatherCondition    `json:"weather"`
+	Clouds     *WeatherClouds        `json:"clouds,omitempty"`
+	Visibility *float64              `json:"visibility,omitempty"` // The visibility in metres.
+	Rain       *WeatherPrecipitation `json:"rain,omitempty"`
+	Snow       *WeatherPrecipitation `json:"snow,omitempty"`
 }
 
 // WeatherMain is part of a successful response from the Openweather API "Weather" endpoint.
 type WeatherMain struct {
-	Temperature float64 `json:"temp"` // The location temperature in degrees celsius.
+	Temperature float64  `json:"temp"`                 // The location temperature in degrees celsius.
+	FeelsLike   *float64 `json:"feels_like,omitempty"` // The apparent temperature in degrees celsius.
+	Pressure    *float64 `json:"pressure,omitempty"`   // The atmospheric pressure (at sea level) in hPa.
+	Humidity    *float64 `json:"humidity,omitempty"`   // The relative humidity as a percentage.
 }
 
 // WeatherWind is part of a successful response from the Openweather API "Weather" endpoint.
 type WeatherWind struct {
-	WindSpeed float64 `json:"speed"` // The location windspeed in m/s.
+	WindSpeed float64  `json:"speed"`          // The location windspeed in m/s.
+	Direction *float64 `json:"deg,omitempty"`  // The direction the wind is blowing from in degrees (meteorological).
+	Gust      *float64 `json:"gust,omitempty"` // The wind gust speed in m/s.
 }
 
-// WeatherByCityName returns a summary of the weather for a city.
-func (c *Client) WeatherByCityName(ctx context.Context, cityName string) (*WeatherSuccess, error) {
-	logger := c.getLoggerFromContext(ctx)
+// WeatherCondition is part of a successful response from the Openweather API "Weather" endpoint.
+// See https://openweathermap.org/weather-conditions.
+type WeatherCondition struct {
+	ID          int    `json:"id"`          // The weather condition ID, e.g 500 (light rain).
+	Main        string `json:"main"`        // The group of weather parameters, e.g "Rain".
+	Description string `json:"description"` // The weather condition within the group, e.g "light rain".
+}
 
-	if cityName == "" {
-		return nil, errors.New("openweather: cityname is required")
+// WeatherClouds is part of a successful response from the Openweather API "Weather" endpoint.
+type WeatherClouds struct {
+	All *float64 `json:"all,omitempty"` // The cloud cover as a percentage.
+}
+
+// WeatherPrecipitation is part of a successful response from the Openweather API "Weather" endpoint.
+type WeatherPrecipitation struct {
+	OneHour    *float64 `json:"1h,omitempty"` // The volume for the last hour in mm.
+	ThreeHours *float64 `json:"3h,omitempty"` // The volume for the last 3 hours in mm.
+}
+
+// weatherRequiredFields are the fields of a response from the "Weather" endpoint that must be
+// present. Without them, the weather would be zeroes.
+type weatherRequiredFields struct {
+	Main struct {
+		Temperature *float64 `json:"temp"`
+	} `json:"main"`
+	Wind struct {
+		WindSpeed *float64 `json:"speed"`
+	} `json:"wind"`
+}
+
+// validate returns an error if a required field is missing.
+func (f *weatherRequiredFields) validate() error {
+	if f.Main.Temperature == nil {
+		return errors.New("openweather: response has no main.temp")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/weather", c.endpointURL), http.NoBody)
-	if err != nil {
-		return nil, errors.Wrap(err, "openweather: create request")
+	if f.Wind.WindSpeed == nil {
+		return errors.New("openweather: response has no wind.speed")
 	}
 
-	q := req.URL.Query()
-	q.Add("appid", c.apiKey)
-	q.Add("q", cityName)
-	q.Add("units", "metric")
-	req.URL.RawQuery = q.Encode()
+	return nil
+}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "openweather: execute request")
+// WeatherByCityName returns a summary of the weather for a city.
+func (c *Client) WeatherByCityName(ctx context.Context, cityName string) (*WeatherSuccess, error) {
+	if cityName == "" {
+		return nil, errors.New("openweather: cityname is required")
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("openweather: unexpected response status code %v", res.StatusCode)
+	var apiResponse WeatherSuccess
+	if err := c.get(ctx, "weather", url.Values{"q": []string{cityName}}, &apiResponse, &weatherRequiredFields{}); err != nil {
+		return nil, err
 	}
 
+	return &apiResponse, nil
+}
+
+// WeatherByCoordinates returns a summary of the weather for a latitude/longitude (in
+// decimal degrees).
+func (c *Client) WeatherByCoordinates(ctx context.Context, latitude, longitude float64) (*WeatherSuccess, error) {
 	var apiResponse WeatherSuccess
-	if res.Body != nil {
-		defer func() {
-			err := res.Body.Close()
-			if err != nil {
-				logger.Error(err, "Error closing response body.")
-			}
-		}()
-
-		if err := json.NewDecoder(res.Body).Decode(&apiResponse); err != nil {
-			return nil, errors.Wrap(err, "openweather: decode body")
-		}
+	if err := c.get(ctx, "weather", coordinatesQuery(latitude, longitude), &apiResponse, &weatherRequiredFields{}); err != nil {
+		return nil, err
 	}
 
 	return &apiResponse, nil
 }
+
+// coordinatesQuery returns the query parameters identifying a latitude/longitude.
+func coordinatesQuery(latitude, longitude float64) url.Values {
+	return url.Values{
+		"lat": []string{strconv.FormatFloat(latitude, 'f', -1, 64)},
+		"lon": []string{strconv.FormatFloat(longitude, 'f', -1, 64)},
+	}
+}
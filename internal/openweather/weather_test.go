@@ -8,8 +8,14 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServiceWeatherByCityName(t *testing.T) {
@@ -73,6 +79,24 @@ func TestServiceWeatherByCityName(t *testing.T) {
 			expected:     nil,
 			expectedErr:  "openweather: decode body: json: cannot unmarshal string into Go value of type openweather.WeatherSuccess",
 		},
+		{
+			name: "body_too_large",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader(make([]byte, maxBodySize+1)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "openweather: read body: larger than 1048576 bytes",
+		},
 		{
 			name: "http_client_error",
 			withClient: New(
@@ -105,6 +129,58 @@ func TestServiceWeatherByCityName(t *testing.T) {
 			expected:     nil,
 			expectedErr:  "openweather: unexpected response status code 500",
 		},
+		{
+			name: "city_not_found",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"cod":"404","message":"city not found"}`)))
+
+						return &http.Response{StatusCode: http.StatusNotFound, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "openweather: unexpected response status code 404: city not found",
+		},
+		{
+			name: "missing_temperature",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"main":{},"wind":{"speed":1}}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "openweather: response has no main.temp",
+		},
+		{
+			name: "no_body",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						return &http.Response{StatusCode: http.StatusOK}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "openweather: response has no main.temp",
+		},
 		{
 			name:         "missing_city_name",
 			withClient:   New("", "", NewWithHTTPClient(&HTTPClientMock{})),
@@ -164,4 +240,98 @@ func TestServiceWeatherByCityName(t *testing.T) {
 		assert.Nil(t, actualResult)
 		assert.ErrorIs(t, actualErr, ctx.Err())
 	})
+
+	t.Run("retry_after", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				r := io.NopCloser(bytes.NewReader([]byte(`{"cod":429,"message":"Test123"}`)))
+
+				return &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     http.Header{"Retry-After": []string{"60"}},
+					Body:       r,
+				}, nil
+			},
+		}
+
+		client := New("", "", NewWithHTTPClient(httpClient))
+
+		_, err := client.WeatherByCityName(context.Background(), "ABC")
+
+		var apiErr *APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, &APIError{StatusCode: 429, Code: "429", Message: "Test123", RetryAfter: time.Minute}, apiErr)
+		}
+
+		// Requests are skipped until Retry-After has passed.
+		_, err = client.WeatherByCityName(context.Background(), "ABC")
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Len(t, httpClient.DoCalls(), 1, "requests sent")
+	})
+}
+
+func TestServiceWeatherByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	dummyResult := WeatherSuccess{
+		Main: WeatherMain{
+			Temperature: 123,
+		},
+		Wind: WeatherWind{
+			WindSpeed: 456,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := New("", "", NewWithHTTPClient(&HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "-33.87", req.URL.Query().Get("lat"))
+			assert.Equal(t, "151.21", req.URL.Query().Get("lon"))
+			assert.Equal(t, "", req.URL.Query().Get("q"))
+
+			payload, err := json.Marshal(dummyResult)
+			assert.NoError(t, err)
+
+			r := io.NopCloser(bytes.NewReader(payload))
+
+			return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+		},
+	}))
+
+	actual, err := client.WeatherByCoordinates(ctx, -33.87, 151.21)
+	assert.NoError(t, err)
+	assert.Equal(t, &dummyResult, actual)
+}
+
+func TestServiceWeatherByCityNameTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New("https://example.com", "Test123",
+		NewWithHTTPClient(&HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			},
+		}),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.WeatherByCityName(context.Background(), "Sydney")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "openweather GET /weather", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/weather"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusBadGateway))
+	}
 }
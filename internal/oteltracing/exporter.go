@@ -0,0 +1,145 @@
+package oteltracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewOTLPHTTPExporter creates an exporter sending spans to the OTLP/HTTP collector at endpoint
+// (e.g "http://localhost:4318"), at the path "/v1/traces". The exporter sends spans as they
+// are exported, so should be wrapped in a batch span processor (e.g with sdktrace.WithBatcher).
+func NewOTLPHTTPExporter(ctx context.Context, endpoint string) (*otlptrace.Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "oteltracing: parse endpoint")
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+	}
+
+	if u.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "oteltracing: create otlp exporter")
+	}
+
+	return exporter, nil
+}
+
+// StdoutExporter writes spans to a writer (e.g os.Stdout) for local use, each as a line of
+// JSON.
+type StdoutExporter struct {
+	w io.Writer
+	m sync.Mutex
+}
+
+var _ sdktrace.SpanExporter = (*StdoutExporter)(nil)
+
+// NewStdoutExporter creates a new [StdoutExporter] writing to w.
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+// ExportSpans writes spans to the writer.
+func (e *StdoutExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	encoder := json.NewEncoder(e.w)
+
+	for _, span := range spans {
+		if err := encoder.Encode(newStdoutSpan(span)); err != nil {
+			return errors.Wrap(err, "oteltracing: write spans")
+		}
+	}
+
+	return nil
+}
+
+// Shutdown does nothing, as there is nothing to clean up.
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// stdoutSpan is a span as written by the [StdoutExporter].
+type stdoutSpan struct {
+	Name                 string
+	TraceID              string
+	SpanID               string
+	ParentSpanID         string `json:",omitempty"`
+	Kind                 string
+	StartTime            time.Time
+	EndTime              time.Time
+	Attributes           map[string]interface{} `json:",omitempty"`
+	Events               []stdoutEvent          `json:",omitempty"`
+	StatusCode           string
+	StatusDescription    string `json:",omitempty"`
+	InstrumentationScope string
+}
+
+// stdoutEvent is a span event as written by the [StdoutExporter].
+type stdoutEvent struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{} `json:",omitempty"`
+}
+
+// newStdoutSpan creates a new [stdoutSpan] from span.
+func newStdoutSpan(span sdktrace.ReadOnlySpan) stdoutSpan {
+	s := stdoutSpan{
+		Name:                 span.Name(),
+		TraceID:              span.SpanContext().TraceID().String(),
+		SpanID:               span.SpanContext().SpanID().String(),
+		Kind:                 span.SpanKind().String(),
+		StartTime:            span.StartTime(),
+		EndTime:              span.EndTime(),
+		Attributes:           stdoutAttributes(span.Attributes()),
+		StatusCode:           span.Status().Code.String(),
+		StatusDescription:    span.Status().Description,
+		InstrumentationScope: span.InstrumentationScope().Name,
+	}
+
+	if span.Parent().IsValid() {
+		s.ParentSpanID = span.Parent().SpanID().String()
+	}
+
+	for _, event := range span.Events() {
+		s.Events = append(s.Events, stdoutEvent{
+			Name:       event.Name,
+			Time:       event.Time,
+			Attributes: stdoutAttributes(event.Attributes),
+		})
+	}
+
+	return s
+}
+
+// stdoutAttributes returns attributes as a map of their keys to values.
+func stdoutAttributes(attributes []attribute.KeyValue) map[string]interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(attributes))
+	for _, kv := range attributes {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+
+	return m
+}
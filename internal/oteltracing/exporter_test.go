@@ -0,0 +1,141 @@
+package oteltracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordSpans returns a parent & child span, as recorded.
+func recordSpans(t *testing.T) []sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("Test123")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+
+	child.SetAttributes(attribute.String("a", "b"), attribute.Int("c", 1), attribute.Bool("d", true))
+	child.RecordError(errors.New("Test456"))
+	child.SetStatus(codes.Error, "Test456")
+	child.End()
+	parent.End()
+
+	return recorder.Ended()
+}
+
+func TestOTLPHTTPExporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exported", func(t *testing.T) {
+		t.Parallel()
+
+		var actualPath, actualContentType string
+		var actual coltracepb.ExportTraceServiceRequest
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			actualPath = req.URL.Path
+			actualContentType = req.Header.Get("Content-Type")
+
+			body, _ := io.ReadAll(req.Body)
+			_ = proto.Unmarshal(body, &actual)
+		}))
+		t.Cleanup(server.Close)
+
+		spans := recordSpans(t)
+
+		exporter, err := NewOTLPHTTPExporter(context.Background(), server.URL+"/")
+		require.NoError(t, err)
+
+		require.NoError(t, exporter.ExportSpans(context.Background(), spans))
+		require.NoError(t, exporter.Shutdown(context.Background()))
+
+		assert.Equal(t, "/v1/traces", actualPath)
+		assert.Equal(t, "application/x-protobuf", actualContentType)
+
+		require.Len(t, actual.ResourceSpans, 1)
+		require.Len(t, actual.ResourceSpans[0].ScopeSpans, 1)
+
+		scopeSpans := actual.ResourceSpans[0].ScopeSpans[0]
+		assert.Equal(t, "Test123", scopeSpans.Scope.Name)
+		require.Len(t, scopeSpans.Spans, 2)
+
+		child := scopeSpans.Spans[0]
+		assert.Equal(t, "child", child.Name)
+		assert.Equal(t, spans[0].SpanContext().TraceID().String(), hex.EncodeToString(child.TraceId))
+		assert.Equal(t, spans[1].SpanContext().SpanID().String(), hex.EncodeToString(child.ParentSpanId))
+		assert.Equal(t, "Test456", child.Status.Message)
+		assert.Len(t, child.Attributes, 3)
+		assert.Len(t, child.Events, 1)
+	})
+
+	t.Run("collector_error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(server.Close)
+
+		exporter, err := NewOTLPHTTPExporter(context.Background(), server.URL)
+		require.NoError(t, err)
+
+		assert.Error(t, exporter.ExportSpans(context.Background(), recordSpans(t)))
+	})
+
+	t.Run("invalid_endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewOTLPHTTPExporter(context.Background(), "http://local host")
+		assert.ErrorContains(t, err, "oteltracing: parse endpoint")
+	})
+}
+
+func TestStdoutExporter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	spans := recordSpans(t)
+
+	err := NewStdoutExporter(&buf).ExportSpans(context.Background(), spans)
+	require.NoError(t, err)
+
+	decoder := json.NewDecoder(&buf)
+
+	var actual []stdoutSpan
+
+	for decoder.More() {
+		var span stdoutSpan
+		require.NoError(t, decoder.Decode(&span))
+
+		actual = append(actual, span)
+	}
+
+	require.Len(t, actual, 2)
+
+	assert.Equal(t, "child", actual[0].Name)
+	assert.Equal(t, spans[1].SpanContext().SpanID().String(), actual[0].ParentSpanID)
+	assert.Equal(t, map[string]interface{}{"a": "b", "c": float64(1), "d": true}, actual[0].Attributes)
+	assert.Equal(t, "Error", actual[0].StatusCode)
+	assert.Len(t, actual[0].Events, 1)
+
+	assert.Equal(t, "parent", actual[1].Name)
+	assert.Empty(t, actual[1].ParentSpanID)
+}
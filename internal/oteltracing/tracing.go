@@ -0,0 +1,69 @@
+// Package oteltracing includes functionality for capturing & exporting otel traces.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by [MuxMiddleware].
+const TracerName = "github.com/byatesrae/weather/internal/oteltracing"
+
+// propagator propagates trace context in W3C "traceparent" & "tracestate" headers.
+var propagator = propagation.TraceContext{}
+
+// MuxMiddleware starts a span for each incoming request using tracerProvider, and can be used
+// as a gorilla/mux middleware. Spans are named after the matched Mux route path template &
+// continue the trace of the request's "traceparent" header, if any.
+func MuxMiddleware(tracerProvider trace.TracerProvider) mux.MiddlewareFunc {
+	tracer := tracerProvider.Tracer(TracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			path, _ := mux.CurrentRoute(req).GetPathTemplate()
+
+			ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(
+				ctx,
+				fmt.Sprintf("%s %s", req.Method, path),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(req.Method),
+					semconv.HTTPRouteKey.String(path),
+					semconv.HTTPTargetKey.String(req.URL.Path),
+				),
+			)
+			defer span.End()
+
+			rwr := statusRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+
+			next.ServeHTTP(&rwr, req.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rwr.statusCode))
+			span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rwr.statusCode, trace.SpanKindServer))
+		})
+	}
+}
+
+// InjectHeaders adds the trace context of ctx (if any) to header, as W3C "traceparent" &
+// "tracestate" headers.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// statusRecorder implements http.ResponseWriter while capturing the response status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int // The value of the last call to http.ResponseWriter.WriteHeader().
+}
+
+func (rw *statusRecorder) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
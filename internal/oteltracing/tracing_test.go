@@ -0,0 +1,118 @@
+package oteltracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMuxMiddleware(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		giveTraceparent    string
+		giveStatusCode     int
+		expectedTraceID    string // Empty if any.
+		expectedStatusCode codes.Code
+	}{
+		{
+			name:               "new_trace",
+			giveStatusCode:     http.StatusOK,
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:               "continued_trace",
+			giveTraceparent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			giveStatusCode:     http.StatusOK,
+			expectedTraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:               "server_error",
+			giveStatusCode:     http.StatusInternalServerError,
+			expectedStatusCode: codes.Error,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup
+			recorder := tracetest.NewSpanRecorder()
+			tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var handlerSpanContext trace.SpanContext
+
+			router := mux.NewRouter()
+			router.Use(MuxMiddleware(tracerProvider))
+			router.HandleFunc("/test/{id}", func(rw http.ResponseWriter, req *http.Request) {
+				handlerSpanContext = trace.SpanContextFromContext(req.Context())
+
+				rw.WriteHeader(tc.giveStatusCode)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test/abc", http.NoBody)
+			if tc.giveTraceparent != "" {
+				req.Header.Set("traceparent", tc.giveTraceparent)
+			}
+
+			// Do
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			assert.Equal(t, "GET /test/{id}", span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, tc.expectedStatusCode, span.Status().Code)
+			assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", tc.giveStatusCode))
+			assert.Equal(t, span.SpanContext(), handlerSpanContext, "handler context holds the span")
+
+			if tc.expectedTraceID != "" {
+				assert.Equal(t, tc.expectedTraceID, span.SpanContext().TraceID().String())
+				assert.True(t, span.Parent().IsRemote())
+			}
+		})
+	}
+}
+
+func TestInjectHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_span", func(t *testing.T) {
+		t.Parallel()
+
+		header := http.Header{}
+		InjectHeaders(context.Background(), header)
+
+		assert.Empty(t, header.Get("traceparent"))
+	})
+
+	t.Run("span", func(t *testing.T) {
+		t.Parallel()
+
+		tracerProvider := sdktrace.NewTracerProvider()
+		ctx, span := tracerProvider.Tracer("Test123").Start(context.Background(), "Test456")
+		t.Cleanup(func() { span.End() })
+
+		header := http.Header{}
+		InjectHeaders(ctx, header)
+
+		expected := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+		assert.Equal(t, expected, header.Get("traceparent"))
+	})
+}
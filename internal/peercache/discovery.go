@@ -0,0 +1,61 @@
+package peercache
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SRVResolver looks up DNS SRV records, see [net.Resolver.LookupSRV].
+type SRVResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// DiscoverSRVPeers keeps the peers of c up to date with the DNS SRV records of name (e.g
+// "_http._tcp.weatherapi.default.svc.cluster.local"), looking them up every interval until
+// ctx is done. Each record is a peer at "http://target:port". Should a lookup fail, the
+// peers are left as they were.
+func (c *PeerCache) DiscoverSRVPeers(ctx context.Context, resolver SRVResolver, name string, interval time.Duration) {
+	logger := c.getLoggerFromContext(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		peers, err := lookupSRVPeers(ctx, resolver, name)
+		if err != nil {
+			logger.Error(err, "Failed to discover peers.")
+		} else {
+			logger.V(1).Info("Discovered peers.", "peers", peers)
+
+			c.SetPeers(peers)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// lookupSRVPeers returns the URLs of the peers in the DNS SRV records of name.
+func lookupSRVPeers(ctx context.Context, resolver SRVResolver, name string) ([]string, error) {
+	_, records, err := resolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, errors.Wrap(err, "peercache: lookup SRV records")
+	}
+
+	peers := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+
+		peers = append(peers, "http://"+net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+
+	return peers, nil
+}
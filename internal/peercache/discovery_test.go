@@ -0,0 +1,86 @@
+package peercache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSRVResolver returns preset SRV records, changeable while in use.
+type fakeSRVResolver struct {
+	m       sync.Mutex
+	records []*net.SRV
+	err     error
+}
+
+func (r *fakeSRVResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return name, r.records, r.err
+}
+
+func (r *fakeSRVResolver) set(records []*net.SRV, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.records, r.err = records, err
+}
+
+func TestPeerCacheDiscoverSRVPeers(t *testing.T) {
+	t.Parallel()
+
+	resolver := &fakeSRVResolver{
+		records: []*net.SRV{
+			{Target: "10.0.0.1.", Port: 8080},
+			{Target: "10.0.0.2.", Port: 8080},
+		},
+	}
+
+	c := New("http://10.0.0.1:8080", WithReplicas(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		c.DiscoverSRVPeers(ctx, resolver, "_http._tcp.weatherapi", time.Millisecond*10)
+		close(done)
+	}()
+
+	// peers returns the peers on the ring.
+	peers := func() []string {
+		c.m.RLock()
+		defer c.m.RUnlock()
+
+		var peers []string
+		for _, peer := range c.ring.peers {
+			peers = append(peers, peer)
+		}
+
+		sort.Strings(peers)
+
+		return peers
+	}
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"http://10.0.0.1:8080", "http://10.0.0.2:8080"}, peers())
+	}, time.Second, time.Millisecond*10)
+
+	resolver.set(nil, errors.New("intentional test error"))
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080"}, peers(), "peers are kept when a lookup fails")
+
+	resolver.set([]*net.SRV{{Target: "10.0.0.3.", Port: 8080}}, nil)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"http://10.0.0.1:8080", "http://10.0.0.3:8080"}, peers())
+	}, time.Second, time.Millisecond*10, "this peer is always included")
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,26 @@
+package peercache
+
+import "fmt"
+
+// loadErrorHeader marks a response to a peer as being a load error, see [LoadError].
+const loadErrorHeader = "X-Peercache-Load-Error"
+
+// LoadError is returned by [PeerCache.Get] when the owner of a key failed to load it with one of
+// the classes of error set with [WithLoadErrors], which it matches (see errors.Is). The value
+// isn't loaded locally instead, as the owner's answer stands.
+type LoadError struct {
+	StatusCode int    // The status code the owner responded with.
+	Message    string // The error message of the owner.
+
+	class error
+}
+
+// Error implements error.
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("peercache: owner failed to load value (status code %v): %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the class of the error, as set with [WithLoadErrors].
+func (e *LoadError) Unwrap() error {
+	return e.class
+}
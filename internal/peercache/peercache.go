@@ -0,0 +1,505 @@
+// Package peercache contains a cache shared by a group of peers (replicas of a service),
+// without an external store. Each key is owned by a single peer, decided by a consistent hash
+// ring. Other peers get & set the key on its owner over HTTP, such that (with a loader, see
+// [PeerCache.SetLoader]) only the owner loads the value of a key. Requests between peers are
+// signed with a shared secret, see [WithSecret].
+package peercache
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/byatesrae/weather/internal/memorycache"
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+)
+
+// Path is the path peers serve each other on, see [PeerCache.ServeHTTP].
+const Path = "/_peercache"
+
+const (
+	// expiryHeader is the header used to exchange the expiry of a value between peers.
+	expiryHeader = "X-Peercache-Expiry"
+
+	// maxValueSize is the maximum size of a value exchanged between peers.
+	maxValueSize = 1 << 20
+)
+
+// Cache is used to store & retrieve the values owned by a peer.
+type Cache interface {
+	Get(ctx context.Context, key interface{}) (interface{}, time.Time, error)
+	Set(ctx context.Context, key, val interface{}, expiry time.Time) error
+}
+
+// Loader loads the value of key (returning it serialized) & its expiry, on behalf of the other
+// peers. It's expected to read the value through the same [PeerCache], setting it there once
+// loaded.
+type Loader func(ctx context.Context, key string) ([]byte, time.Time, error)
+
+// HTTPClient is used to send requests to peers.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewOptions are the options for the [New] function.
+type NewOptions struct {
+	local                Cache
+	client               HTTPClient
+	replicas             int
+	secret               string
+	loadErrors           map[int]error
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+}
+
+// WithLocal sets the cache that the values owned by this peer are stored in. Defaults to a
+// [memorycache.MemoryCache].
+func WithLocal(local Cache) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.local = local
+	}
+}
+
+// WithHTTPClient sets the client used to send requests to peers.
+func WithHTTPClient(client HTTPClient) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.client = client
+	}
+}
+
+// WithReplicas sets the number of times each peer is placed on the consistent hash ring. More
+// replicas spread keys more evenly across peers.
+func WithReplicas(replicas int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.replicas = replicas
+	}
+}
+
+// WithSecret sets the secret shared by all peers, that requests between them are signed with.
+// Requests from other peers are only served once it's set, such that values can't be read or
+// made up by anyone else able to reach a peer.
+func WithSecret(secret string) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.secret = secret
+	}
+}
+
+// WithLoadErrors sets the classes of error (see errors.Is) the loader may fail with, keyed by
+// the status code they're sent to other peers with. Other peers return them as a [LoadError],
+// rather than loading the value themselves.
+func WithLoadErrors(loadErrors map[int]error) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.loadErrors = loadErrors
+	}
+}
+
+// WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
+// the context.
+func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.getLoggerFromContext = getLoggerFromContext
+	}
+}
+
+// PeerCache is a cache shared by a group of peers, that is safe for concurrent access. Keys
+// must be a string or [fmt.Stringer] & values must be []byte or implement
+// [encoding.BinaryMarshaler]. Values are always retrieved as []byte.
+//
+// Should the owner of a key be unreachable, the key is stored locally instead.
+type PeerCache struct {
+	self                 string
+	local                Cache
+	client               HTTPClient
+	replicas             int
+	secret               []byte
+	loadErrors           map[int]error
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+
+	ring   *ring
+	loader Loader
+	m      sync.RWMutex
+}
+
+// New creates a new [PeerCache] for the peer reachable by other peers at the URL self (e.g
+// "http://10.0.0.1:8080"). Initially it is the only peer, see [PeerCache.SetPeers].
+func New(self string, overrides ...func(o *NewOptions)) *PeerCache {
+	noopLogger := nooplogr.New()
+
+	options := NewOptions{
+		local:    memorycache.New(),
+		client:   &http.Client{Timeout: time.Second * 2},
+		replicas: 50,
+		getLoggerFromContext: func(ctx context.Context) logr.Logger {
+			return noopLogger
+		},
+	}
+
+	for _, override := range overrides {
+		override(&options)
+	}
+
+	return &PeerCache{
+		self:                 self,
+		local:                options.local,
+		client:               options.client,
+		replicas:             options.replicas,
+		secret:               []byte(options.secret),
+		loadErrors:           options.loadErrors,
+		getLoggerFromContext: options.getLoggerFromContext,
+		ring:                 newRing([]string{self}, options.replicas),
+	}
+}
+
+// SetPeers sets the URLs of all peers in the group. This peer is always part of the group,
+// whether or not it's included in peers.
+func (c *PeerCache) SetPeers(peers []string) {
+	unique := map[string]struct{}{c.self: {}}
+	for _, peer := range peers {
+		unique[peer] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(unique))
+	for peer := range unique {
+		sorted = append(sorted, peer)
+	}
+
+	sort.Strings(sorted)
+
+	r := newRing(sorted, c.replicas)
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.ring = r
+}
+
+// SetLoader sets the loader used to load the values owned by this peer when requested by
+// other peers. Without a loader, other peers load values themselves (setting them on the
+// owner).
+func (c *PeerCache) SetLoader(loader Loader) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.loader = loader
+}
+
+// Get retrieves a value from cache as well as the time it expires.
+func (c *PeerCache) Get(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+	name, err := keyName(key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	owner := c.owner(ctx, name)
+	if owner == c.self {
+		return c.local.Get(ctx, name)
+	}
+
+	val, expiry, err := c.getFromPeer(ctx, owner, name)
+	if errors.As(err, new(*LoadError)) {
+		return nil, time.Time{}, err
+	}
+
+	if err != nil {
+		c.getLoggerFromContext(ctx).Error(err, "Failed to get from peer, falling back to the local cache.", "peer", owner)
+
+		return c.local.Get(ctx, name)
+	}
+
+	return val, expiry, nil
+}
+
+// Set will set a value to be cached as well as an expiry.
+func (c *PeerCache) Set(ctx context.Context, key, val interface{}, expiry time.Time) error {
+	name, err := keyName(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalValue(val)
+	if err != nil {
+		return err
+	}
+
+	owner := c.owner(ctx, name)
+	if owner == c.self {
+		return c.local.Set(ctx, name, data, expiry)
+	}
+
+	if err := c.setOnPeer(ctx, owner, name, data, expiry); err != nil {
+		c.getLoggerFromContext(ctx).Error(err, "Failed to set on peer, falling back to the local cache.", "peer", owner)
+
+		return c.local.Set(ctx, name, data, expiry)
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler, serving the requests of other peers (at [Path]) to get
+// (GET) or set (PUT) the value of the query parameter "key". The value is exchanged as the
+// body, with its expiry as a header. Requests not signed by a peer (see [WithSecret]) are
+// rejected.
+func (c *PeerCache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := c.getLoggerFromContext(req.Context())
+
+	key := req.URL.Query().Get("key")
+	if key == "" {
+		http.Error(rw, "key is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var data []byte
+
+	if req.Method == http.MethodPut {
+		var err error
+		// One byte past the limit is read to tell a value at the limit from one over it.
+		if data, err = io.ReadAll(io.LimitReader(req.Body, maxValueSize+1)); err != nil {
+			http.Error(rw, "failed to read value", http.StatusBadRequest)
+
+			return
+		}
+
+		if len(data) > maxValueSize {
+			http.Error(rw, "value too large", http.StatusRequestEntityTooLarge)
+
+			return
+		}
+	}
+
+	if !c.authenticate(req, key, data) {
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+
+		return
+	}
+
+	// The requesting peer decided this peer is the owner, which is respected even if the
+	// peers are seen differently here (to avoid requests bouncing between peers).
+	ctx := context.WithValue(req.Context(), peerRequestCtxKey{}, true)
+
+	switch req.Method {
+	case http.MethodGet:
+		val, expiry, err := c.getOwned(ctx, key)
+		if err != nil {
+			c.writeGetError(rw, logger, key, err)
+
+			return
+		}
+
+		if val == nil {
+			http.Error(rw, "not found", http.StatusNotFound)
+
+			return
+		}
+
+		rw.Header().Set(expiryHeader, expiry.Format(time.RFC3339Nano))
+		rw.Header().Set("Content-Type", "application/octet-stream")
+
+		if _, err := rw.Write(val); err != nil {
+			logger.Error(err, "Failed to write value for peer.", "key", key)
+		}
+	case http.MethodPut:
+		expiry, err := time.Parse(time.RFC3339Nano, req.Header.Get(expiryHeader))
+		if err != nil {
+			http.Error(rw, "invalid expiry", http.StatusBadRequest)
+
+			return
+		}
+
+		if err := c.local.Set(ctx, key, data, expiry); err != nil {
+			logger.Error(err, "Failed to set value for peer.", "key", key)
+			http.Error(rw, "failed to set value", http.StatusInternalServerError)
+
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, PUT")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeGetError responds to a peer that getting the value of key failed with err, with the
+// status code of its class if it's a load error (see [WithLoadErrors]).
+func (c *PeerCache) writeGetError(rw http.ResponseWriter, logger logr.Logger, key string, err error) {
+	for statusCode, class := range c.loadErrors {
+		if errors.Is(err, class) {
+			rw.Header().Set(loadErrorHeader, "true")
+			http.Error(rw, err.Error(), statusCode)
+
+			return
+		}
+	}
+
+	logger.Error(err, "Failed to get value for peer.", "key", key)
+	http.Error(rw, "failed to get value", http.StatusInternalServerError)
+}
+
+// getOwned gets the value of key owned by this peer, using the loader (if set) to load it
+// should it be missing or expired.
+func (c *PeerCache) getOwned(ctx context.Context, key string) ([]byte, time.Time, error) {
+	c.m.RLock()
+	loader := c.loader
+	c.m.RUnlock()
+
+	if loader != nil {
+		return loader(ctx, key)
+	}
+
+	val, expiry, err := c.local.Get(ctx, key)
+	if err != nil || val == nil {
+		return nil, time.Time{}, err
+	}
+
+	data, ok := val.([]byte)
+	if !ok {
+		return nil, time.Time{}, errors.Errorf("peercache: unexpected local value %T", val)
+	}
+
+	return data, expiry, nil
+}
+
+// peerRequestCtxKey marks a context as being for a request from another peer.
+type peerRequestCtxKey struct{}
+
+// owner returns the peer owning key. Requests from other peers are always owned by this peer.
+func (c *PeerCache) owner(ctx context.Context, key string) string {
+	if peerRequest, _ := ctx.Value(peerRequestCtxKey{}).(bool); peerRequest {
+		return c.self
+	}
+
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	return c.ring.owner(key)
+}
+
+// getFromPeer gets the value of key from peer.
+func (c *PeerCache) getFromPeer(ctx context.Context, peer, key string) ([]byte, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peerURL(peer, key), http.NoBody)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "peercache: create request")
+	}
+
+	c.sign(req, key, nil)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "peercache: send request")
+	}
+	defer res.Body.Close()
+
+	if class, ok := c.loadErrors[res.StatusCode]; ok && res.Header.Get(loadErrorHeader) != "" {
+		message, err := readValue(res.Body)
+		if err != nil {
+			return nil, time.Time{}, errors.Wrap(err, "peercache: read load error")
+		}
+
+		return nil, time.Time{}, &LoadError{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(message)), class: class}
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, time.Time{}, nil
+	default:
+		return nil, time.Time{}, errors.Errorf("peercache: unexpected response status %v", res.StatusCode)
+	}
+
+	expiry, err := time.Parse(time.RFC3339Nano, res.Header.Get(expiryHeader))
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "peercache: parse expiry")
+	}
+
+	data, err := readValue(res.Body)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "peercache: read response")
+	}
+
+	return data, expiry, nil
+}
+
+// readValue reads r, erroring if it's larger than maxValueSize rather than truncating it.
+func readValue(r io.Reader) ([]byte, error) {
+	// One byte past the limit is read to tell a value at the limit from one over it.
+	data, err := io.ReadAll(io.LimitReader(r, maxValueSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxValueSize {
+		return nil, errors.Errorf("larger than %d bytes", maxValueSize)
+	}
+
+	return data, nil
+}
+
+// setOnPeer sets the value of key on peer.
+func (c *PeerCache) setOnPeer(ctx context.Context, peer, key string, data []byte, expiry time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, peerURL(peer, key), bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "peercache: create request")
+	}
+
+	req.Header.Set(expiryHeader, expiry.Format(time.RFC3339Nano))
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	c.sign(req, key, data)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "peercache: send request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return errors.Errorf("peercache: unexpected response status %v", res.StatusCode)
+	}
+
+	return nil
+}
+
+// peerURL returns the URL of key on peer.
+func peerURL(peer, key string) string {
+	return peer + Path + "?key=" + url.QueryEscape(key)
+}
+
+// keyName returns the name key is exchanged with peers under.
+func keyName(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case string:
+		return k, nil
+	case fmt.Stringer:
+		return k.String(), nil
+	default:
+		return "", errors.Errorf("peercache: unsupported key type %T", key)
+	}
+}
+
+// marshalValue returns the serialized form of val.
+func marshalValue(val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case []byte:
+		return v, nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return nil, errors.Wrap(err, "peercache: marshal value")
+		}
+
+		return data, nil
+	default:
+		return nil, errors.Errorf("peercache: unsupported value type %T", val)
+	}
+}
@@ -0,0 +1,368 @@
+package peercache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather/internal/memorycache"
+)
+
+// errTestNotFound is a class of load error, sent to other peers as a 404.
+var errTestNotFound = errors.New("test: not found")
+
+// testPeer is a peer served by an in-process server.
+type testPeer struct {
+	cache  *PeerCache
+	local  *memorycache.MemoryCache
+	server *httptest.Server
+}
+
+// startPeers starts count peers in a group. They're stopped when the test completes.
+func startPeers(t *testing.T, count int) []*testPeer {
+	t.Helper()
+
+	peers := make([]*testPeer, count)
+	urls := make([]string, count)
+
+	for i := range peers {
+		peer := &testPeer{local: memorycache.New()}
+		peer.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			peer.cache.ServeHTTP(rw, req)
+		}))
+		peer.cache = New(
+			peer.server.URL,
+			WithLocal(peer.local),
+			WithSecret("Test123"),
+			WithLoadErrors(map[int]error{http.StatusNotFound: errTestNotFound}),
+		)
+
+		t.Cleanup(peer.server.Close)
+
+		peers[i] = peer
+		urls[i] = peer.server.URL
+	}
+
+	for _, peer := range peers {
+		peer.cache.SetPeers(urls)
+	}
+
+	return peers
+}
+
+func TestPeerCacheGetLoadedByOwner(t *testing.T) {
+	t.Parallel()
+
+	peers := startPeers(t, 3)
+	expiry := time.Now().Add(time.Minute).UTC()
+
+	for _, peer := range peers {
+		peer := peer
+
+		peer.cache.SetLoader(func(ctx context.Context, key string) ([]byte, time.Time, error) {
+			return []byte(key + " loaded by " + peer.cache.self), expiry, nil
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	owners := map[string]bool{}
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("city:%v", i)
+		owner := peers[0].cache.owner(ctx, key)
+		owners[owner] = true
+
+		for _, peer := range peers {
+			actual, actualExpiry, err := peer.cache.Get(ctx, key)
+			require.NoError(t, err)
+
+			if peer.cache.self == owner {
+				assert.Nil(t, actual, "the owner doesn't load its own reads")
+
+				continue
+			}
+
+			assert.Equal(t, []byte(key+" loaded by "+owner), actual)
+			assert.Equal(t, expiry, actualExpiry)
+		}
+	}
+
+	assert.Len(t, owners, 3, "keys are spread across peers")
+}
+
+func TestPeerCacheSet(t *testing.T) {
+	t.Parallel()
+
+	peers := startPeers(t, 3)
+	expiry := time.Now().Add(time.Minute).UTC()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("city:%v", i)
+
+		err := peers[0].cache.Set(ctx, key, []byte("value"), expiry)
+		require.NoError(t, err)
+
+		for _, peer := range peers {
+			actual, actualExpiry, err := peer.cache.Get(ctx, key)
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte("value"), actual)
+			assert.Equal(t, expiry, actualExpiry)
+
+			local, _, _ := peer.local.Get(ctx, key)
+			if peer.cache.self == peers[0].cache.owner(ctx, key) {
+				assert.NotNil(t, local, "the owner stores the value")
+			} else {
+				assert.Nil(t, local, "non-owners don't store the value")
+			}
+		}
+	}
+}
+
+func TestPeerCacheOwnerUnreachable(t *testing.T) {
+	t.Parallel()
+
+	peers := startPeers(t, 2)
+	peers[1].server.Close()
+
+	expiry := time.Now().Add(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// Find a key owned by the unreachable peer.
+	var key string
+	for i := 0; key == ""; i++ {
+		if candidate := fmt.Sprintf("city:%v", i); peers[0].cache.owner(ctx, candidate) == peers[1].cache.self {
+			key = candidate
+		}
+	}
+
+	err := peers[0].cache.Set(ctx, key, []byte("value"), expiry)
+	require.NoError(t, err)
+
+	actual, actualExpiry, err := peers[0].cache.Get(ctx, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("value"), actual)
+	assert.Equal(t, expiry, actualExpiry)
+}
+
+func TestPeerCacheGetLoadError(t *testing.T) {
+	t.Parallel()
+
+	peers := startPeers(t, 2)
+
+	var loads int
+
+	for _, peer := range peers {
+		peer.cache.SetLoader(func(ctx context.Context, key string) ([]byte, time.Time, error) {
+			loads++
+
+			return nil, time.Time{}, fmt.Errorf("%w: Test456", errTestNotFound)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// Find a key owned by the second peer.
+	var key string
+	for i := 0; key == ""; i++ {
+		if candidate := fmt.Sprintf("city:%v", i); peers[0].cache.owner(ctx, candidate) == peers[1].cache.self {
+			key = candidate
+		}
+	}
+
+	_, _, err := peers[0].cache.Get(ctx, key)
+
+	var loadErr *LoadError
+	if assert.ErrorAs(t, err, &loadErr) {
+		assert.Equal(t, http.StatusNotFound, loadErr.StatusCode)
+		assert.Equal(t, "test: not found: Test456", loadErr.Message)
+	}
+
+	assert.ErrorIs(t, err, errTestNotFound)
+	assert.Equal(t, 1, loads, "only the owner loads the value")
+}
+
+func TestPeerCacheServeHTTPUnauthenticated(t *testing.T) {
+	t.Parallel()
+
+	expiry := time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano)
+
+	for _, tc := range []struct {
+		name        string
+		withSecret  string
+		giveMethod  string
+		giveHeaders map[string]string
+	}{
+		{
+			name:        "put_unsigned",
+			withSecret:  "Test123",
+			giveMethod:  http.MethodPut,
+			giveHeaders: map[string]string{expiryHeader: expiry},
+		},
+		{
+			name:       "put_wrong_signature",
+			withSecret: "Test123",
+			giveMethod: http.MethodPut,
+			giveHeaders: map[string]string{
+				expiryHeader:    expiry,
+				timestampHeader: strconv.FormatInt(time.Now().Unix(), 10),
+				signatureHeader: "0123456789abcdef",
+			},
+		},
+		{
+			name:       "put_expired_signature",
+			withSecret: "Test123",
+			giveMethod: http.MethodPut,
+			giveHeaders: func() map[string]string {
+				timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+				signature := New("", WithSecret("Test123")).signature(http.MethodPut, "Test123", expiry, timestamp, []byte("made up"))
+
+				return map[string]string{expiryHeader: expiry, timestampHeader: timestamp, signatureHeader: signature}
+			}(),
+		},
+		{
+			name:       "get_unsigned",
+			withSecret: "Test123",
+			giveMethod: http.MethodGet,
+		},
+		{
+			name:       "no_secret",
+			giveMethod: http.MethodPut,
+			giveHeaders: func() map[string]string {
+				timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+				signature := New("").signature(http.MethodPut, "Test123", expiry, timestamp, []byte("made up"))
+
+				return map[string]string{expiryHeader: expiry, timestampHeader: timestamp, signatureHeader: signature}
+			}(),
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			local := memorycache.New()
+			c := New("http://localhost:8080", WithLocal(local), WithSecret(tc.withSecret))
+
+			req := httptest.NewRequest(tc.giveMethod, Path+"?key=Test123", bytes.NewReader([]byte("made up")))
+			for k, v := range tc.giveHeaders {
+				req.Header.Set(k, v)
+			}
+
+			rw := httptest.NewRecorder()
+
+			c.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+			actual, _, err := local.Get(context.Background(), "Test123")
+			assert.NoError(t, err)
+			assert.Nil(t, actual, "no value is set")
+		})
+	}
+}
+
+func TestPeerCacheServeHTTPValueTooLarge(t *testing.T) {
+	t.Parallel()
+
+	local := memorycache.New()
+	c := New("http://localhost:8080", WithLocal(local), WithSecret("Test123"))
+
+	data := make([]byte, maxValueSize+1)
+
+	req := httptest.NewRequest(http.MethodPut, Path+"?key=Test123", bytes.NewReader(data))
+	req.Header.Set(expiryHeader, time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano))
+	c.sign(req, "Test123", data)
+
+	rw := httptest.NewRecorder()
+
+	c.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+
+	actual, _, err := local.Get(context.Background(), "Test123")
+	assert.NoError(t, err)
+	assert.Nil(t, actual, "no value is set")
+}
+
+func TestPeerCacheGetFromPeerValueTooLarge(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		giveHeaders map[string]string
+		giveStatus  int
+		expectedErr string
+	}{
+		{
+			name:        "value",
+			giveHeaders: map[string]string{expiryHeader: time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano)},
+			giveStatus:  http.StatusOK,
+			expectedErr: "peercache: read response: larger than 1048576 bytes",
+		},
+		{
+			name:        "load_error",
+			giveHeaders: map[string]string{loadErrorHeader: "true"},
+			giveStatus:  http.StatusNotFound,
+			expectedErr: "peercache: read load error: larger than 1048576 bytes",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for k, v := range tc.giveHeaders {
+					rw.Header().Set(k, v)
+				}
+
+				rw.WriteHeader(tc.giveStatus)
+				_, _ = rw.Write(make([]byte, maxValueSize+1))
+			}))
+			t.Cleanup(server.Close)
+
+			c := New("http://localhost:8080", WithSecret("Test123"), WithLoadErrors(map[int]error{http.StatusNotFound: errTestNotFound}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, _, err := c.getFromPeer(ctx, server.URL, "Test123")
+			assert.EqualError(t, err, tc.expectedErr)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func TestPeerCacheErr(t *testing.T) {
+	t.Parallel()
+
+	c := New("http://localhost:8080")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	_, _, err := c.Get(ctx, 123)
+	assert.EqualError(t, err, "peercache: unsupported key type int")
+
+	err = c.Set(ctx, "Test123", 456, time.Now())
+	assert.EqualError(t, err, "peercache: unsupported value type int")
+}
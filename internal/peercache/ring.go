@@ -0,0 +1,50 @@
+package peercache
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// ring is a consistent hash ring, assigning each key an owner peer. Adding or removing a peer
+// only reassigns the keys owned by that peer.
+type ring struct {
+	hashes []uint32          // Sorted.
+	peers  map[uint32]string // The peer of each hash in hashes.
+}
+
+// newRing creates a ring of peers, each placed on the ring replicas times to spread keys
+// evenly.
+func newRing(peers []string, replicas int) *ring {
+	r := &ring{peers: make(map[uint32]string, len(peers)*replicas)}
+
+	for _, peer := range peers {
+		for i := 0; i < replicas; i++ {
+			hash := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + peer))
+
+			r.hashes = append(r.hashes, hash)
+			r.peers[hash] = peer
+		}
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+
+	return r
+}
+
+// owner returns the peer owning key, or "" if there are no peers.
+func (r *ring) owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	hash := crc32.ChecksumIEEE([]byte(key))
+
+	// The owner is the first peer clockwise from the hash of key.
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.peers[r.hashes[i]]
+}
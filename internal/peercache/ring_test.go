@@ -0,0 +1,41 @@
+package peercache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingOwner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_peers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "", newRing(nil, 50).owner("city:sydney"))
+	})
+
+	t.Run("peer_added", func(t *testing.T) {
+		t.Parallel()
+
+		before := newRing([]string{"a", "b", "c"}, 50)
+		after := newRing([]string{"a", "b", "c", "d"}, 50)
+
+		counts := map[string]int{}
+
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("city:%v", i)
+			owner := after.owner(key)
+			counts[owner]++
+
+			if owner != "d" {
+				assert.Equal(t, before.owner(key), owner, "only keys owned by the new peer move")
+			}
+		}
+
+		for _, peer := range []string{"a", "b", "c", "d"} {
+			assert.Greater(t, counts[peer], 100, "keys are spread evenly, peer %s", peer)
+		}
+	})
+}
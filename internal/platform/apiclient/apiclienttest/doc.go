@@ -0,0 +1,2 @@
+// Package apiclienttest includes test doubles for the apiclient package.
+package apiclienttest
@@ -0,0 +1,214 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+)
+
+// maxBodySize is the maximum size of a response body read by [Client.Get]. The APIs' responses
+// are a few KB, so a larger body is an error rather than something worth buffering.
+const maxBodySize = 1 << 20
+
+// HTTPClient is an HTTP client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewOptions are the options for the [New] function, taken by the New function of each API
+// client.
+type NewOptions struct {
+	client               HTTPClient
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracerProvider       trace.TracerProvider
+}
+
+// NewWithHTTPClient sets the HTTPClient in the [New] function.
+func NewWithHTTPClient(client HTTPClient) func(*NewOptions) {
+	return func(o *NewOptions) {
+		o.client = client
+	}
+}
+
+// WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
+// the context.
+func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.getLoggerFromContext = getLoggerFromContext
+	}
+}
+
+// WithTracerProvider sets the provider of the tracer used to trace requests to the API.
+func WithTracerProvider(tracerProvider trace.TracerProvider) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// Client sends requests to an API.
+type Client struct {
+	name                 string
+	client               HTTPClient
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracer               trace.Tracer
+}
+
+// New creates a new [Client] for the API name (e.g "openmeteo"), which prefixes errors & the
+// names of spans. Spans are started by the tracer named tracerName (e.g the import path of the
+// API's client).
+func New(name, tracerName string, optionOverrides ...func(*NewOptions)) *Client {
+	noopLogger := nooplogr.New()
+
+	options := NewOptions{
+		client: &http.Client{Timeout: time.Second * 5},
+		getLoggerFromContext: func(ctx context.Context) logr.Logger {
+			return noopLogger
+		},
+		tracerProvider: trace.NewNoopTracerProvider(),
+	}
+
+	for _, optionOverride := range optionOverrides {
+		optionOverride(&options)
+	}
+
+	return &Client{
+		name:                 name,
+		client:               options.client,
+		getLoggerFromContext: options.getLoggerFromContext,
+		tracer:               options.tracerProvider.Tracer(tracerName),
+	}
+}
+
+// Logger returns the logger of ctx.
+func (c *Client) Logger(ctx context.Context) logr.Logger {
+	return c.getLoggerFromContext(ctx)
+}
+
+// Request is a GET request sent by [Client.Get].
+type Request struct {
+	// Route names the span, e.g "points/{point}" for the span "nws GET /points/{point}". If
+	// empty, the span is named e.g "nws GET".
+	Route string
+
+	// URL is the URL requested, with Query added to its query.
+	URL   string
+	Query url.Values
+
+	// TracedURL replaces URL in the span & errors, as URL (or Query) may hold credentials. If
+	// empty, URL is used.
+	TracedURL string
+
+	Header     http.Header          // Added to the request.
+	Attributes []attribute.KeyValue // Added to the span.
+}
+
+// ResponseHandler handles the response to a request sent by [Client.Get]. body is the response
+// body, nil if the response has none.
+type ResponseHandler func(res *http.Response, body []byte) error
+
+// Get sends req, passing the response to handle. The request is traced, with an error (either
+// sending the request or returned by handle) recorded on its span. A response body larger than
+// 1 MiB is an error.
+func (c *Client) Get(ctx context.Context, req *Request, handle ResponseHandler) (err error) {
+	logger := c.getLoggerFromContext(ctx)
+
+	tracedURL := req.TracedURL
+	if tracedURL == "" {
+		tracedURL = req.URL
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, http.NoBody)
+	if err != nil {
+		return errors.Wrapf(redact(err, tracedURL), "%s: create request", c.name)
+	}
+
+	if len(req.Query) > 0 {
+		query := httpReq.URL.Query()
+		for k, v := range req.Query {
+			query[k] = v
+		}
+
+		httpReq.URL.RawQuery = query.Encode()
+	}
+
+	for k, v := range req.Header {
+		httpReq.Header[k] = v
+	}
+
+	spanName := c.name + " GET"
+	if req.Route != "" {
+		spanName += " /" + req.Route
+	}
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(http.MethodGet),
+			semconv.HTTPURLKey.String(tracedURL),
+		),
+		trace.WithAttributes(req.Attributes...),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}()
+
+	httpReq = httpReq.WithContext(ctx)
+
+	res, err := c.client.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(redact(err, tracedURL), "%s: execute request", c.name)
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+
+	var body []byte
+
+	if res.Body != nil {
+		defer func() {
+			err := res.Body.Close()
+			if err != nil {
+				logger.Error(err, "Error closing response body.")
+			}
+		}()
+
+		// One byte past the limit is read to tell a body at the limit from one over it.
+		if body, err = io.ReadAll(io.LimitReader(res.Body, maxBodySize+1)); err != nil {
+			return errors.Wrapf(err, "%s: read body", c.name)
+		}
+
+		if len(body) > maxBodySize {
+			return errors.Errorf("%s: read body: larger than %d bytes", c.name, maxBodySize)
+		}
+	}
+
+	return handle(res, body)
+}
+
+// redact replaces the URL of err (if it is a [*url.Error]), that may hold credentials, with
+// tracedURL.
+func redact(err error, tracedURL string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = tracedURL
+	}
+
+	return err
+}
@@ -0,0 +1,148 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// doFunc implements HTTPClient with a function.
+type doFunc func(req *http.Request) (*http.Response, error)
+
+func (f doFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("request", func(t *testing.T) {
+		t.Parallel()
+
+		var actual *http.Request
+
+		client := New("test", "Test123", NewWithHTTPClient(doFunc(func(req *http.Request) (*http.Response, error) {
+			actual = req
+
+			return &http.Response{StatusCode: http.StatusTeapot}, nil
+		})))
+
+		var actualStatusCode int
+		var actualBody []byte
+
+		err := client.Get(
+			context.Background(),
+			&Request{
+				URL:    "https://example.com/path?a=1",
+				Query:  url.Values{"b": []string{"2"}},
+				Header: http.Header{"X-Test": []string{"Test456"}},
+			},
+			func(res *http.Response, body []byte) error {
+				actualStatusCode, actualBody = res.StatusCode, body
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.MethodGet, actual.Method)
+		assert.Equal(t, "https://example.com/path?a=1&b=2", actual.URL.String())
+		assert.Equal(t, "Test456", actual.Header.Get("X-Test"))
+		assert.Equal(t, http.StatusTeapot, actualStatusCode)
+		assert.Nil(t, actualBody, "no body")
+	})
+
+	t.Run("redacted_errors", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("test", "Test123", NewWithHTTPClient(doFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: errors.New("Test456")}
+		})))
+
+		handle := func(res *http.Response, body []byte) error {
+			return nil
+		}
+
+		err := client.Get(context.Background(), &Request{URL: "https://example.com/secret", TracedURL: "https://example.com/*****"}, handle)
+		assert.EqualError(t, err, `test: execute request: Get "https://example.com/*****": Test456`)
+
+		err = client.Get(context.Background(), &Request{URL: "https://example.com/secret\n", TracedURL: "https://example.com/*****"}, handle)
+		assert.EqualError(t, err, `test: create request: parse "https://example.com/*****": net/url: invalid control character in URL`)
+	})
+
+	t.Run("body_size", func(t *testing.T) {
+		t.Parallel()
+
+		for _, size := range []int{maxBodySize, maxBodySize + 1} {
+			client := New("test", "Test123", NewWithHTTPClient(doFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(make([]byte, size)))}, nil
+			})))
+
+			var actualBody []byte
+
+			err := client.Get(context.Background(), &Request{URL: "https://example.com"}, func(res *http.Response, body []byte) error {
+				actualBody = body
+
+				return nil
+			})
+
+			if size > maxBodySize {
+				assert.EqualError(t, err, "test: read body: larger than 1048576 bytes")
+				assert.Nil(t, actualBody, "not handled")
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, actualBody, size)
+			}
+		}
+	})
+
+	t.Run("tracing", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := tracetest.NewSpanRecorder()
+
+		client := New(
+			"test",
+			"Test123",
+			NewWithHTTPClient(doFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			})),
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		)
+
+		err := client.Get(
+			context.Background(),
+			&Request{
+				Route:      "path/{id}",
+				URL:        "https://example.com/path/1",
+				Query:      url.Values{"key": []string{"secret"}},
+				Attributes: []attribute.KeyValue{attribute.String("a", "b")},
+			},
+			func(res *http.Response, body []byte) error {
+				return errors.New("Test456")
+			},
+		)
+		assert.EqualError(t, err, "Test456")
+
+		spans := recorder.Ended()
+		if assert.Len(t, spans, 1) {
+			assert.Equal(t, "test GET /path/{id}", spans[0].Name())
+			assert.Equal(t, "Test123", spans[0].InstrumentationScope().Name)
+			assert.Equal(t, codes.Error, spans[0].Status().Code)
+			assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/path/1"))
+			assert.Contains(t, spans[0].Attributes(), attribute.String("a", "b"))
+			assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusOK))
+		}
+	})
+}
@@ -0,0 +1,5 @@
+// Package apiclient includes the scaffolding shared by the clients of provider APIs: the options
+// of their New function, and sending traced GET requests whose responses each client decodes.
+package apiclient
+
+//go:generate moq -pkg apiclienttest -out apiclienttest/moq.go . HTTPClient
@@ -0,0 +1,373 @@
+package providerquery
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/byatesrae/weather"
+)
+
+// AggregationMethod decides how weather summaries from multiple providers are combined.
+type AggregationMethod string
+
+const (
+	// AggregationMethodNone does not combine summaries, using the first successful provider
+	// response (see [QueryMode]).
+	AggregationMethodNone AggregationMethod = "none"
+
+	// AggregationMethodMedian queries all providers concurrently, combining each value in
+	// their summaries by median.
+	AggregationMethodMedian AggregationMethod = "median"
+
+	// AggregationMethodWeightedMean queries all providers concurrently, combining each value
+	// in their summaries by mean, weighted by provider (see [WithProviderWeights]).
+	AggregationMethodWeightedMean AggregationMethod = "weighted_mean"
+)
+
+// Valid reports whether m is a known aggregation method.
+func (m AggregationMethod) Valid() bool {
+	switch m {
+	case AggregationMethodNone, AggregationMethodMedian, AggregationMethodWeightedMean:
+		return true
+	default:
+		return false
+	}
+}
+
+// OutlierTolerances are the amounts a provider's values may differ from the median of all
+// providers before that provider is rejected as an outlier when aggregating. A tolerance
+// <= 0 is not checked.
+type OutlierTolerances struct {
+	Temperature float64 // In degrees celsius.
+	WindSpeed   float64 // In km/h.
+	Humidity    float64 // As a percentage.
+	Pressure    float64 // In hPa.
+}
+
+// WeatherSource is the weather summary from a single provider that was aggregated into a
+// [WeatherResult].
+type WeatherSource struct {
+	Provider string           `json:"provider"`
+	Weather  *weather.Summary `json:"weather"`
+	Outlier  bool             `json:"outlier"` // True if the summary was rejected as an outlier, so isn't part of the result.
+}
+
+// weatherLoader returns a function that loads a weather summary using query across providers,
+// either from the first provider to answer or, with an aggregation method, from all providers.
+func (q *Queryer) weatherLoader(query providerQueryFunc) providerLoadFunc {
+	return func(ctx context.Context, logger logr.Logger) (*providerResult, error) {
+		if q.aggregationMethod == AggregationMethodNone {
+			return q.queryAllProviders(ctx, logger, q.providers, query)
+		}
+
+		return q.aggregateAllProviders(ctx, logger, q.providers, query)
+	}
+}
+
+// aggregateAllProviders queries all providers (skipping those with an open circuit breaker)
+// concurrently, aggregating their weather summaries.
+func (q *Queryer) aggregateAllProviders(
+	ctx context.Context,
+	logger logr.Logger,
+	providers []Provider,
+	query providerQueryFunc,
+) (*providerResult, error) {
+	providers, _ = orderProviders(q.ordering, q.stats, providers)
+
+	responses := make(chan *providerResponse, len(providers))
+	inFlight := 0
+
+	for _, provider := range providers {
+		if q.startProviderQuery(ctx, logger, provider, query, responses) {
+			inFlight++
+		}
+	}
+
+	summaries := make(map[string]*weather.Summary, inFlight)
+
+	var queryErr error
+	var errs []error
+
+	for ; inFlight > 0; inFlight-- {
+		res, err := awaitProviderResponse(ctx, responses, 0)
+		if err != nil {
+			logger.Error(err, "Context done before all providers responded, aggregating those that did.")
+
+			errs = append(errs, err)
+
+			break
+		}
+
+		if res.err != nil {
+			logger.Error(res.err, "Failed to query provider.", providerLogKey, res.provider.ProviderName())
+
+			if isQueryError(res.err) {
+				queryErr = res.err
+			} else {
+				errs = append(errs, res.err)
+			}
+		}
+
+		if res.value != nil {
+			summaries[res.provider.ProviderName()] = res.value.(*weather.Summary) // should never panic
+		}
+	}
+
+	if len(summaries) == 0 {
+		if queryErr != nil {
+			return nil, queryErr
+		}
+
+		return nil, noSuccessfulResponsesError(errs)
+	}
+
+	// Sources are listed in query preference order.
+	sources := make([]WeatherSource, 0, len(summaries))
+	for _, provider := range providers {
+		if summary, ok := summaries[provider.ProviderName()]; ok {
+			sources = append(sources, WeatherSource{Provider: provider.ProviderName(), Weather: summary})
+		}
+	}
+
+	markOutliers(sources, q.outlierTolerances)
+
+	var names []string
+	for _, source := range sources {
+		if source.Outlier {
+			logger.Info("Rejected provider summary as an outlier.", providerLogKey, source.Provider)
+
+			continue
+		}
+
+		names = append(names, source.Provider)
+	}
+
+	return &providerResult{
+		value:    aggregateSummaries(sources, q.aggregationMethod, q.providerWeights),
+		provider: strings.Join(names, ","),
+		sources:  sources,
+	}, nil
+}
+
+// summaryField is a numeric field of [weather.Summary].
+type summaryField struct {
+	get func(s *weather.Summary) *float64 // Returns nil if the field isn't set.
+	set func(s *weather.Summary, v float64)
+}
+
+// summaryFields are the numeric fields of [weather.Summary] that are aggregated. Wind
+// direction is aggregated separately, being circular.
+var summaryFields = []summaryField{
+	{
+		get: func(s *weather.Summary) *float64 { return &s.Temperature },
+		set: func(s *weather.Summary, v float64) { s.Temperature = v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return &s.WindSpeed },
+		set: func(s *weather.Summary, v float64) { s.WindSpeed = v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.FeelsLike },
+		set: func(s *weather.Summary, v float64) { s.FeelsLike = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.Humidity },
+		set: func(s *weather.Summary, v float64) { s.Humidity = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.Pressure },
+		set: func(s *weather.Summary, v float64) { s.Pressure = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.CloudCover },
+		set: func(s *weather.Summary, v float64) { s.CloudCover = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.Visibility },
+		set: func(s *weather.Summary, v float64) { s.Visibility = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.WindGust },
+		set: func(s *weather.Summary, v float64) { s.WindGust = &v },
+	},
+	{
+		get: func(s *weather.Summary) *float64 { return s.Precipitation },
+		set: func(s *weather.Summary, v float64) { s.Precipitation = &v },
+	},
+}
+
+// markOutliers marks sources as outliers where any of their values differ from the median
+// of all sources by more than tolerances. Outliers are only rejected if the remaining
+// sources are a majority, otherwise there's no telling which sources are wrong.
+func markOutliers(sources []WeatherSource, tolerances OutlierTolerances) {
+	checks := []struct {
+		get       func(s *weather.Summary) *float64
+		tolerance float64
+	}{
+		{get: func(s *weather.Summary) *float64 { return &s.Temperature }, tolerance: tolerances.Temperature},
+		{get: func(s *weather.Summary) *float64 { return &s.WindSpeed }, tolerance: tolerances.WindSpeed},
+		{get: func(s *weather.Summary) *float64 { return s.Humidity }, tolerance: tolerances.Humidity},
+		{get: func(s *weather.Summary) *float64 { return s.Pressure }, tolerance: tolerances.Pressure},
+	}
+
+	outliers := make([]bool, len(sources))
+	outlierCount := 0
+
+	for _, check := range checks {
+		if check.tolerance <= 0 {
+			continue
+		}
+
+		var values []float64
+		for _, source := range sources {
+			if v := check.get(source.Weather); v != nil {
+				values = append(values, *v)
+			}
+		}
+
+		median := medianOf(values)
+
+		for i, source := range sources {
+			if v := check.get(source.Weather); v != nil && !outliers[i] && math.Abs(*v-median) > check.tolerance {
+				outliers[i] = true
+				outlierCount++
+			}
+		}
+	}
+
+	if outlierCount == 0 || (len(sources)-outlierCount)*2 <= len(sources) {
+		return
+	}
+
+	for i := range sources {
+		sources[i].Outlier = outliers[i]
+	}
+}
+
+// aggregateSummaries combines the summaries of sources (excluding outliers) using method.
+// Values not supplied by any source are left unset. Wind direction is the (weighted)
+// circular mean, the condition is that of the most preferred source supplying one & the
+// observation time is the oldest of the sources.
+func aggregateSummaries(sources []WeatherSource, method AggregationMethod, weights map[string]float64) *weather.Summary {
+	var accepted []WeatherSource
+	for _, source := range sources {
+		if !source.Outlier {
+			accepted = append(accepted, source)
+		}
+	}
+
+	// weight returns the weight of provider, defaulting to 1.
+	weight := func(provider string) float64 {
+		if w, ok := weights[provider]; ok {
+			return w
+		}
+
+		return 1
+	}
+
+	summary := &weather.Summary{}
+
+	for _, field := range summaryFields {
+		var values, valueWeights []float64
+
+		for _, source := range accepted {
+			if v := field.get(source.Weather); v != nil {
+				values = append(values, *v)
+				valueWeights = append(valueWeights, weight(source.Provider))
+			}
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if method == AggregationMethodWeightedMean {
+			field.set(summary, roundTo(weightedMeanOf(values, valueWeights), 2))
+		} else {
+			field.set(summary, roundTo(medianOf(values), 2))
+		}
+	}
+
+	var x, y float64
+	var hasWindDirection bool
+
+	for _, source := range accepted {
+		if source.Weather.WindDirection == nil {
+			continue
+		}
+
+		radians := *source.Weather.WindDirection * math.Pi / 180
+		x += math.Cos(radians) * weight(source.Provider)
+		y += math.Sin(radians) * weight(source.Provider)
+		hasWindDirection = true
+	}
+
+	if hasWindDirection {
+		degrees := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+		summary.SetWindDirection(roundTo(degrees, 0))
+	}
+
+	for _, source := range accepted {
+		if source.Weather.Condition != nil {
+			condition := *source.Weather.Condition
+			summary.Condition = &condition
+
+			break
+		}
+	}
+
+	for _, source := range accepted {
+		if observedAt := source.Weather.ObservedAt; observedAt != nil && (summary.ObservedAt == nil || observedAt.Before(*summary.ObservedAt)) {
+			oldest := *observedAt
+			summary.ObservedAt = &oldest
+		}
+	}
+
+	return summary
+}
+
+// medianOf returns the median of values (or 0 if there are none).
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+// weightedMeanOf returns the mean of values weighted by weights. If the weights sum to 0,
+// the unweighted mean is returned.
+func weightedMeanOf(values, weights []float64) float64 {
+	var sum, weightedSum, weightSum float64
+
+	for i, v := range values {
+		sum += v
+		weightedSum += v * weights[i]
+		weightSum += weights[i]
+	}
+
+	if weightSum == 0 {
+		return sum / float64(len(values))
+	}
+
+	return weightedSum / weightSum
+}
+
+// roundTo rounds v to places decimal places.
+func roundTo(v float64, places int) float64 {
+	shift := math.Pow(10, float64(places))
+
+	return math.Round(v*shift) / shift
+}
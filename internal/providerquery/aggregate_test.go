@@ -0,0 +1,204 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestAggregateSummaries(t *testing.T) {
+	t.Parallel()
+
+	observedAt := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	rain := weather.NewCondition(weather.ConditionRain)
+
+	sources := []WeatherSource{
+		{Provider: "a", Weather: &weather.Summary{Temperature: 10, WindSpeed: 10, WindDirection: floatPtr(350), ObservedAt: &observedAt}},
+		{Provider: "b", Weather: &weather.Summary{Temperature: 11, WindSpeed: 20, WindDirection: floatPtr(10), Humidity: floatPtr(50), Condition: rain}},
+		{Provider: "c", Weather: &weather.Summary{Temperature: 15, WindSpeed: 60}},
+		{Provider: "d", Weather: &weather.Summary{Temperature: 100}, Outlier: true},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		giveMethod  AggregationMethod
+		giveWeights map[string]float64
+		expected    *weather.Summary
+	}{
+		{
+			name:       "median",
+			giveMethod: AggregationMethodMedian,
+			expected: &weather.Summary{
+				Temperature:          11,
+				WindSpeed:            20,
+				Humidity:             floatPtr(50),
+				WindDirection:        floatPtr(0),
+				WindDirectionCompass: "N",
+				Condition:            rain,
+				ObservedAt:           &observedAt,
+			},
+		},
+		{
+			name:        "weighted_mean",
+			giveMethod:  AggregationMethodWeightedMean,
+			giveWeights: map[string]float64{"a": 2, "c": 0},
+			expected: &weather.Summary{
+				Temperature:          10.33,
+				WindSpeed:            13.33,
+				Humidity:             floatPtr(50),
+				WindDirection:        floatPtr(357),
+				WindDirectionCompass: "N",
+				Condition:            rain,
+				ObservedAt:           &observedAt,
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, aggregateSummaries(sources, tc.giveMethod, tc.giveWeights))
+		})
+	}
+}
+
+func TestMarkOutliers(t *testing.T) {
+	t.Parallel()
+
+	tolerances := OutlierTolerances{Temperature: 5, Humidity: 20}
+
+	for _, tc := range []struct {
+		name     string
+		give     []*weather.Summary
+		expected []bool
+	}{
+		{
+			name: "none",
+			give: []*weather.Summary{
+				{Temperature: 10},
+				{Temperature: 12},
+				{Temperature: 14},
+			},
+			expected: []bool{false, false, false},
+		},
+		{
+			name: "temperature",
+			give: []*weather.Summary{
+				{Temperature: 10},
+				{Temperature: 11},
+				{Temperature: 20},
+			},
+			expected: []bool{false, false, true},
+		},
+		{
+			name: "humidity",
+			give: []*weather.Summary{
+				{Temperature: 10, Humidity: floatPtr(10)},
+				{Temperature: 10, Humidity: floatPtr(80)},
+				{Temperature: 10, Humidity: floatPtr(85)},
+				{Temperature: 10},
+			},
+			expected: []bool{true, false, false, false},
+		},
+		{
+			name: "no_majority",
+			give: []*weather.Summary{
+				{Temperature: 10},
+				{Temperature: 30},
+			},
+			expected: []bool{false, false},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sources := make([]WeatherSource, len(tc.give))
+			for i, summary := range tc.give {
+				sources[i] = WeatherSource{Weather: summary}
+			}
+
+			markOutliers(sources, tolerances)
+
+			actual := make([]bool, len(sources))
+			for i, source := range sources {
+				actual[i] = source.Outlier
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestQueryerReadWeatherResultAggregated(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	// newProvider creates a provider named name that answers with temperature, or err if set.
+	newProvider := func(name string, temperature float64, err error) Provider {
+		return &ProviderMock{
+			GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+				if err != nil {
+					return nil, err
+				}
+
+				return &weather.Summary{Temperature: temperature}, nil
+			},
+			ProviderNameFunc: func() string {
+				return name
+			},
+		}
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{
+			newProvider("a", 10, nil),
+			newProvider("b", 30, nil),
+			newProvider("c", 0, errors.New("intentional test error")),
+			newProvider("d", 12, nil),
+		},
+		emptyCache,
+		withClock(clock),
+		WithAggregationMethod(AggregationMethodMedian),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	actual, err := queryer.ReadWeatherResult(ctx, "Sydney")
+	require.NoError(t, err)
+
+	assert.Equal(t, &weather.Summary{Temperature: 11}, actual.Weather)
+	assert.Equal(t, "a,d", actual.Provider)
+	assert.Equal(
+		t,
+		[]WeatherSource{
+			{Provider: "a", Weather: &weather.Summary{Temperature: 10}},
+			{Provider: "b", Weather: &weather.Summary{Temperature: 30}, Outlier: true},
+			{Provider: "d", Weather: &weather.Summary{Temperature: 12}},
+		},
+		actual.Sources,
+	)
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
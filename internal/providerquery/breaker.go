@@ -0,0 +1,217 @@
+package providerquery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// breakerState is the state of a [circuitBreaker].
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // The provider is queried as normal.
+	breakerHalfOpen                     // A limited number of probe queries are let through to the provider.
+	breakerOpen                         // The provider is not queried.
+)
+
+// String returns the name of the state, as used in logs & metrics.
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half_open"
+	case breakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// breakerOutcome is the outcome of a provider query let through by a [circuitBreaker].
+type breakerOutcome int
+
+const (
+	breakerSuccess   breakerOutcome = iota // The provider responded successfully.
+	breakerFailure                         // The provider failed to respond successfully.
+	breakerAbandoned                       // The query was abandoned (not the fault of the provider).
+	breakerRejected                        // The provider rejected the query, and will keep doing so.
+)
+
+// String returns the name of the outcome, as used in metrics.
+func (o breakerOutcome) String() string {
+	switch o {
+	case breakerSuccess:
+		return "success"
+	case breakerFailure:
+		return "failure"
+	case breakerAbandoned:
+		return "abandoned"
+	case breakerRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// newBreakerOutcome determines the outcome of a provider query that returned err, where
+// ctx is the context the query was made under.
+func newBreakerOutcome(ctx context.Context, err error) breakerOutcome {
+	switch {
+	case err == nil:
+		return breakerSuccess
+	case ctx.Err() != nil:
+		return breakerAbandoned
+	case isQueryError(err), errors.Is(err, ErrLocationUnsupported):
+		return breakerSuccess
+	case errors.Is(err, ErrProviderRejected):
+		return breakerRejected
+	default:
+		return breakerFailure
+	}
+}
+
+// circuitBreaker guards a single provider. After failureThreshold consecutive failures the
+// breaker opens and the provider is skipped. Once coolDown has elapsed the breaker is
+// half-open, letting up to halfOpenProbes queries through; a successful probe closes the
+// breaker while a failed probe opens it again. A rejected query opens the breaker at once.
+type circuitBreaker struct {
+	provider         string
+	failureThreshold int // A value <= 0 disables the breaker.
+	coolDown         time.Duration
+	halfOpenProbes   int
+	clock            Clock
+	metrics          *Metrics
+
+	mu         sync.Mutex
+	state      breakerState
+	generation uint64 // Incremented on every state transition.
+	failures   int    // Consecutive failures while closed.
+	openedAt   time.Time
+	probes     int // Probes in flight while half-open.
+}
+
+// newCircuitBreaker creates a new (closed) [circuitBreaker] for provider.
+func newCircuitBreaker(provider string, options *NewOptions) *circuitBreaker {
+	return &circuitBreaker{
+		provider:         provider,
+		failureThreshold: options.breakerFailureThreshold,
+		coolDown:         options.breakerCoolDown,
+		halfOpenProbes:   options.breakerHalfOpenProbes,
+		clock:            options.clock,
+		metrics:          options.metrics,
+	}
+}
+
+// allow reports whether the provider may be queried. If it is allowed, done must be called
+// with the outcome of the query.
+func (b *circuitBreaker) allow(logger logr.Logger) (done func(outcome breakerOutcome), allowed bool) {
+	if b == nil || b.failureThreshold <= 0 {
+		return func(breakerOutcome) {}, true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && b.clock.Now().Sub(b.openedAt) >= b.coolDown {
+		b.transition(logger, breakerHalfOpen)
+	}
+
+	switch b.state {
+	case breakerClosed:
+		generation := b.generation
+
+		return func(outcome breakerOutcome) { b.done(logger, generation, false, outcome) }, true
+	case breakerHalfOpen:
+		if b.probes >= b.halfOpenProbes {
+			return nil, false
+		}
+
+		b.probes++
+		generation := b.generation
+
+		return func(outcome breakerOutcome) { b.done(logger, generation, true, outcome) }, true
+	default:
+		return nil, false
+	}
+}
+
+// done records the outcome of a query let through while the breaker was in the state
+// identified by generation. Outcomes of queries that straddle a transition are ignored.
+func (b *circuitBreaker) done(logger logr.Logger, generation uint64, probe bool, outcome breakerOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	if probe {
+		b.probes--
+	}
+
+	switch {
+	case outcome == breakerAbandoned:
+	case outcome == breakerRejected:
+		b.transition(logger, breakerOpen)
+	case outcome == breakerSuccess && probe:
+		b.transition(logger, breakerClosed)
+	case outcome == breakerSuccess:
+		b.failures = 0
+	case probe:
+		b.transition(logger, breakerOpen)
+	default:
+		b.failures++
+
+		if b.failures >= b.failureThreshold {
+			b.transition(logger, breakerOpen)
+		}
+	}
+}
+
+// transition moves the breaker to state to. b.mu must be held.
+func (b *circuitBreaker) transition(logger logr.Logger, to breakerState) {
+	from := b.state
+
+	b.state = to
+	b.generation++
+	b.failures = 0
+	b.probes = 0
+
+	if to == breakerOpen {
+		b.openedAt = b.clock.Now()
+	}
+
+	logger.Info("Circuit breaker state changed.", providerLogKey, b.provider, "from", from.String(), "to", to.String())
+
+	b.metrics.recordBreakerTransition(b.provider, from, to)
+}
+
+// currentState returns the state of the breaker.
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// nextState returns the state the breaker will be in for the next query, an open breaker
+// whose cool-down has elapsed being half-open.
+func (b *circuitBreaker) nextState() breakerState {
+	if b == nil || b.failureThreshold <= 0 {
+		return breakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && b.clock.Now().Sub(b.openedAt) >= b.coolDown {
+		return breakerHalfOpen
+	}
+
+	return b.state
+}
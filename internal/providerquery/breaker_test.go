@@ -0,0 +1,208 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	clock := &manualClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+	logger := nooplogr.New()
+
+	breaker := newCircuitBreaker("provider", &NewOptions{
+		clock:                   clock,
+		breakerFailureThreshold: 2,
+		breakerCoolDown:         time.Minute,
+		breakerHalfOpenProbes:   1,
+	})
+
+	// query is a query let through by the breaker, with the given outcome.
+	query := func(outcome breakerOutcome) {
+		t.Helper()
+
+		done, allowed := breaker.allow(logger)
+		require.True(t, allowed, "allowed")
+
+		done(outcome)
+	}
+
+	// Closed: a success resets the consecutive failures, abandoned queries aren't counted.
+	query(breakerFailure)
+	query(breakerSuccess)
+	query(breakerFailure)
+	query(breakerAbandoned)
+	assert.Equal(t, breakerClosed, breaker.currentState())
+
+	// Closed -> open.
+	query(breakerFailure)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	_, allowed := breaker.allow(logger)
+	assert.False(t, allowed, "allowed while open")
+
+	// Open -> half-open, limited to a single probe.
+	clock.Add(time.Minute)
+
+	probeDone, allowed := breaker.allow(logger)
+	require.True(t, allowed, "probe allowed")
+	assert.Equal(t, breakerHalfOpen, breaker.currentState())
+
+	_, allowed = breaker.allow(logger)
+	assert.False(t, allowed, "allowed while probe in flight")
+
+	// Half-open -> open.
+	probeDone(breakerFailure)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	// Half-open (abandoned probe doesn't count) -> closed.
+	clock.Add(time.Minute)
+
+	query(breakerAbandoned)
+	assert.Equal(t, breakerHalfOpen, breaker.currentState())
+
+	query(breakerSuccess)
+	assert.Equal(t, breakerClosed, breaker.currentState())
+
+	// Outcomes of queries that straddle a transition are ignored.
+	staleDone, allowed := breaker.allow(logger)
+	require.True(t, allowed, "allowed")
+
+	query(breakerFailure)
+	query(breakerFailure)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	staleDone(breakerSuccess)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	// Closed -> open at once, on a rejected query.
+	clock.Add(time.Minute)
+	query(breakerSuccess)
+	assert.Equal(t, breakerClosed, breaker.currentState())
+
+	query(breakerRejected)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+}
+
+func TestNewBreakerOutcome(t *testing.T) {
+	t.Parallel()
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tc := range []struct {
+		name     string
+		giveCtx  context.Context
+		giveErr  error
+		expected breakerOutcome
+	}{
+		{name: "success", giveCtx: context.Background(), expected: breakerSuccess},
+		{name: "failure", giveCtx: context.Background(), giveErr: errors.New("Test123"), expected: breakerFailure},
+		{name: "abandoned", giveCtx: cancelledCtx, giveErr: errors.New("Test123"), expected: breakerAbandoned},
+		{name: "location_not_found", giveCtx: context.Background(), giveErr: fmt.Errorf("Test123: %w", ErrLocationNotFound), expected: breakerSuccess},
+		{name: "invalid_input", giveCtx: context.Background(), giveErr: fmt.Errorf("Test123: %w", ErrInvalidInput), expected: breakerSuccess},
+		{name: "location_unsupported", giveCtx: context.Background(), giveErr: fmt.Errorf("Test123: %w", ErrLocationUnsupported), expected: breakerSuccess},
+		{name: "rejected", giveCtx: context.Background(), giveErr: fmt.Errorf("Test123: %w", ErrProviderRejected), expected: breakerRejected},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, newBreakerOutcome(tc.giveCtx, tc.giveErr))
+		})
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	t.Parallel()
+
+	breaker := newCircuitBreaker("provider", &NewOptions{
+		clock:                   fixedClock{},
+		breakerFailureThreshold: 0,
+	})
+
+	for i := 0; i < 10; i++ {
+		done, allowed := breaker.allow(nooplogr.New())
+		require.True(t, allowed, "allowed")
+
+		done(breakerFailure)
+	}
+
+	assert.Equal(t, breakerClosed, breaker.currentState())
+}
+
+func TestQueryerReadWeatherResultCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	clock := &manualClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	errProviderUp := false
+	errProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			if errProviderUp {
+				return &weather.Summary{Temperature: 1}, nil
+			}
+
+			return nil, errors.New("intentional test error")
+		},
+		ProviderNameFunc: func() string {
+			return "errProvider"
+		},
+	}
+
+	goodProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 2}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{errProvider, goodProvider},
+		emptyCache,
+		withClock(clock),
+		WithBreakerFailureThreshold(2),
+		WithBreakerCoolDown(time.Minute),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	for i := 0; i < 4; i++ {
+		result, err := queryer.ReadWeatherResult(ctx, "Sydney")
+		require.NoError(t, err)
+		assert.Equal(t, "goodProvider", result.Provider)
+	}
+
+	assert.Len(t, errProvider.GetWeatherSummaryCalls(), 2, "errProvider skipped once the breaker is open")
+
+	errProviderUp = true
+
+	clock.Add(time.Minute)
+
+	result, err := queryer.ReadWeatherResult(ctx, "Sydney")
+	require.NoError(t, err)
+	assert.Equal(t, "errProvider", result.Provider, "errProvider probed once the breaker is half-open")
+}
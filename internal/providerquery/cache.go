@@ -2,21 +2,140 @@ package providerquery
 
 import (
 	"context"
+	"encoding"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/byatesrae/weather"
 )
 
-// resultCacheKey is used as a key to cache resultCacheEntry.
-type resultCacheKey struct{}
+// resultCacheKey is used as a key to cache resultCacheEntry. Only one of city or
+// coordinates is set.
+type resultCacheKey struct {
+	city         string // The normalized city name, see normalizeCity.
+	coordinates  string // The bucketed coordinates, see Coordinates.format.
+	forecastDays int    // If non-zero, the key is for a forecast covering this many days.
+}
+
+// String returns a string uniquely representing the key.
+func (k resultCacheKey) String() string {
+	location := "city:" + k.city
+	if k.coordinates != "" {
+		location = "coordinates:" + k.coordinates
+	}
+
+	if k.forecastDays != 0 {
+		return "forecast:" + strconv.Itoa(k.forecastDays) + ":" + location
+	}
+
+	return location
+}
+
+// normalizeCity normalizes a city name such that variations in case and whitespace
+// resolve to the same value (e.g. " sydney" & "Sydney").
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
 
-// resultCacheEntry wraps a weather summary to be cached.
+// resultCacheEntry wraps a weather summary or forecast to be cached.
 type resultCacheEntry struct {
-	result    *weather.Summary
+	result    interface{} // Either *weather.Summary or *weather.Forecast.
+	provider  string      // The name of the provider the result was fetched from.
+	sources   []WeatherSource
 	createdAt time.Time
 }
 
-// Cache is used to store & retrieve responses.
+var _ encoding.BinaryMarshaler = resultCacheEntry{}
+
+// resultCacheEntryVersion is the version of the serialized form of resultCacheEntry. It must
+// be incremented whenever that form changes incompatibly, such that entries serialized by
+// other versions (e.g. replicas sharing a cache during a deployment) are ignored rather than
+// misread.
+const resultCacheEntryVersion = 1
+
+// serializedResultCacheEntry is the serialized form of resultCacheEntry. Only one of Summary
+// or Forecast is set.
+type serializedResultCacheEntry struct {
+	Version   int               `json:"version"`
+	Summary   *weather.Summary  `json:"summary,omitempty"`
+	Forecast  *weather.Forecast `json:"forecast,omitempty"`
+	Provider  string            `json:"provider"`
+	Sources   []WeatherSource   `json:"sources,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, allowing the entry to be stored by caches
+// that serialize values (see [Cache]).
+func (e resultCacheEntry) MarshalBinary() ([]byte, error) {
+	serialized := serializedResultCacheEntry{
+		Version:   resultCacheEntryVersion,
+		Provider:  e.provider,
+		Sources:   e.sources,
+		CreatedAt: e.createdAt,
+	}
+
+	switch result := e.result.(type) {
+	case *weather.Summary:
+		serialized.Summary = result
+	case *weather.Forecast:
+		serialized.Forecast = result
+	default:
+		return nil, errors.Errorf("providerquery: unsupported cache entry result %T", e.result)
+	}
+
+	data, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, errors.Wrap(err, "providerquery: marshal cache entry")
+	}
+
+	return data, nil
+}
+
+// unmarshalResultCacheEntry parses data serialized by resultCacheEntry.MarshalBinary.
+func unmarshalResultCacheEntry(data []byte) (resultCacheEntry, error) {
+	var serialized serializedResultCacheEntry
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return resultCacheEntry{}, errors.Wrap(err, "providerquery: unmarshal cache entry")
+	}
+
+	if serialized.Version != resultCacheEntryVersion {
+		return resultCacheEntry{}, errors.Errorf(
+			"providerquery: unsupported cache entry version %v, expected %v",
+			serialized.Version,
+			resultCacheEntryVersion,
+		)
+	}
+
+	entry := resultCacheEntry{
+		provider:  serialized.Provider,
+		sources:   serialized.Sources,
+		createdAt: serialized.CreatedAt,
+	}
+
+	switch {
+	case serialized.Summary != nil:
+		entry.result = serialized.Summary
+	case serialized.Forecast != nil:
+		entry.result = serialized.Forecast
+	default:
+		return resultCacheEntry{}, errors.New("providerquery: cache entry has no result")
+	}
+
+	return entry, nil
+}
+
+// Cache is used to store & retrieve responses. Values are set as resultCacheEntry, which a
+// cache may store as is or serialized (see [encoding.BinaryMarshaler]), in which case Get
+// returns the serialized []byte.
+//
+// A cache that loads results itself (e.g. shared by replicas, one of which loads each result,
+// see [Queryer.ReadSerializedResult]) may return the error of a failed load from Get, wrapping
+// its class (e.g [ErrLocationNotFound]). It's returned as is, rather than the result being
+// loaded again. Any other error is treated as a cache miss.
 type Cache interface {
 	Get(ctx context.Context, key interface{}) (interface{}, time.Time, error)
 	Set(ctx context.Context, key, val interface{}, expiry time.Time) error
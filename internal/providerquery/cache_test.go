@@ -0,0 +1,152 @@
+package providerquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestResultCacheEntryMarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	start := createdAt.Truncate(time.Hour * 24)
+
+	for _, tc := range []struct {
+		name string
+		give resultCacheEntry
+	}{
+		{
+			name: "summary",
+			give: resultCacheEntry{
+				result: &weather.Summary{
+					Temperature: 12.5,
+					Humidity:    floatPtr(50),
+					Condition:   weather.NewCondition(weather.ConditionRain),
+					ObservedAt:  &createdAt,
+				},
+				provider:  "a,b",
+				sources:   []WeatherSource{{Provider: "a", Weather: &weather.Summary{Temperature: 12}}, {Provider: "b", Weather: &weather.Summary{Temperature: 13}}},
+				createdAt: createdAt,
+			},
+		},
+		{
+			name: "forecast",
+			give: resultCacheEntry{
+				result: &weather.Forecast{
+					Daily: []weather.ForecastEntry{{Summary: weather.Summary{Temperature: 20}, Start: start, End: start.Add(time.Hour * 24)}},
+				},
+				provider:  "a",
+				createdAt: createdAt,
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tc.give.MarshalBinary()
+			require.NoError(t, err)
+
+			actual, err := unmarshalResultCacheEntry(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.give, actual)
+		})
+	}
+}
+
+func TestUnmarshalResultCacheEntryErr(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        string
+		expectedErr string
+	}{
+		{
+			name:        "invalid",
+			give:        "{",
+			expectedErr: "providerquery: unmarshal cache entry: unexpected end of JSON input",
+		},
+		{
+			name:        "other_version",
+			give:        `{"version":999,"summary":{}}`,
+			expectedErr: "providerquery: unsupported cache entry version 999, expected 1",
+		},
+		{
+			name:        "no_result",
+			give:        `{"version":1}`,
+			expectedErr: "providerquery: cache entry has no result",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := unmarshalResultCacheEntry([]byte(tc.give))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestQueryerReadWeatherResultSerializedCache(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	provider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 20}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	for _, tc := range []struct {
+		name             string
+		giveCached       string
+		expectedProvider string
+	}{
+		{
+			name:             "cached",
+			giveCached:       `{"version":1,"summary":{"wind_speed":0,"temperature_degrees":10},"provider":"cachedProvider","created_at":"2020-11-11T10:10:00Z"}`,
+			expectedProvider: "cachedProvider",
+		},
+		{
+			name:             "other_version",
+			giveCached:       `{"version":999}`,
+			expectedProvider: "goodProvider",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			serializingCache := &CacheMock{
+				GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+					return []byte(tc.giveCached), clock.now.Add(time.Minute), nil
+				},
+				SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+					return nil
+				},
+			}
+
+			queryer := newTestQueryer(t, []Provider{provider}, serializingCache, withClock(clock))
+
+			actual, err := queryer.ReadWeatherResult(context.Background(), "Sydney")
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedProvider, actual.Provider)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package providerquery
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 // fixedClock returns a preset time.
 type fixedClock struct{ now time.Time }
@@ -11,3 +14,27 @@ var _ Clock = (*fixedClock)(nil)
 func (c fixedClock) Now() time.Time {
 	return c.now
 }
+
+// manualClock returns a time that is moved forward manually, safe for use across goroutines.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+var _ Clock = (*manualClock)(nil)
+
+// Now returns the current time of the clock.
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Add moves the clock forward by d.
+func (c *manualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,39 @@
+package providerquery
+
+import (
+	"context"
+	"time"
+)
+
+// detachedContext carries the values of its parent, but not its deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+var _ context.Context = detachedContext{}
+
+// detachContext returns a context with the values of parent that is never done. It's used for
+// work that should outlive the request that started it (e.g. caching or refreshing a result).
+func detachContext(parent context.Context) context.Context {
+	return detachedContext{parent: parent}
+}
+
+// Deadline implements context.Context, never having a deadline.
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements context.Context, never being done.
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err implements context.Context, never being done.
+func (detachedContext) Err() error {
+	return nil
+}
+
+// Value implements context.Context, returning the value of the parent context.
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,44 @@
+package providerquery
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Coordinates is a location expressed in decimal degrees.
+type Coordinates struct {
+	Latitude  float64 // In the range [-90, 90].
+	Longitude float64 // In the range [-180, 180].
+}
+
+// Validate returns an error if the coordinates are out of range.
+func (c Coordinates) Validate() error {
+	if math.IsNaN(c.Latitude) || c.Latitude < -90 || c.Latitude > 90 {
+		return fmt.Errorf("latitude %v is not in the range [-90, 90]", c.Latitude)
+	}
+
+	if math.IsNaN(c.Longitude) || c.Longitude < -180 || c.Longitude > 180 {
+		return fmt.Errorf("longitude %v is not in the range [-180, 180]", c.Longitude)
+	}
+
+	return nil
+}
+
+// bucket rounds the coordinates to precision decimal places, such that nearby
+// coordinates resolve to the same value.
+func (c Coordinates) bucket(precision int) Coordinates {
+	scale := math.Pow(10, float64(precision))
+
+	// Adding 0 turns a negative zero (e.g -0.001 rounded) into 0, which would otherwise
+	// format as "-0" and resolve to a different value than 0.
+	return Coordinates{
+		Latitude:  math.Round(c.Latitude*scale)/scale + 0,
+		Longitude: math.Round(c.Longitude*scale)/scale + 0,
+	}
+}
+
+// format formats the coordinates as "latitude,longitude" with precision decimal places.
+func (c Coordinates) format(precision int) string {
+	return strconv.FormatFloat(c.Latitude, 'f', precision, 64) + "," + strconv.FormatFloat(c.Longitude, 'f', precision, 64)
+}
@@ -0,0 +1,66 @@
+package providerquery
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinatesValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        Coordinates
+		expectedErr string
+	}{
+		{name: "valid", give: Coordinates{Latitude: -33.87, Longitude: 151.21}},
+		{name: "valid_bounds", give: Coordinates{Latitude: 90, Longitude: -180}},
+		{
+			name:        "latitude_out_of_range",
+			give:        Coordinates{Latitude: 90.1},
+			expectedErr: "latitude 90.1 is not in the range [-90, 90]",
+		},
+		{
+			name:        "longitude_out_of_range",
+			give:        Coordinates{Longitude: -180.1},
+			expectedErr: "longitude -180.1 is not in the range [-180, 180]",
+		},
+		{
+			name:        "latitude_nan",
+			give:        Coordinates{Latitude: math.NaN()},
+			expectedErr: "latitude NaN is not in the range [-90, 90]",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.give.Validate()
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCoordinatesBucket(t *testing.T) {
+	t.Parallel()
+
+	c := Coordinates{Latitude: -33.8688, Longitude: 151.2093}
+
+	assert.Equal(t, Coordinates{Latitude: -33.87, Longitude: 151.21}, c.bucket(2))
+	assert.Equal(t, Coordinates{Latitude: -34, Longitude: 151}, c.bucket(0))
+	assert.Equal(t, "-33.87,151.21", c.bucket(2).format(2))
+	assert.Equal(t, "-34,151", c.bucket(0).format(0))
+
+	negativeZero := Coordinates{Latitude: -0.001, Longitude: math.Copysign(0, -1)}.bucket(2)
+	assert.False(t, math.Signbit(negativeZero.Latitude), "latitude not negative zero")
+	assert.False(t, math.Signbit(negativeZero.Longitude), "longitude not negative zero")
+	assert.Equal(t, "0.00,0.00", negativeZero.format(2))
+}
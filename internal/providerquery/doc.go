@@ -1,4 +1,4 @@
 // Package providerquery provides a service to query weather summary providers.
 package providerquery
 
-//go:generate moq -out moq_test.go . Provider Cache
+//go:generate moq -out moq_test.go . Provider ForecastProvider Cache
@@ -0,0 +1,116 @@
+package providerquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of errors (see errors.Is), shared by the Queryer & providers such that callers can
+// tell a bad query from an outage. A [Provider] wraps the class its error belongs to, if any,
+// such that the Queryer can treat it differently.
+var (
+	// ErrLocationNotFound is returned by a provider that doesn't know the location queried.
+	// The provider did answer, so isn't counted as failing by its circuit breaker.
+	ErrLocationNotFound = errors.New("providerquery: location not found")
+
+	// ErrInvalidInput is returned for a query that is invalid, either by the Queryer or by a
+	// provider that can't answer it. Like [ErrLocationNotFound], it isn't counted as failing.
+	ErrInvalidInput = errors.New("providerquery: invalid input")
+
+	// ErrLocationUnsupported is returned by a provider that doesn't cover the location queried
+	// (e.g. a regional provider), which other providers may. Unlike [ErrLocationNotFound] the
+	// Queryer fails over to the next provider, but it isn't counted as failing either.
+	ErrLocationUnsupported = errors.New("providerquery: location unsupported")
+
+	// ErrProviderRejected is returned by a provider rejecting every query until it's
+	// reconfigured or its quota resets (e.g. an invalid key or exceeded quota). Its circuit
+	// breaker opens immediately, rather than after repeated failures.
+	ErrProviderRejected = errors.New("providerquery: provider rejected query")
+
+	// ErrProvidersUnavailable is returned by the Queryer when no provider answered a query
+	// successfully (or none could be queried, their circuit breakers being open) and there is
+	// no cached result to fall back on.
+	ErrProvidersUnavailable = errors.New("providerquery: all providers unavailable")
+
+	// ErrUpstreamTimeout is returned by the Queryer instead of [ErrProvidersUnavailable] when
+	// the providers queried didn't answer in time.
+	ErrUpstreamTimeout = errors.New("providerquery: upstream timeout")
+)
+
+// isQueryError reports whether err is a provider refusing the query itself, rather than
+// failing to answer it, such that the other providers would too.
+func isQueryError(err error) bool {
+	return errors.Is(err, ErrLocationNotFound) || errors.Is(err, ErrInvalidInput)
+}
+
+// isLoadError reports whether err is the error of loading a result, such that loading it again
+// would fail the same way.
+func isLoadError(err error) bool {
+	return isQueryError(err) || errors.Is(err, ErrProvidersUnavailable) || errors.Is(err, ErrUpstreamTimeout)
+}
+
+// isTimeout reports whether err is the result of a deadline being exceeded.
+func isTimeout(err error) bool {
+	var timeoutErr interface{ Timeout() bool }
+
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout())
+}
+
+// noSuccessfulResponsesError returns the error for a query no provider answered successfully,
+// given the errors of those queried: an [ErrLocationNotFound] if none of them cover the
+// location, an [ErrUpstreamTimeout] if they all timed out, otherwise an
+// [ErrProvidersUnavailable].
+func noSuccessfulResponsesError(errs []error) error {
+	unsupported := len(errs) > 0
+	timedOut := len(errs) > 0
+
+	for _, err := range errs {
+		if !errors.Is(err, ErrLocationUnsupported) {
+			unsupported = false
+		}
+
+		if !isTimeout(err) {
+			timedOut = false
+		}
+	}
+
+	if unsupported {
+		return fmt.Errorf("%w: no provider covers the location", ErrLocationNotFound)
+	}
+
+	if timedOut {
+		return fmt.Errorf("%w: no successful provider responses", ErrUpstreamTimeout)
+	}
+
+	return fmt.Errorf("%w: no successful provider responses", ErrProvidersUnavailable)
+}
+
+// contextDoneError returns the error for a query abandoned because ctx is done with err.
+func contextDoneError(err error) error {
+	err = errors.Wrap(err, "providerquery: context done before exhausting providers")
+	if isTimeout(err) {
+		return fmt.Errorf("%w: %w", ErrUpstreamTimeout, err)
+	}
+
+	return err
+}
+
+// TooStaleError is returned when a new result can't be loaded and the cached result has been
+// expired for longer than the max staleness (see [WithMaxStaleness]).
+type TooStaleError struct {
+	Staleness    time.Duration // How long the cached result has been expired for.
+	MaxStaleness time.Duration
+	RetryAfter   time.Duration // A suggestion of how long to wait before trying again.
+}
+
+// Error implements error.
+func (e *TooStaleError) Error() string {
+	return fmt.Sprintf(
+		"providerquery: cached result expired %v ago, exceeding the max staleness of %v",
+		e.Staleness,
+		e.MaxStaleness,
+	)
+}
@@ -0,0 +1,94 @@
+package providerquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/byatesrae/weather"
+)
+
+// ForecastResult contains a weather forecast and timeline data.
+type ForecastResult struct {
+	Expiry    time.Time
+	CreatedAt time.Time
+	Forecast  *weather.Forecast
+	Stale     bool          // True if the result is being served past Expiry, because it could not be refreshed.
+	Age       time.Duration // The amount of time since the result was fetched from the provider.
+}
+
+// ReadForecastResult will query one or more providers (those implementing [ForecastProvider])
+// for a forecast covering days days for city. Like [Queryer.ReadWeatherResult], the result
+// will be cached and sometimes served stale.
+func (q *Queryer) ReadForecastResult(ctx context.Context, city string, days int) (_ *ForecastResult, err error) {
+	ctx, span := q.tracer.Start(ctx, "Queryer.ReadForecastResult", trace.WithAttributes(
+		attribute.String(cityAttributeKey, city),
+		attribute.Int(forecastDaysAttributeKey, days),
+	))
+	defer func() { endSpan(span, err) }()
+
+	city = strings.Join(strings.Fields(city), " ")
+	if city == "" {
+		return nil, fmt.Errorf("%w: city is required", ErrInvalidInput)
+	}
+
+	if days < 1 {
+		return nil, fmt.Errorf("%w: days must be at least 1", ErrInvalidInput)
+	}
+
+	key := resultCacheKey{city: normalizeCity(city), forecastDays: days}
+
+	logger := q.getLoggerFromContext(ctx).WithValues(cityLogKey, key.city, forecastDaysLogKey, days)
+
+	res, err := q.readResult(ctx, logger, key, q.forecastCacheTTL, q.forecastLoader(cityForecastQuery(city, days)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ForecastResult{
+		Forecast:  res.value.(*weather.Forecast), // should never panic
+		CreatedAt: res.createdAt,
+		Expiry:    res.expiry,
+		Stale:     res.stale,
+		Age:       res.age,
+	}, nil
+}
+
+// cityForecastQuery returns the provider query for a forecast covering days days for city.
+func cityForecastQuery(city string, days int) providerQueryFunc {
+	return func(ctx context.Context, provider Provider) (interface{}, error) {
+		forecast, err := provider.(ForecastProvider).GetWeatherForecast(ctx, city, days) // should never panic
+		if forecast == nil {
+			return nil, err
+		}
+
+		return forecast, err
+	}
+}
+
+// forecastLoader returns a function that loads a forecast using query across the providers
+// implementing [ForecastProvider].
+func (q *Queryer) forecastLoader(query providerQueryFunc) providerLoadFunc {
+	return func(ctx context.Context, logger logr.Logger) (*providerResult, error) {
+		return q.queryAllProviders(ctx, logger, q.forecastProviders(), query)
+	}
+}
+
+// forecastProviders returns the providers that implement [ForecastProvider], in order of
+// query preference.
+func (q *Queryer) forecastProviders() []Provider {
+	var providers []Provider
+
+	for _, provider := range q.providers {
+		if _, ok := provider.(ForecastProvider); ok {
+			providers = append(providers, provider)
+		}
+	}
+
+	return providers
+}
@@ -0,0 +1,145 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestQueryerReadForecastResult(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+	forecastCacheTTL := time.Minute
+
+	goodResult := &ForecastResult{
+		Forecast: &weather.Forecast{
+			Daily: []weather.ForecastEntry{{Summary: weather.Summary{Temperature: 123.456}}},
+		},
+		CreatedAt: clock.now,
+		Expiry:    clock.now.Add(forecastCacheTTL),
+	}
+
+	goodForecastProvider := &ForecastProviderMock{
+		GetWeatherForecastFunc: func(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+			return goodResult.Forecast, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodForecastProvider"
+		},
+	}
+
+	errForecastProvider := &ForecastProviderMock{
+		GetWeatherForecastFunc: func(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+			return nil, errors.New("intentional test error")
+		},
+		ProviderNameFunc: func() string {
+			return "errForecastProvider"
+		},
+	}
+
+	// Does not implement ForecastProvider, so should never be queried for forecasts.
+	weatherOnlyProvider := &ProviderMock{
+		ProviderNameFunc: func() string {
+			return "weatherOnlyProvider"
+		},
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+	cacheWithResult := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return resultCacheEntry{result: goodResult.Forecast, createdAt: goodResult.CreatedAt}, goodResult.Expiry, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		withQueryer *Queryer
+		giveCity    string
+		giveDays    int
+		expected    *ForecastResult
+		expectedErr string
+	}{
+		{
+			name: "success",
+			withQueryer: newTestQueryer(t,
+				[]Provider{weatherOnlyProvider, errForecastProvider, goodForecastProvider},
+				emptyCache,
+				withClock(clock),
+				WithForecastCacheTTL(forecastCacheTTL),
+			),
+			giveCity: "ABC",
+			giveDays: 3,
+			expected: goodResult,
+		},
+		{
+			name:        "success_cached",
+			withQueryer: newTestQueryer(t, []Provider{goodForecastProvider}, cacheWithResult, withClock(clock)),
+			giveCity:    "ABC",
+			giveDays:    3,
+			expected:    goodResult,
+		},
+		{
+			name:        "no_forecast_providers",
+			withQueryer: newTestQueryer(t, []Provider{weatherOnlyProvider}, emptyCache, withClock(clock)),
+			giveCity:    "ABC",
+			giveDays:    3,
+			expectedErr: "providerquery: all providers unavailable: no successful provider responses",
+		},
+		{
+			name:        "days_invalid",
+			withQueryer: newTestQueryer(t, []Provider{goodForecastProvider}, emptyCache, withClock(clock)),
+			giveCity:    "ABC",
+			giveDays:    0,
+			expectedErr: "providerquery: invalid input: days must be at least 1",
+		},
+		{
+			name:        "city_missing",
+			withQueryer: newTestQueryer(t, []Provider{goodForecastProvider}, emptyCache, withClock(clock)),
+			giveCity:    "",
+			giveDays:    3,
+			expectedErr: "providerquery: invalid input: city is required",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			actual, actualErr := tc.withQueryer.ReadForecastResult(ctx, tc.giveCity, tc.giveDays)
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, actualErr, tc.expectedErr)
+			} else {
+				assert.NoError(t, actualErr)
+			}
+		})
+	}
+}
+
+func TestResultCacheKeyString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "city:sydney", resultCacheKey{city: "sydney"}.String())
+	assert.Equal(t, "coordinates:-33.87,151.21", resultCacheKey{coordinates: "-33.87,151.21"}.String())
+	assert.Equal(t, "forecast:3:city:sydney", resultCacheKey{city: "sydney", forecastDays: 3}.String())
+}
@@ -0,0 +1,327 @@
+package providerquery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+const (
+	// breakerTransitionCountName is the name of the metric used to record the number
+	// of provider circuit breaker state transitions.
+	breakerTransitionCountName = "provider_circuit_breaker_transition_count"
+
+	// breakerTransitionCountDesc is the description of the metric used to record the
+	// number of provider circuit breaker state transitions.
+	breakerTransitionCountDesc = "The number of provider circuit breaker state transitions."
+
+	// breakerStateName is the name of the metric used to record the state of provider
+	// circuit breakers.
+	breakerStateName = "provider_circuit_breaker_state"
+
+	// breakerStateDesc is the description of the metric used to record the state of
+	// provider circuit breakers.
+	breakerStateDesc = "The state of provider circuit breakers (0 closed, 1 half-open, 2 open)."
+
+	// providerRequestDurationName is the name of the metric used to record the duration of
+	// provider requests.
+	providerRequestDurationName = "provider_request_duration_seconds"
+
+	// providerRequestDurationDesc is the description of the metric used to record the
+	// duration of provider requests.
+	providerRequestDurationDesc = "The duration of provider requests in seconds."
+
+	// providerFailoverCountName is the name of the metric used to record the number of
+	// failovers from one provider to the next.
+	providerFailoverCountName = "provider_failover_count"
+
+	// providerFailoverCountDesc is the description of the metric used to record the number
+	// of failovers from one provider to the next.
+	providerFailoverCountDesc = "The number of times a provider failed & the next provider was queried instead."
+
+	// staleServedCountName is the name of the metric used to record the number of stale
+	// results served.
+	staleServedCountName = "result_stale_served_count"
+
+	// staleServedCountDesc is the description of the metric used to record the number of
+	// stale results served.
+	staleServedCountDesc = "The number of results served stale, because a new result could not be loaded."
+
+	// cacheHitCountName is the name of the metric used to record the number of result cache
+	// hits.
+	cacheHitCountName = "result_cache_hit_count"
+
+	// cacheHitCountDesc is the description of the metric used to record the number of
+	// result cache hits.
+	cacheHitCountDesc = "The number of result cache gets that found a result."
+
+	// cacheMissCountName is the name of the metric used to record the number of result
+	// cache misses.
+	cacheMissCountName = "result_cache_miss_count"
+
+	// cacheMissCountDesc is the description of the metric used to record the number of
+	// result cache misses.
+	cacheMissCountDesc = "The number of result cache gets that found no result."
+
+	// cacheErrorCountName is the name of the metric used to record the number of result
+	// cache errors.
+	cacheErrorCountName = "result_cache_error_count"
+
+	// cacheErrorCountDesc is the description of the metric used to record the number of
+	// result cache errors.
+	cacheErrorCountDesc = "The number of result cache gets & sets that failed."
+
+	// sharedLoadCountName is the name of the metric used to record the number of result
+	// loads shared with a concurrent load.
+	sharedLoadCountName = "result_load_shared_count"
+
+	// sharedLoadCountDesc is the description of the metric used to record the number of
+	// result loads shared with a concurrent load.
+	sharedLoadCountDesc = "The number of result loads that shared the providers queried by a concurrent load of the same result."
+
+	// resultAgeName is the name of the metric used to record the age of results served.
+	resultAgeName = "result_age_seconds"
+
+	// resultAgeDesc is the description of the metric used to record the age of results
+	// served.
+	resultAgeDesc = "The age of results served in seconds, being the time since they were fetched from providers."
+
+	// providerAttributeKey will be the key used to attach to metrics the name of the
+	// provider.
+	providerAttributeKey = "provider"
+
+	// outcomeAttributeKey will be the key used to attach to metrics the outcome of a
+	// provider request.
+	outcomeAttributeKey = "outcome"
+
+	// operationAttributeKey will be the key used to attach to metrics the cache operation
+	// ("get" or "set") that failed.
+	operationAttributeKey = "operation"
+
+	// fromStateAttributeKey will be the key used to attach to metrics the state a
+	// circuit breaker transitioned from.
+	fromStateAttributeKey = "from"
+
+	// toStateAttributeKey will be the key used to attach to metrics the state a
+	// circuit breaker transitioned to.
+	toStateAttributeKey = "to"
+)
+
+// Metrics captures otel metrics for a [Queryer], see [WithMetrics]. Metrics include:
+//   - provider_circuit_breaker_transition_count
+//   - provider_circuit_breaker_state
+//   - provider_request_duration_seconds
+//   - provider_failover_count
+//   - result_stale_served_count
+//   - result_cache_hit_count
+//   - result_cache_miss_count
+//   - result_cache_error_count
+//   - result_load_shared_count
+//   - result_age_seconds
+type Metrics struct {
+	breakerTransitionCount  syncint64.Counter
+	breakerStateGauge       asyncint64.Gauge
+	providerRequestDuration syncfloat64.Histogram
+	providerFailoverCount   syncint64.Counter
+	staleServedCount        syncint64.Counter
+	cacheHitCount           syncint64.Counter
+	cacheMissCount          syncint64.Counter
+	cacheErrorCount         syncint64.Counter
+	sharedLoadCount         syncint64.Counter
+	resultAge               syncfloat64.Histogram
+
+	mu       sync.RWMutex
+	breakers []*circuitBreaker // Breakers observed for provider_circuit_breaker_state.
+}
+
+// NewMetrics creates the instruments used to capture [Queryer] metrics with meter.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	breakerTransitionCount, err := meter.SyncInt64().Counter(
+		breakerTransitionCountName,
+		instrument.WithDescription(breakerTransitionCountDesc),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", breakerTransitionCountName, err)
+	}
+
+	breakerStateGauge, err := meter.AsyncInt64().Gauge(
+		breakerStateName,
+		instrument.WithDescription(breakerStateDesc),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", breakerStateName, err)
+	}
+
+	providerRequestDuration, err := meter.SyncFloat64().Histogram(
+		providerRequestDurationName,
+		instrument.WithDescription(providerRequestDurationDesc),
+		instrument.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", providerRequestDurationName, err)
+	}
+
+	resultAge, err := meter.SyncFloat64().Histogram(
+		resultAgeName,
+		instrument.WithDescription(resultAgeDesc),
+		instrument.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create %s metric: %w", resultAgeName, err)
+	}
+
+	counters := make(map[string]syncint64.Counter)
+
+	for name, desc := range map[string]string{
+		providerFailoverCountName: providerFailoverCountDesc,
+		staleServedCountName:      staleServedCountDesc,
+		cacheHitCountName:         cacheHitCountDesc,
+		cacheMissCountName:        cacheMissCountDesc,
+		cacheErrorCountName:       cacheErrorCountDesc,
+		sharedLoadCountName:       sharedLoadCountDesc,
+	} {
+		counter, err := meter.SyncInt64().Counter(name, instrument.WithDescription(desc))
+		if err != nil {
+			return nil, fmt.Errorf("create %s metric: %w", name, err)
+		}
+
+		counters[name] = counter
+	}
+
+	m := &Metrics{
+		breakerTransitionCount:  breakerTransitionCount,
+		breakerStateGauge:       breakerStateGauge,
+		providerRequestDuration: providerRequestDuration,
+		providerFailoverCount:   counters[providerFailoverCountName],
+		staleServedCount:        counters[staleServedCountName],
+		cacheHitCount:           counters[cacheHitCountName],
+		cacheMissCount:          counters[cacheMissCountName],
+		cacheErrorCount:         counters[cacheErrorCountName],
+		sharedLoadCount:         counters[sharedLoadCountName],
+		resultAge:               resultAge,
+	}
+
+	err = meter.RegisterCallback([]instrument.Asynchronous{breakerStateGauge}, func(ctx context.Context) {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		for _, breaker := range m.breakers {
+			breakerStateGauge.Observe(ctx, int64(breaker.currentState()), attribute.String(providerAttributeKey, breaker.provider))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register callback for %s metric: %w", breakerStateName, err)
+	}
+
+	return m, nil
+}
+
+// observeBreakers adds breakers to those observed for provider_circuit_breaker_state.
+func (m *Metrics) observeBreakers(breakers map[string]*circuitBreaker) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, breaker := range breakers {
+		m.breakers = append(m.breakers, breaker)
+	}
+}
+
+// recordBreakerTransition records a circuit breaker for provider transitioning from one
+// state to another.
+func (m *Metrics) recordBreakerTransition(provider string, from, to breakerState) {
+	if m == nil {
+		return
+	}
+
+	m.breakerTransitionCount.Add(
+		context.Background(),
+		1,
+		attribute.String(providerAttributeKey, provider),
+		attribute.String(fromStateAttributeKey, from.String()),
+		attribute.String(toStateAttributeKey, to.String()),
+	)
+}
+
+// recordProviderRequest records a request to provider that took duration, with outcome.
+func (m *Metrics) recordProviderRequest(ctx context.Context, provider string, outcome breakerOutcome, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.providerRequestDuration.Record(
+		ctx,
+		duration.Seconds(),
+		attribute.String(providerAttributeKey, provider),
+		attribute.String(outcomeAttributeKey, outcome.String()),
+	)
+}
+
+// recordFailover records provider failing & the next provider being queried instead.
+func (m *Metrics) recordFailover(ctx context.Context, provider string) {
+	if m == nil {
+		return
+	}
+
+	m.providerFailoverCount.Add(ctx, 1, attribute.String(providerAttributeKey, provider))
+}
+
+// recordStaleServed records a result being served stale.
+func (m *Metrics) recordStaleServed(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.staleServedCount.Add(ctx, 1)
+}
+
+// recordCacheGet records a result cache get that found a result (if hit).
+func (m *Metrics) recordCacheGet(ctx context.Context, hit bool) {
+	if m == nil {
+		return
+	}
+
+	if hit {
+		m.cacheHitCount.Add(ctx, 1)
+	} else {
+		m.cacheMissCount.Add(ctx, 1)
+	}
+}
+
+// recordCacheError records a result cache operation ("get" or "set") failing.
+func (m *Metrics) recordCacheError(ctx context.Context, operation string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheErrorCount.Add(ctx, 1, attribute.String(operationAttributeKey, operation))
+}
+
+// recordSharedLoad records a result load shared with a concurrent load of the same result.
+func (m *Metrics) recordSharedLoad(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.sharedLoadCount.Add(ctx, 1)
+}
+
+// recordResultAge records the age of a result served.
+func (m *Metrics) recordResultAge(ctx context.Context, age time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.resultAge.Record(ctx, age.Seconds())
+}
@@ -0,0 +1,274 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	selector "go.opentelemetry.io/otel/sdk/metric/selector/simple"
+
+	"github.com/byatesrae/weather"
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+)
+
+func TestMetricsBreaker(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	metricController := controller.New(
+		processor.NewFactory(
+			selector.NewWithInexpensiveDistribution(),
+			aggregation.CumulativeTemporalitySelector(),
+			processor.WithMemory(true),
+		),
+		controller.WithCollectPeriod(0),
+	)
+
+	metrics, err := NewMetrics(metricController.Meter("Test123"))
+	require.NoError(t, err, "create metrics")
+
+	breaker := newCircuitBreaker("provider", &NewOptions{
+		clock:                   &manualClock{},
+		metrics:                 metrics,
+		breakerFailureThreshold: 1,
+		breakerCoolDown:         time.Minute,
+		breakerHalfOpenProbes:   1,
+	})
+	metrics.observeBreakers(map[string]*circuitBreaker{"provider": breaker})
+
+	// Do
+	done, allowed := breaker.allow(nooplogr.New())
+	require.True(t, allowed, "allowed")
+
+	done(breakerFailure)
+
+	// Assert
+	require.NoError(t, metricController.Collect(context.Background()), "collect metrics")
+
+	assert.Equal(
+		t,
+		map[string]int64{
+			`provider_circuit_breaker_transition_count{from=closed,provider=provider,to=open}`: 1,
+			`provider_circuit_breaker_state{provider=provider}`:                                int64(breakerOpen),
+		},
+		collectInt64Records(t, metricController),
+	)
+}
+
+func TestMetricsQueryer(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	metricController := controller.New(
+		processor.NewFactory(
+			selector.NewWithHistogramDistribution(),
+			aggregation.CumulativeTemporalitySelector(),
+			processor.WithMemory(true),
+		),
+		controller.WithCollectPeriod(0),
+	)
+
+	metrics, err := NewMetrics(metricController.Meter("Test123"))
+	require.NoError(t, err, "create metrics")
+
+	errProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, errors.New("Test123")
+		},
+		ProviderNameFunc: func() string {
+			return "errProvider"
+		},
+	}
+
+	goodProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 10}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	cache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			if key.(resultCacheKey).city == "sydney" {
+				return nil, time.Time{}, errors.New("Test456")
+			}
+
+			entry := resultCacheEntry{result: &weather.Summary{}, provider: "goodProvider", createdAt: clock.now.Add(-time.Minute)}
+
+			return entry, clock.now.Add(-time.Second), nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return errors.New("Test789")
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{errProvider, goodProvider},
+		cache,
+		withClock(clock),
+		WithMetrics(metrics),
+		WithBreakerFailureThreshold(0),
+	)
+
+	// Do
+	_, err = queryer.ReadWeatherResult(context.Background(), "Sydney")
+	require.NoError(t, err, "read Sydney")
+
+	// A stale result is served once neither provider succeeds.
+	goodProvider.GetWeatherSummaryFunc = errProvider.GetWeatherSummaryFunc
+
+	_, err = queryer.ReadWeatherResult(context.Background(), "Melbourne")
+	require.NoError(t, err, "read Melbourne")
+
+	// Assert
+	assert.Eventually(t, func() bool { return len(cache.SetCalls()) == 1 }, time.Second, time.Millisecond*10)
+
+	require.NoError(t, metricController.Collect(context.Background()), "collect metrics")
+
+	assert.Equal(
+		t,
+		map[string]int64{
+			`provider_circuit_breaker_state{provider=errProvider}`:                     int64(breakerClosed),
+			`provider_circuit_breaker_state{provider=goodProvider}`:                    int64(breakerClosed),
+			`provider_request_duration_seconds{outcome=failure,provider=errProvider}`:  2,
+			`provider_request_duration_seconds{outcome=success,provider=goodProvider}`: 1,
+			`provider_request_duration_seconds{outcome=failure,provider=goodProvider}`: 1,
+			`provider_failover_count{provider=errProvider}`:                            2,
+			`result_stale_served_count{}`:                                              1,
+			`result_cache_hit_count{}`:                                                 1,
+			`result_cache_miss_count{}`:                                                1,
+			`result_cache_error_count{operation=get}`:                                  1,
+			`result_cache_error_count{operation=set}`:                                  1,
+			`result_age_seconds{}`:                                                     2,
+		},
+		collectInt64Records(t, metricController),
+	)
+}
+
+func TestMetricsSharedLoad(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	metricController := controller.New(
+		processor.NewFactory(
+			selector.NewWithInexpensiveDistribution(),
+			aggregation.CumulativeTemporalitySelector(),
+			processor.WithMemory(true),
+		),
+		controller.WithCollectPeriod(0),
+	)
+
+	metrics, err := NewMetrics(metricController.Meter("Test123"))
+	require.NoError(t, err, "create metrics")
+
+	release := make(chan struct{})
+
+	provider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			<-release
+
+			return &weather.Summary{Temperature: 10}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "provider"
+		},
+	}
+
+	cache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t, []Provider{provider}, cache, WithMetrics(metrics))
+
+	// Do
+	const readers = 3
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := queryer.ReadWeatherResult(context.Background(), "Sydney")
+			assert.NoError(t, err, "read Sydney")
+		}()
+	}
+
+	// Every reader has missed the cache & (shortly after) joined the load before it completes.
+	assert.Eventually(t, func() bool { return len(cache.GetCalls()) == readers }, time.Second, time.Millisecond*10)
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+
+	wg.Wait()
+
+	// Assert
+	require.Len(t, provider.GetWeatherSummaryCalls(), 1, "provider calls")
+
+	require.NoError(t, metricController.Collect(context.Background()), "collect metrics")
+
+	assert.Equal(t, int64(readers-1), collectInt64Records(t, metricController)[`result_load_shared_count{}`])
+}
+
+// collectInt64Records returns the value of each int64 sum or last value record in
+// metricController, keyed by the record's name & attributes.
+func collectInt64Records(t *testing.T, metricController *controller.Controller) map[string]int64 {
+	t.Helper()
+
+	records := make(map[string]int64)
+
+	err := metricController.ForEach(func(_ instrumentation.Library, exportReader export.Reader) error {
+		return exportReader.ForEach(
+			aggregation.CumulativeTemporalitySelector(),
+			func(record export.Record) error {
+				attributes := record.Attributes()
+				key := record.Descriptor().Name() + "{" + attributes.Encoded(attribute.DefaultEncoder()) + "}"
+
+				switch v := record.Aggregation().(type) {
+				case aggregation.Histogram: // The number of values recorded.
+					count, err := v.Count()
+					require.NoError(t, err, "get count")
+
+					records[key] = int64(count)
+				case aggregation.Sum:
+					num, err := v.Sum()
+					require.NoError(t, err, "get sum")
+
+					records[key] = num.AsInt64()
+				case aggregation.LastValue:
+					num, _, err := v.LastValue()
+					require.NoError(t, err, "get last value")
+
+					records[key] = num.AsInt64()
+				default:
+					t.Fatalf("unsupported aggregator: %s", record.Aggregation().Kind())
+				}
+
+				return nil
+			},
+		)
+	})
+	require.NoError(t, err, "iterate metric records")
+
+	return records
+}
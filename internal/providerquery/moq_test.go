@@ -23,6 +23,9 @@ var _ Provider = &ProviderMock{}
 //			GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
 //				panic("mock out the GetWeatherSummary method")
 //			},
+//			GetWeatherSummaryByCoordinatesFunc: func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+//				panic("mock out the GetWeatherSummaryByCoordinates method")
+//			},
 //			ProviderNameFunc: func() string {
 //				panic("mock out the ProviderName method")
 //			},
@@ -36,6 +39,9 @@ type ProviderMock struct {
 	// GetWeatherSummaryFunc mocks the GetWeatherSummary method.
 	GetWeatherSummaryFunc func(ctx context.Context, cityName string) (*weather.Summary, error)
 
+	// GetWeatherSummaryByCoordinatesFunc mocks the GetWeatherSummaryByCoordinates method.
+	GetWeatherSummaryByCoordinatesFunc func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error)
+
 	// ProviderNameFunc mocks the ProviderName method.
 	ProviderNameFunc func() string
 
@@ -48,12 +54,20 @@ type ProviderMock struct {
 			// CityName is the cityName argument value.
 			CityName string
 		}
+		// GetWeatherSummaryByCoordinates holds details about calls to the GetWeatherSummaryByCoordinates method.
+		GetWeatherSummaryByCoordinates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Coordinates is the coordinates argument value.
+			Coordinates Coordinates
+		}
 		// ProviderName holds details about calls to the ProviderName method.
 		ProviderName []struct {
 		}
 	}
-	lockGetWeatherSummary sync.RWMutex
-	lockProviderName      sync.RWMutex
+	lockGetWeatherSummary              sync.RWMutex
+	lockGetWeatherSummaryByCoordinates sync.RWMutex
+	lockProviderName                   sync.RWMutex
 }
 
 // GetWeatherSummary calls GetWeatherSummaryFunc.
@@ -92,6 +106,42 @@ func (mock *ProviderMock) GetWeatherSummaryCalls() []struct {
 	return calls
 }
 
+// GetWeatherSummaryByCoordinates calls GetWeatherSummaryByCoordinatesFunc.
+func (mock *ProviderMock) GetWeatherSummaryByCoordinates(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+	if mock.GetWeatherSummaryByCoordinatesFunc == nil {
+		panic("ProviderMock.GetWeatherSummaryByCoordinatesFunc: method is nil but Provider.GetWeatherSummaryByCoordinates was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Coordinates Coordinates
+	}{
+		Ctx:         ctx,
+		Coordinates: coordinates,
+	}
+	mock.lockGetWeatherSummaryByCoordinates.Lock()
+	mock.calls.GetWeatherSummaryByCoordinates = append(mock.calls.GetWeatherSummaryByCoordinates, callInfo)
+	mock.lockGetWeatherSummaryByCoordinates.Unlock()
+	return mock.GetWeatherSummaryByCoordinatesFunc(ctx, coordinates)
+}
+
+// GetWeatherSummaryByCoordinatesCalls gets all the calls that were made to GetWeatherSummaryByCoordinates.
+// Check the length with:
+//
+//	len(mockedProvider.GetWeatherSummaryByCoordinatesCalls())
+func (mock *ProviderMock) GetWeatherSummaryByCoordinatesCalls() []struct {
+	Ctx         context.Context
+	Coordinates Coordinates
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Coordinates Coordinates
+	}
+	mock.lockGetWeatherSummaryByCoordinates.RLock()
+	calls = mock.calls.GetWeatherSummaryByCoordinates
+	mock.lockGetWeatherSummaryByCoordinates.RUnlock()
+	return calls
+}
+
 // ProviderName calls ProviderNameFunc.
 func (mock *ProviderMock) ProviderName() string {
 	if mock.ProviderNameFunc == nil {
@@ -119,6 +169,221 @@ func (mock *ProviderMock) ProviderNameCalls() []struct {
 	return calls
 }
 
+// Ensure, that ForecastProviderMock does implement ForecastProvider.
+// If this is not the case, regenerate this file with moq.
+var _ ForecastProvider = &ForecastProviderMock{}
+
+// ForecastProviderMock is a mock implementation of ForecastProvider.
+//
+//	func TestSomethingThatUsesForecastProvider(t *testing.T) {
+//
+//		// make and configure a mocked ForecastProvider
+//		mockedForecastProvider := &ForecastProviderMock{
+//			GetWeatherForecastFunc: func(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+//				panic("mock out the GetWeatherForecast method")
+//			},
+//			GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+//				panic("mock out the GetWeatherSummary method")
+//			},
+//			GetWeatherSummaryByCoordinatesFunc: func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+//				panic("mock out the GetWeatherSummaryByCoordinates method")
+//			},
+//			ProviderNameFunc: func() string {
+//				panic("mock out the ProviderName method")
+//			},
+//		}
+//
+//		// use mockedForecastProvider in code that requires ForecastProvider
+//		// and then make assertions.
+//
+//	}
+type ForecastProviderMock struct {
+	// GetWeatherForecastFunc mocks the GetWeatherForecast method.
+	GetWeatherForecastFunc func(ctx context.Context, cityName string, days int) (*weather.Forecast, error)
+
+	// GetWeatherSummaryFunc mocks the GetWeatherSummary method.
+	GetWeatherSummaryFunc func(ctx context.Context, cityName string) (*weather.Summary, error)
+
+	// GetWeatherSummaryByCoordinatesFunc mocks the GetWeatherSummaryByCoordinates method.
+	GetWeatherSummaryByCoordinatesFunc func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error)
+
+	// ProviderNameFunc mocks the ProviderName method.
+	ProviderNameFunc func() string
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetWeatherForecast holds details about calls to the GetWeatherForecast method.
+		GetWeatherForecast []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CityName is the cityName argument value.
+			CityName string
+			// Days is the days argument value.
+			Days int
+		}
+		// GetWeatherSummary holds details about calls to the GetWeatherSummary method.
+		GetWeatherSummary []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CityName is the cityName argument value.
+			CityName string
+		}
+		// GetWeatherSummaryByCoordinates holds details about calls to the GetWeatherSummaryByCoordinates method.
+		GetWeatherSummaryByCoordinates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Coordinates is the coordinates argument value.
+			Coordinates Coordinates
+		}
+		// ProviderName holds details about calls to the ProviderName method.
+		ProviderName []struct {
+		}
+	}
+	lockGetWeatherForecast             sync.RWMutex
+	lockGetWeatherSummary              sync.RWMutex
+	lockGetWeatherSummaryByCoordinates sync.RWMutex
+	lockProviderName                   sync.RWMutex
+}
+
+// GetWeatherForecast calls GetWeatherForecastFunc.
+func (mock *ForecastProviderMock) GetWeatherForecast(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+	if mock.GetWeatherForecastFunc == nil {
+		panic("ForecastProviderMock.GetWeatherForecastFunc: method is nil but ForecastProvider.GetWeatherForecast was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		CityName string
+		Days     int
+	}{
+		Ctx:      ctx,
+		CityName: cityName,
+		Days:     days,
+	}
+	mock.lockGetWeatherForecast.Lock()
+	mock.calls.GetWeatherForecast = append(mock.calls.GetWeatherForecast, callInfo)
+	mock.lockGetWeatherForecast.Unlock()
+	return mock.GetWeatherForecastFunc(ctx, cityName, days)
+}
+
+// GetWeatherForecastCalls gets all the calls that were made to GetWeatherForecast.
+// Check the length with:
+//
+//	len(mockedForecastProvider.GetWeatherForecastCalls())
+func (mock *ForecastProviderMock) GetWeatherForecastCalls() []struct {
+	Ctx      context.Context
+	CityName string
+	Days     int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		CityName string
+		Days     int
+	}
+	mock.lockGetWeatherForecast.RLock()
+	calls = mock.calls.GetWeatherForecast
+	mock.lockGetWeatherForecast.RUnlock()
+	return calls
+}
+
+// GetWeatherSummary calls GetWeatherSummaryFunc.
+func (mock *ForecastProviderMock) GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error) {
+	if mock.GetWeatherSummaryFunc == nil {
+		panic("ForecastProviderMock.GetWeatherSummaryFunc: method is nil but ForecastProvider.GetWeatherSummary was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		CityName string
+	}{
+		Ctx:      ctx,
+		CityName: cityName,
+	}
+	mock.lockGetWeatherSummary.Lock()
+	mock.calls.GetWeatherSummary = append(mock.calls.GetWeatherSummary, callInfo)
+	mock.lockGetWeatherSummary.Unlock()
+	return mock.GetWeatherSummaryFunc(ctx, cityName)
+}
+
+// GetWeatherSummaryCalls gets all the calls that were made to GetWeatherSummary.
+// Check the length with:
+//
+//	len(mockedForecastProvider.GetWeatherSummaryCalls())
+func (mock *ForecastProviderMock) GetWeatherSummaryCalls() []struct {
+	Ctx      context.Context
+	CityName string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		CityName string
+	}
+	mock.lockGetWeatherSummary.RLock()
+	calls = mock.calls.GetWeatherSummary
+	mock.lockGetWeatherSummary.RUnlock()
+	return calls
+}
+
+// GetWeatherSummaryByCoordinates calls GetWeatherSummaryByCoordinatesFunc.
+func (mock *ForecastProviderMock) GetWeatherSummaryByCoordinates(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+	if mock.GetWeatherSummaryByCoordinatesFunc == nil {
+		panic("ForecastProviderMock.GetWeatherSummaryByCoordinatesFunc: method is nil but ForecastProvider.GetWeatherSummaryByCoordinates was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Coordinates Coordinates
+	}{
+		Ctx:         ctx,
+		Coordinates: coordinates,
+	}
+	mock.lockGetWeatherSummaryByCoordinates.Lock()
+	mock.calls.GetWeatherSummaryByCoordinates = append(mock.calls.GetWeatherSummaryByCoordinates, callInfo)
+	mock.lockGetWeatherSummaryByCoordinates.Unlock()
+	return mock.GetWeatherSummaryByCoordinatesFunc(ctx, coordinates)
+}
+
+// GetWeatherSummaryByCoordinatesCalls gets all the calls that were made to GetWeatherSummaryByCoordinates.
+// Check the length with:
+//
+//	len(mockedForecastProvider.GetWeatherSummaryByCoordinatesCalls())
+func (mock *ForecastProviderMock) GetWeatherSummaryByCoordinatesCalls() []struct {
+	Ctx         context.Context
+	Coordinates Coordinates
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Coordinates Coordinates
+	}
+	mock.lockGetWeatherSummaryByCoordinates.RLock()
+	calls = mock.calls.GetWeatherSummaryByCoordinates
+	mock.lockGetWeatherSummaryByCoordinates.RUnlock()
+	return calls
+}
+
+// ProviderName calls ProviderNameFunc.
+func (mock *ForecastProviderMock) ProviderName() string {
+	if mock.ProviderNameFunc == nil {
+		panic("ForecastProviderMock.ProviderNameFunc: method is nil but ForecastProvider.ProviderName was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockProviderName.Lock()
+	mock.calls.ProviderName = append(mock.calls.ProviderName, callInfo)
+	mock.lockProviderName.Unlock()
+	return mock.ProviderNameFunc()
+}
+
+// ProviderNameCalls gets all the calls that were made to ProviderName.
+// Check the length with:
+//
+//	len(mockedForecastProvider.ProviderNameCalls())
+func (mock *ForecastProviderMock) ProviderNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockProviderName.RLock()
+	calls = mock.calls.ProviderName
+	mock.lockProviderName.RUnlock()
+	return calls
+}
+
 // Ensure, that CacheMock does implement Cache.
 // If this is not the case, regenerate this file with moq.
 var _ Cache = &CacheMock{}
@@ -0,0 +1,213 @@
+package providerquery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsSmoothingFactor is the weight given to the latest observation when updating the
+// moving averages in [ProviderStats]. Higher values forget older observations faster.
+const statsSmoothingFactor = 0.2
+
+// ProviderStats are recent statistics for a provider.
+type ProviderStats struct {
+	Provider    string        // The name of the provider, see [Provider.ProviderName].
+	Queries     int           // The number of queries observed (excluding abandoned queries).
+	ErrorRate   float64       // Moving average of failed queries, in the range [0, 1].
+	Latency     time.Duration // Moving average of the latency of successful queries.
+	LastSuccess time.Time     // When the provider last responded successfully (zero if never).
+}
+
+// ProviderScore is a provider's statistics and the score assigned to it by an
+// [OrderingStrategy].
+type ProviderScore struct {
+	ProviderStats
+	Score float64
+}
+
+// ProviderScores are the providers in the order they will next be queried.
+type ProviderScores struct {
+	Strategy  string // The name of the [OrderingStrategy].
+	Providers []ProviderScore
+}
+
+// OrderingStrategy decides the order in which providers are queried.
+type OrderingStrategy interface {
+	// Name returns the name of the strategy.
+	Name() string
+
+	// Score returns a score for each provider in stats (in the same order). Providers are
+	// queried in ascending order of score, ties being queried in the order configured.
+	Score(stats []ProviderStats) []float64
+}
+
+// staticOrdering queries providers in the order configured.
+type staticOrdering struct{}
+
+// NewStaticOrdering creates an [OrderingStrategy] that always queries providers in the
+// order configured.
+func NewStaticOrdering() OrderingStrategy {
+	return staticOrdering{}
+}
+
+// Name returns "static".
+func (staticOrdering) Name() string {
+	return "static"
+}
+
+// Score scores every provider 0.
+func (staticOrdering) Score(stats []ProviderStats) []float64 {
+	return make([]float64, len(stats))
+}
+
+// stickyOrdering queries the last successful provider first.
+type stickyOrdering struct{}
+
+// NewStickyOrdering creates an [OrderingStrategy] that queries the provider that most
+// recently responded successfully first, followed by the rest in the order configured.
+func NewStickyOrdering() OrderingStrategy {
+	return stickyOrdering{}
+}
+
+// Name returns "sticky".
+func (stickyOrdering) Name() string {
+	return "sticky"
+}
+
+// Score scores the provider that most recently responded successfully 0, the rest 1.
+func (stickyOrdering) Score(stats []ProviderStats) []float64 {
+	scores := make([]float64, len(stats))
+
+	last := -1
+	for i, s := range stats {
+		scores[i] = 1
+
+		if !s.LastSuccess.IsZero() && (last < 0 || s.LastSuccess.After(stats[last].LastSuccess)) {
+			last = i
+		}
+	}
+
+	if last >= 0 {
+		scores[last] = 0
+	}
+
+	return scores
+}
+
+// scoreOrdering queries providers with the lowest expected cost first.
+type scoreOrdering struct {
+	errorPenalty time.Duration
+}
+
+// NewScoreOrdering creates an [OrderingStrategy] that queries providers in order of
+// expected cost: latency + error rate * errorPenalty (in seconds). A reasonable
+// errorPenalty is the provider timeout, being roughly what a failed query costs.
+// Providers that have not yet been queried score 0, such that they're tried early.
+func NewScoreOrdering(errorPenalty time.Duration) OrderingStrategy {
+	return scoreOrdering{errorPenalty: errorPenalty}
+}
+
+// Name returns "score".
+func (scoreOrdering) Name() string {
+	return "score"
+}
+
+// Score scores each provider by expected cost.
+func (o scoreOrdering) Score(stats []ProviderStats) []float64 {
+	scores := make([]float64, len(stats))
+
+	for i, s := range stats {
+		scores[i] = s.Latency.Seconds() + s.ErrorRate*o.errorPenalty.Seconds()
+	}
+
+	return scores
+}
+
+// providerStatsTracker tracks [ProviderStats] per provider, safe for use across goroutines.
+type providerStatsTracker struct {
+	mu    sync.RWMutex
+	stats map[string]ProviderStats
+}
+
+func newProviderStatsTracker() *providerStatsTracker {
+	return &providerStatsTracker{stats: make(map[string]ProviderStats)}
+}
+
+// observe records the outcome of a query to provider that took latency, completing at now.
+func (t *providerStatsTracker) observe(provider string, outcome breakerOutcome, latency time.Duration, now time.Time) {
+	if outcome == breakerAbandoned {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stats[provider]
+	s.Provider = provider
+
+	failed := 0.0
+	if outcome == breakerFailure || outcome == breakerRejected {
+		failed = 1
+	}
+
+	if s.Queries == 0 {
+		s.ErrorRate = failed
+	} else {
+		s.ErrorRate += statsSmoothingFactor * (failed - s.ErrorRate)
+	}
+
+	if outcome == breakerSuccess {
+		if s.LastSuccess.IsZero() {
+			s.Latency = latency
+		} else {
+			s.Latency += time.Duration(statsSmoothingFactor * float64(latency-s.Latency))
+		}
+
+		s.LastSuccess = now
+	}
+
+	s.Queries++
+
+	t.stats[provider] = s
+}
+
+// get returns the stats for each of providers.
+func (t *providerStatsTracker) get(providers []Provider) []ProviderStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := make([]ProviderStats, len(providers))
+	for i, provider := range providers {
+		stats[i] = t.stats[provider.ProviderName()]
+		stats[i].Provider = provider.ProviderName()
+	}
+
+	return stats
+}
+
+// orderProviders orders providers using strategy, returning the ordered providers along
+// with their scores.
+func orderProviders(strategy OrderingStrategy, stats *providerStatsTracker, providers []Provider) ([]Provider, []ProviderScore) {
+	providerStats := stats.get(providers)
+	scores := strategy.Score(providerStats)
+
+	indexes := make([]int, len(providers))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return scores[indexes[a]] < scores[indexes[b]]
+	})
+
+	orderedProviders := make([]Provider, len(providers))
+	orderedScores := make([]ProviderScore, len(providers))
+
+	for i, index := range indexes {
+		orderedProviders[i] = providers[index]
+		orderedScores[i] = ProviderScore{ProviderStats: providerStats[index], Score: scores[index]}
+	}
+
+	return orderedProviders, orderedScores
+}
@@ -0,0 +1,162 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestOrderingStrategyScore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+
+	stats := []ProviderStats{
+		{Provider: "a", Queries: 10, ErrorRate: 0.5, Latency: time.Millisecond * 100, LastSuccess: now.Add(-time.Minute)},
+		{Provider: "b", Queries: 10, ErrorRate: 0, Latency: time.Millisecond * 800, LastSuccess: now},
+		{Provider: "c"},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		give     OrderingStrategy
+		expected []float64
+	}{
+		{
+			name:     "static",
+			give:     NewStaticOrdering(),
+			expected: []float64{0, 0, 0},
+		},
+		{
+			name:     "sticky",
+			give:     NewStickyOrdering(),
+			expected: []float64{1, 0, 1},
+		},
+		{
+			name:     "score",
+			give:     NewScoreOrdering(time.Second * 3),
+			expected: []float64{1.6, 0.8, 0},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.name, tc.give.Name())
+			assert.InDeltaSlice(t, tc.expected, tc.give.Score(stats), 1e-9)
+		})
+	}
+}
+
+func TestProviderStatsTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	provider := &ProviderMock{
+		ProviderNameFunc: func() string {
+			return "provider"
+		},
+	}
+
+	tracker := newProviderStatsTracker()
+
+	tracker.observe("provider", breakerSuccess, time.Second, now)
+	tracker.observe("provider", breakerAbandoned, time.Hour, now.Add(time.Minute))
+	tracker.observe("provider", breakerFailure, time.Hour, now.Add(time.Minute))
+	tracker.observe("provider", breakerSuccess, time.Second*2, now.Add(time.Minute*2))
+
+	assert.Equal(
+		t,
+		[]ProviderStats{{
+			Provider:    "provider",
+			Queries:     3,
+			ErrorRate:   0.16,
+			Latency:     time.Millisecond * 1200,
+			LastSuccess: now.Add(time.Minute * 2),
+		}},
+		tracker.get([]Provider{provider}),
+	)
+}
+
+func TestQueryerReadWeatherResultOrdering(t *testing.T) {
+	t.Parallel()
+
+	clock := &manualClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	firstProviderUp := true
+	firstProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			if firstProviderUp {
+				return &weather.Summary{Temperature: 1}, nil
+			}
+
+			return nil, errors.New("intentional test error")
+		},
+		ProviderNameFunc: func() string {
+			return "firstProvider"
+		},
+	}
+
+	secondProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 2}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "secondProvider"
+		},
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{firstProvider, secondProvider},
+		emptyCache,
+		withClock(clock),
+		WithOrderingStrategy(NewStickyOrdering()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// readProvider reads a weather result, returning the provider that supplied it.
+	readProvider := func() string {
+		t.Helper()
+
+		clock.Add(time.Second)
+
+		result, err := queryer.ReadWeatherResult(ctx, "Sydney")
+		require.NoError(t, err)
+
+		return result.Provider
+	}
+
+	assert.Equal(t, "firstProvider", readProvider())
+
+	firstProviderUp = false
+	assert.Equal(t, "secondProvider", readProvider())
+
+	firstProviderUp = true
+	assert.Equal(t, "secondProvider", readProvider(), "sticks to the last successful provider")
+	assert.Len(t, firstProvider.GetWeatherSummaryCalls(), 2)
+
+	scores := queryer.ProviderScores()
+	assert.Equal(t, "sticky", scores.Strategy)
+	require.Len(t, scores.Providers, 2)
+	assert.Equal(t, "secondProvider", scores.Providers[0].Provider)
+	assert.Equal(t, "firstProvider", scores.Providers[1].Provider)
+	assert.Equal(t, 0.2, scores.Providers[1].ErrorRate)
+}
@@ -13,4 +13,15 @@ type Provider interface {
 
 	// GetWeatherSummary gets a weather summary for a city.
 	GetWeatherSummary(ctx context.Context, cityName string) (*weather.Summary, error)
+
+	// GetWeatherSummaryByCoordinates gets a weather summary for a latitude/longitude.
+	GetWeatherSummaryByCoordinates(ctx context.Context, coordinates Coordinates) (*weather.Summary, error)
+}
+
+// ForecastProvider is a [Provider] that can also be queried for weather forecasts.
+type ForecastProvider interface {
+	Provider
+
+	// GetWeatherForecast gets a weather forecast covering days days for a city.
+	GetWeatherForecast(ctx context.Context, cityName string, days int) (*weather.Forecast, error)
 }
@@ -2,10 +2,15 @@ package providerquery
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 
 	"github.com/byatesrae/weather"
@@ -13,46 +18,114 @@ import (
 )
 
 const (
-	providerLogKey = "provider"
+	providerLogKey     = "provider"
+	cityLogKey         = "city"
+	coordinatesLogKey  = "coordinates"
+	forecastDaysLogKey = "forecastDays"
 )
 
-// WeatherResult contains a weather summary and timeline data.
+// WeatherResult contains a weather summary, timeline data and provenance.
 type WeatherResult struct {
-	Expiry    time.Time
-	CreatedAt time.Time
-	Weather   *weather.Summary
+	Expiry    time.Time        // When the result should no longer be served (unless stale).
+	CreatedAt time.Time        // When the result was fetched from the provider.
+	Weather   *weather.Summary // Weather.ObservedAt is when the provider observed the weather, if known.
+	Provider  string           // The name of the provider the result was fetched from, see [Provider.ProviderName].
+	Stale     bool             // True if the result is being served past Expiry, because it could not be refreshed.
+	Age       time.Duration    // The amount of time since the result was fetched from the provider.
+
+	// The summaries of each provider, if Weather was aggregated from multiple providers (see
+	// [WithAggregationMethod]). In that case Provider lists the providers aggregated.
+	Sources []WeatherSource
 }
 
-// Queryer will query a list of providers for a weather summary.
+// Queryer will query a list of providers for a weather summary or forecast.
 type Queryer struct {
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracer               trace.Tracer
+	metrics              *Metrics
 	cache                Cache
 
 	// Timeout for querying the cache.
 	cacheTimeout time.Duration
 
-	// A slice of providers to query, ordered by query preference.
+	// A slice of providers to query, ordered by query preference (see ordering).
 	providers []Provider
 
+	// Decides the order providers are queried in, based on their recent stats.
+	ordering OrderingStrategy
+	stats    *providerStatsTracker
+
+	// When the freshest result read or loaded was fetched from its provider, see Readiness.
+	freshestResult *freshestResultTracker
+
+	// How providers are queried, and (for hedged queries) how long to wait for a provider
+	// to answer before also querying the next.
+	queryMode  QueryMode
+	hedgeDelay time.Duration
+
+	// How weather summaries from multiple providers are combined, if at all.
+	aggregationMethod AggregationMethod
+	providerWeights   map[string]float64
+	outlierTolerances OutlierTolerances
+
+	// A circuit breaker per provider, keyed by provider name.
+	breakers map[string]*circuitBreaker
+
 	// Timeout for querying an individual provider.
 	providerTimeout time.Duration
 
-	// Regardless of how many times ReadWeatherResult is called, query providers once (to avoid a thundering heard).
-	queryAllProvidersForWeatherOnce singleflight.Group
+	// Regardless of how many times a result is read for a location, query providers once per location (to
+	// avoid a thundering heard).
+	queryAllProvidersOnce singleflight.Group
+
+	// How long before expiry a cached result is refreshed in the background (0 disables), and
+	// the keys (see resultCacheKey.String) currently being refreshed.
+	refreshAheadWindow time.Duration
+	refreshing         sync.Map
 
-	// TTL applied for cached provider results.
+	// The number of decimal places coordinates are rounded to before querying.
+	coordinatePrecision int
+
+	// TTL applied for cached provider weather results.
 	resultCacheTTL time.Duration
-	resultTimeout  time.Duration
+
+	// TTL applied for cached provider forecast results.
+	forecastCacheTTL time.Duration
+
+	// The maximum amount of time a result may be served past its expiry (0 is until the cache
+	// removes it).
+	maxStaleness time.Duration
 
 	// Timeout for getting a response across all providers.
+	resultTimeout time.Duration
+
 	clock Clock
 }
 
 // NewOptions are options for the New function.
 type NewOptions struct {
 	clock                Clock
+	cacheTimeout         time.Duration
+	providerTimeout      time.Duration
+	resultTimeout        time.Duration
 	resultCacheTTL       time.Duration
+	forecastCacheTTL     time.Duration
+	maxStaleness         time.Duration
+	refreshAheadWindow   time.Duration
+	coordinatePrecision  int
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracerProvider       trace.TracerProvider
+	metrics              *Metrics
+	ordering             OrderingStrategy
+	queryMode            QueryMode
+	hedgeDelay           time.Duration
+	aggregationMethod    AggregationMethod
+	providerWeights      map[string]float64
+	outlierTolerances    OutlierTolerances
+
+	breakerFailureThreshold int
+	breakerCoolDown         time.Duration
+	breakerHalfOpenProbes   int
 }
 
 // withClock sets the clock used in the New function.
@@ -62,6 +135,31 @@ func withClock(clock Clock) func(o *NewOptions) {
 	}
 }
 
+// WithCacheTimeout sets how long getting or setting a result in the cache may take. A cache
+// shared by loading results on a single replica (see [Queryer.ReadSerializedResult]) needs as
+// long as a result takes to load.
+func WithCacheTimeout(cacheTimeout time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.cacheTimeout = cacheTimeout
+	}
+}
+
+// WithProviderTimeout sets how long querying an individual provider may take, before the next
+// provider is queried. It must be greater than 0.
+func WithProviderTimeout(providerTimeout time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.providerTimeout = providerTimeout
+	}
+}
+
+// WithResultTimeout sets how long loading a result may take, across all the providers queried.
+// It must be greater than 0.
+func WithResultTimeout(resultTimeout time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.resultTimeout = resultTimeout
+	}
+}
+
 // WithResultCacheTTL sets the amount of time a result is cached for.
 func WithResultCacheTTL(resultCacheTTL time.Duration) func(o *NewOptions) {
 	return func(o *NewOptions) {
@@ -69,6 +167,42 @@ func WithResultCacheTTL(resultCacheTTL time.Duration) func(o *NewOptions) {
 	}
 }
 
+// WithForecastCacheTTL sets the amount of time a forecast result is cached for.
+func WithForecastCacheTTL(forecastCacheTTL time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.forecastCacheTTL = forecastCacheTTL
+	}
+}
+
+// WithMaxStaleness sets the maximum amount of time a cached result may be served past its
+// expiry, when a new result can't be loaded. Past that a [*TooStaleError] is returned
+// instead. A max staleness of 0 (the default) doesn't limit it, serving cached results until
+// the cache removes them (e.g. see memorycache.WithExpiryGrace).
+func WithMaxStaleness(maxStaleness time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.maxStaleness = maxStaleness
+	}
+}
+
+// WithRefreshAheadWindow sets how long before expiry a cached result is refreshed. A result
+// read within this window of its expiry is returned immediately, while a new result is loaded
+// in the background. A window of 0 (the default) disables refreshing ahead, such that a new
+// result is only loaded once the cached result has expired.
+func WithRefreshAheadWindow(refreshAheadWindow time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.refreshAheadWindow = refreshAheadWindow
+	}
+}
+
+// WithCoordinatePrecision sets the number of decimal places coordinates are rounded
+// to before querying providers. Requests for coordinates that round to the same value
+// share a cached result. For reference, 2 decimal places is roughly 1km.
+func WithCoordinatePrecision(precision int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.coordinatePrecision = precision
+	}
+}
+
 // WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
 // the context.
 func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
@@ -77,148 +211,689 @@ func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) log
 	}
 }
 
-// New creates a new [Queryer].
-func New(providers []Provider, cache Cache, overrides ...func(o *NewOptions)) *Queryer {
+// WithTracerProvider sets the provider of the tracer used to trace reading results, querying
+// the cache & each provider query.
+func WithTracerProvider(tracerProvider trace.TracerProvider) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithMetrics sets the metrics captured by the Queryer, see [NewMetrics].
+func WithMetrics(metrics *Metrics) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithOrderingStrategy sets the strategy used to decide the order providers are queried
+// in. Defaults to [NewStaticOrdering].
+func WithOrderingStrategy(ordering OrderingStrategy) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.ordering = ordering
+	}
+}
+
+// WithQueryMode sets how providers are queried when loading a new result. Defaults to
+// [QueryModeSequential].
+func WithQueryMode(queryMode QueryMode) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.queryMode = queryMode
+	}
+}
+
+// WithHedgeDelay sets how long to wait for a provider to answer before also querying the
+// next provider, when using [QueryModeHedged]. A hedge delay <= 0 never hedges.
+func WithHedgeDelay(hedgeDelay time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.hedgeDelay = hedgeDelay
+	}
+}
+
+// WithAggregationMethod sets how weather summaries are combined across providers. With an
+// aggregation method other than [AggregationMethodNone], all providers are queried
+// concurrently for weather (regardless of query mode) & their summaries combined.
+func WithAggregationMethod(aggregationMethod AggregationMethod) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.aggregationMethod = aggregationMethod
+	}
+}
+
+// WithProviderWeights sets the weight of each provider (by name) when aggregating with
+// [AggregationMethodWeightedMean]. Providers without a weight have a weight of 1.
+func WithProviderWeights(providerWeights map[string]float64) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.providerWeights = providerWeights
+	}
+}
+
+// WithOutlierTolerances sets how far a provider's summary may differ from the rest before it
+// is rejected as an outlier when aggregating.
+func WithOutlierTolerances(outlierTolerances OutlierTolerances) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.outlierTolerances = outlierTolerances
+	}
+}
+
+// WithBreakerFailureThreshold sets the number of consecutive failures after which a
+// provider's circuit breaker opens, skipping that provider until the breaker cool-down
+// (see [WithBreakerCoolDown]) has elapsed. A value <= 0 disables circuit breaking.
+func WithBreakerFailureThreshold(failureThreshold int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.breakerFailureThreshold = failureThreshold
+	}
+}
+
+// WithBreakerCoolDown sets the amount of time a provider's circuit breaker stays open
+// before becoming half-open.
+func WithBreakerCoolDown(coolDown time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.breakerCoolDown = coolDown
+	}
+}
+
+// WithBreakerHalfOpenProbes sets the maximum number of concurrent queries let through
+// to a provider while its circuit breaker is half-open.
+func WithBreakerHalfOpenProbes(probes int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.breakerHalfOpenProbes = probes
+	}
+}
+
+// New creates a new [Queryer]. Each provider must have a unique name, which its circuit breaker,
+// ordering statistics & aggregated sources are keyed by.
+func New(providers []Provider, cache Cache, overrides ...func(o *NewOptions)) (*Queryer, error) {
 	noopLogger := nooplogr.New()
 
 	options := &NewOptions{
-		clock:          standardClock{},
-		resultCacheTTL: time.Second * 3,
+		clock:               standardClock{},
+		cacheTimeout:        time.Second * 2,
+		providerTimeout:     time.Second * 3,
+		resultTimeout:       time.Second * 10,
+		resultCacheTTL:      time.Second * 3,
+		forecastCacheTTL:    time.Minute * 10,
+		coordinatePrecision: 2,
 		getLoggerFromContext: func(ctx context.Context) logr.Logger {
 			return noopLogger
 		},
+		tracerProvider:    trace.NewNoopTracerProvider(),
+		ordering:          NewStaticOrdering(),
+		queryMode:         QueryModeSequential,
+		hedgeDelay:        time.Millisecond * 500,
+		aggregationMethod: AggregationMethodNone,
+		outlierTolerances: OutlierTolerances{
+			Temperature: 5,
+			WindSpeed:   15,
+			Humidity:    20,
+			Pressure:    10,
+		},
+		breakerFailureThreshold: 5,
+		breakerCoolDown:         time.Second * 30,
+		breakerHalfOpenProbes:   1,
 	}
 
 	for _, override := range overrides {
 		override(options)
 	}
 
+	if options.providerTimeout <= 0 {
+		return nil, errors.New("providerquery: provider timeout must be greater than 0")
+	}
+
+	if options.resultTimeout <= 0 {
+		return nil, errors.New("providerquery: result timeout must be greater than 0")
+	}
+
+	breakers := make(map[string]*circuitBreaker, len(providers))
+	for _, provider := range providers {
+		if _, ok := breakers[provider.ProviderName()]; ok {
+			return nil, errors.Errorf("providerquery: duplicate provider name %q", provider.ProviderName())
+		}
+
+		breakers[provider.ProviderName()] = newCircuitBreaker(provider.ProviderName(), options)
+	}
+
+	options.metrics.observeBreakers(breakers)
+
 	return &Queryer{
 		getLoggerFromContext: options.getLoggerFromContext,
+		tracer:               options.tracerProvider.Tracer(tracerName),
+		metrics:              options.metrics,
 		cache:                cache,
-		cacheTimeout:         time.Second * 2, // These timeouts should all be configurable.
+		cacheTimeout:         options.cacheTimeout,
 		providers:            providers,
-		providerTimeout:      time.Second * 3,
+		breakers:             breakers,
+		ordering:             options.ordering,
+		queryMode:            options.queryMode,
+		hedgeDelay:           options.hedgeDelay,
+		aggregationMethod:    options.aggregationMethod,
+		providerWeights:      options.providerWeights,
+		outlierTolerances:    options.outlierTolerances,
+		stats:                newProviderStatsTracker(),
+		freshestResult:       &freshestResultTracker{},
+		providerTimeout:      options.providerTimeout,
+		coordinatePrecision:  options.coordinatePrecision,
 		resultCacheTTL:       options.resultCacheTTL,
-		resultTimeout:        time.Second * 10,
+		forecastCacheTTL:     options.forecastCacheTTL,
+		maxStaleness:         options.maxStaleness,
+		refreshAheadWindow:   options.refreshAheadWindow,
+		resultTimeout:        options.resultTimeout,
 		clock:                options.clock,
+	}, nil
+}
+
+// ProviderScores returns the providers in the order they will next be queried, along with
+// the stats & scores used to order them.
+func (q *Queryer) ProviderScores() *ProviderScores {
+	_, scores := orderProviders(q.ordering, q.stats, q.providers)
+
+	return &ProviderScores{Strategy: q.ordering.Name(), Providers: scores}
+}
+
+// ReadWeatherResult will query one or more providers for a weather result for city. The
+// result will be cached (per city, ignoring case & surrounding whitespace) and sometimes
+// served stale.
+func (q *Queryer) ReadWeatherResult(ctx context.Context, city string) (_ *WeatherResult, err error) {
+	ctx, span := q.tracer.Start(ctx, "Queryer.ReadWeatherResult", trace.WithAttributes(attribute.String(cityAttributeKey, city)))
+	defer func() { endSpan(span, err) }()
+
+	key, query, err := cityWeatherQuery(city)
+	if err != nil {
+		return nil, err
 	}
+
+	logger := q.getLoggerFromContext(ctx).WithValues(cityLogKey, key.city)
+
+	return q.readWeatherResult(ctx, logger, key, query)
 }
 
-// ReadWeatherResult will query one or more providers for a weather result. The result
-// will be cached and sometimes served stale.
-func (q *Queryer) ReadWeatherResult(ctx context.Context, city string) (*WeatherResult, error) {
-	logger := q.getLoggerFromContext(ctx)
+// cityWeatherQuery returns the cache key & provider query for the weather of city.
+func cityWeatherQuery(city string) (resultCacheKey, providerQueryFunc, error) {
+	city = strings.Join(strings.Fields(city), " ")
+	if city == "" {
+		return resultCacheKey{}, nil, fmt.Errorf("%w: city is required", ErrInvalidInput)
+	}
 
-	result := q.getCachedReadWeatherResult(ctx, logger)
+	key := resultCacheKey{city: normalizeCity(city)}
 
-	retrievedCachedResult := result != nil
+	return key, func(ctx context.Context, provider Provider) (interface{}, error) {
+		summary, err := provider.GetWeatherSummary(ctx, city)
+		if summary == nil {
+			return nil, err
+		}
 
-	if !retrievedCachedResult || q.clock.Now().After(result.Expiry) {
-		logger.V(1).Info("Querying all providers.")
+		return summary, err
+	}, nil
+}
+
+// ReadWeatherResultByCoordinates will query one or more providers for a weather result
+// for coordinates. The coordinates are rounded to the configured precision (see
+// [WithCoordinatePrecision]) before querying, such that nearby coordinates share a
+// cached result.
+func (q *Queryer) ReadWeatherResultByCoordinates(ctx context.Context, coordinates Coordinates) (_ *WeatherResult, err error) {
+	ctx, span := q.tracer.Start(ctx, "Queryer.ReadWeatherResultByCoordinates")
+	defer func() { endSpan(span, err) }()
+
+	if err := coordinates.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: coordinates: %w", ErrInvalidInput, err)
+	}
+
+	coordinates = coordinates.bucket(q.coordinatePrecision)
+
+	key := resultCacheKey{coordinates: coordinates.format(q.coordinatePrecision)}
+
+	span.SetAttributes(attribute.String(coordinatesAttributeKey, key.coordinates))
+
+	logger := q.getLoggerFromContext(ctx).WithValues(coordinatesLogKey, key.coordinates)
+
+	return q.readWeatherResult(ctx, logger, key, coordinatesWeatherQuery(coordinates))
+}
+
+// coordinatesWeatherQuery returns the provider query for the weather at coordinates.
+func coordinatesWeatherQuery(coordinates Coordinates) providerQueryFunc {
+	return func(ctx context.Context, provider Provider) (interface{}, error) {
+		summary, err := provider.GetWeatherSummaryByCoordinates(ctx, coordinates)
+		if summary == nil {
+			return nil, err
+		}
+
+		return summary, err
+	}
+}
+
+// readWeatherResult reads the weather result cached under key, using query to load a
+// new result should the cached result be missing or expired.
+func (q *Queryer) readWeatherResult(
+	ctx context.Context,
+	logger logr.Logger,
+	key resultCacheKey,
+	query providerQueryFunc,
+) (*WeatherResult, error) {
+	res, err := q.readResult(ctx, logger, key, q.resultCacheTTL, q.weatherLoader(query))
+	if err != nil {
+		return nil, err
+	}
 
-		queryAllProvidersCtx, queryAllProvidersCancel := context.WithTimeout(ctx, q.resultTimeout)
-		defer queryAllProvidersCancel()
+	return &WeatherResult{
+		Weather:   res.value.(*weather.Summary), // should never panic
+		CreatedAt: res.createdAt,
+		Expiry:    res.expiry,
+		Provider:  res.provider,
+		Stale:     res.stale,
+		Age:       res.age,
+		Sources:   res.sources,
+	}, nil
+}
+
+// result is a value (*weather.Summary or *weather.Forecast) read from providers or the
+// cache, along with timeline data & provenance.
+type result struct {
+	value     interface{}
+	provider  string
+	sources   []WeatherSource
+	createdAt time.Time
+	expiry    time.Time
+	stale     bool
+	age       time.Duration
+}
+
+// providerResult is a value successfully read from a provider.
+type providerResult struct {
+	value    interface{}
+	provider string
+	sources  []WeatherSource // Set if value was aggregated from multiple providers.
+}
+
+// providerQueryFunc queries a single provider for a value (*weather.Summary or
+// *weather.Forecast).
+type providerQueryFunc func(ctx context.Context, provider Provider) (interface{}, error)
+
+// providerLoadFunc loads a new value from providers.
+type providerLoadFunc func(ctx context.Context, logger logr.Logger) (*providerResult, error)
+
+// readResult reads the result cached under key, using load to load a new result (cached
+// for ttl) should the cached result be missing or expired. A cached result within the
+// refresh-ahead window of its expiry is returned as is, while a new result is loaded in the
+// background.
+func (q *Queryer) readResult(
+	ctx context.Context,
+	logger logr.Logger,
+	key resultCacheKey,
+	ttl time.Duration,
+	load providerLoadFunc,
+) (*result, error) {
+	res, err := q.getCachedResult(ctx, logger, key)
+	if err != nil {
+		return nil, err
+	}
 
-		newWeather, err, _ := q.queryAllProvidersForWeatherOnce.Do("queryAllProvidersForWeather", func() (interface{}, error) {
-			return q.queryAllProvidersForWeather(queryAllProvidersCtx, logger, city)
-		})
+	retrievedCachedResult := res != nil
+
+	if !retrievedCachedResult || q.clock.Now().After(res.expiry) {
+		logger.V(1).Info("Querying all providers.")
+
+		newResult, err := q.loadResult(ctx, logger, key, ttl, load)
 		if err != nil {
-			logger.Error(err, "Failed to retrieve new weather result.")
+			logger.Error(err, "Failed to retrieve new result.")
 
 			if !retrievedCachedResult {
-				return nil, errors.New("providerqueryer: failed to load a new result and no cached result to fall back on")
+				return nil, err
 			}
-		} else if newWeather != nil {
-			now := q.clock.Now().UTC()
-			result = &WeatherResult{
-				Weather:   newWeather.(*weather.Summary), // should never panic
-				CreatedAt: now,
-				Expiry:    now.Add(q.resultCacheTTL),
+
+			if staleness := q.clock.Now().Sub(res.expiry); q.maxStaleness > 0 && staleness > q.maxStaleness {
+				return nil, &TooStaleError{Staleness: staleness, MaxStaleness: q.maxStaleness, RetryAfter: ttl}
 			}
 
-			go q.cacheWeatherResult(ctx, logger, result)
+			res.stale = true
+
+			logger.V(1).Info("Serving stale result.", providerLogKey, res.provider)
+
+			q.metrics.recordStaleServed(ctx)
+		} else {
+			res = newResult
+		}
+	} else if q.refreshAheadWindow > 0 && res.expiry.Sub(q.clock.Now()) <= q.refreshAheadWindow {
+		q.refreshAhead(ctx, logger, key, ttl, load)
+	}
+
+	res.age = q.clock.Now().Sub(res.createdAt)
+
+	q.metrics.recordResultAge(ctx, res.age)
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(providerAttributeKey, res.provider), attribute.Bool(staleAttributeKey, res.stale))
+
+	return res, nil
+}
+
+// loadResult loads a new result using load, caching it under key for ttl. Concurrent loads
+// of the same key share a single call to load.
+func (q *Queryer) loadResult(
+	ctx context.Context,
+	logger logr.Logger,
+	key resultCacheKey,
+	ttl time.Duration,
+	load providerLoadFunc,
+) (*result, error) {
+	// Do reports a shared load to the caller that ran it too, so only callers that didn't are
+	// counted.
+	loaded := false
+
+	newValue, err, _ := q.queryAllProvidersOnce.Do(key.String(), func() (interface{}, error) {
+		loaded = true
+
+		loadCtx, loadCancel := context.WithTimeout(ctx, q.resultTimeout)
+		defer loadCancel()
+
+		newResult, err := load(loadCtx, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		now := q.clock.Now().UTC()
+		res := &result{
+			value:     newResult.value,
+			provider:  newResult.provider,
+			sources:   newResult.sources,
+			createdAt: now,
+			expiry:    now.Add(ttl),
 		}
+
+		q.freshestResult.observe(res.createdAt)
+
+		// The result is cached even if ctx is cancelled once it's returned.
+		go q.cacheResult(detachContext(ctx), logger, key, res)
+
+		return res, nil
+	})
+	if !loaded {
+		q.metrics.recordSharedLoad(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Each caller gets its own copy, being free to modify it.
+	res := *newValue.(*result) // should never panic
+
+	return &res, nil
+}
+
+// refreshAhead loads a new result for key in the background, unless one is already being
+// loaded. The load is detached from ctx such that it outlives the request that started it.
+func (q *Queryer) refreshAhead(
+	ctx context.Context,
+	logger logr.Logger,
+	key resultCacheKey,
+	ttl time.Duration,
+	load providerLoadFunc,
+) {
+	if _, refreshing := q.refreshing.LoadOrStore(key.String(), struct{}{}); refreshing {
+		return
+	}
+
+	logger.V(1).Info("Refreshing result ahead of expiry.")
+
+	go func() {
+		defer q.refreshing.Delete(key.String())
+
+		if _, err := q.loadResult(detachContext(ctx), logger, key, ttl, load); err != nil {
+			logger.Error(err, "Failed to refresh result ahead of expiry.")
+		}
+	}()
+}
+
+// warmWeatherResult loads a new weather result for city, unless the cached result expires
+// after within.
+func (q *Queryer) warmWeatherResult(ctx context.Context, city string, within time.Duration) error {
+	key, query, err := cityWeatherQuery(city)
+	if err != nil {
+		return err
+	}
+
+	logger := q.getLoggerFromContext(ctx).WithValues(cityLogKey, key.city)
+
+	res, err := q.getCachedResult(ctx, logger, key)
+	if err != nil {
+		return errors.Wrap(err, "providerquery: warm result")
+	}
+
+	if res != nil && res.expiry.Sub(q.clock.Now()) > within {
+		return nil
+	}
+
+	logger.V(1).Info("Warming result.")
+
+	if _, err := q.loadResult(ctx, logger, key, q.resultCacheTTL, q.weatherLoader(query)); err != nil {
+		return errors.Wrap(err, "providerquery: warm result")
 	}
 
-	return result, nil
+	return nil
 }
 
-func (q *Queryer) getCachedReadWeatherResult(ctx context.Context, logger logr.Logger) *WeatherResult {
+// getCachedResult returns the result cached under key, or nil if there is none. Failing to get
+// the result is treated as there being none, unless the cache returns the error of a load (see
+// [Cache]), which is returned instead.
+func (q *Queryer) getCachedResult(ctx context.Context, logger logr.Logger, key resultCacheKey) (*result, error) {
+	ctx, span := q.tracer.Start(ctx, "Cache.Get", trace.WithAttributes(attribute.String(cacheKeyAttributeKey, key.String())))
+	defer span.End()
+
 	cacheGetCtx, cacheGetCancel := context.WithTimeout(ctx, q.cacheTimeout)
 	defer cacheGetCancel()
 
-	previousWeather, previousExpiry, err := q.cache.Get(cacheGetCtx, resultCacheKey{})
+	previousValue, previousExpiry, err := q.cache.Get(cacheGetCtx, key)
+	if isLoadError(err) {
+		span.RecordError(err)
+
+		return nil, err
+	}
+
 	if err != nil {
 		logger.Error(err, "Failed to retrieve result from cache.")
+
+		span.RecordError(err)
+		q.metrics.recordCacheError(ctx, "get")
 	}
 
-	var result *WeatherResult
+	var res *result
+
+	if serialized, ok := previousValue.([]byte); ok {
+		entry, err := unmarshalResultCacheEntry(serialized)
+		if err != nil {
+			logger.Error(err, "Failed to read result from cache, ignoring it.")
+
+			q.metrics.recordCacheError(ctx, "get")
+			q.metrics.recordCacheGet(ctx, false)
+
+			return nil, nil
+		}
 
-	if previousWeather != nil {
-		cachedWeather := previousWeather.(resultCacheEntry) // Should never panic
-		result = &WeatherResult{
-			Weather:   cachedWeather.result,
-			CreatedAt: cachedWeather.createdAt,
-			Expiry:    previousExpiry,
+		previousValue = entry
+	}
+
+	if previousValue != nil {
+		cachedValue := previousValue.(resultCacheEntry) // Should never panic
+		res = &result{
+			value:     cachedValue.result,
+			provider:  cachedValue.provider,
+			sources:   cachedValue.sources,
+			createdAt: cachedValue.createdAt,
+			expiry:    previousExpiry,
 		}
 
+		q.freshestResult.observe(res.createdAt)
+
 		logger.V(1).Info("Cache hit", "expires", previousExpiry.Sub(q.clock.Now()))
 	}
 
-	return result
+	span.SetAttributes(attribute.Bool(cacheHitAttributeKey, res != nil))
+	q.metrics.recordCacheGet(ctx, res != nil)
+
+	return res, nil
 }
 
-// queryAllProvidersForWeather returns a weather summary by city name.
-// It will query each provider one at a time until it gets a successful response to return.
-func (q *Queryer) queryAllProvidersForWeather(
+// queryAllProviders returns a value using query across providers.
+// It will query providers (in the order decided by the ordering strategy, skipping those with
+// an open circuit breaker) according to the query mode until it gets a successful response to
+// return, or a provider refuses the query (see [ErrLocationNotFound] & [ErrInvalidInput]). Any
+// queries still in flight at that point are cancelled. Should no provider answer successfully,
+// the error is an [ErrProvidersUnavailable] or [ErrUpstreamTimeout].
+func (q *Queryer) queryAllProviders(
 	ctx context.Context,
 	logger logr.Logger,
-	cityName string,
-) (*weather.Summary, error) {
-	for _, provider := range q.providers {
-		res, err := q.queryProviderForWeather(ctx, cityName, provider)
+	providers []Provider,
+	query providerQueryFunc,
+) (*providerResult, error) {
+	providers, _ = orderProviders(q.ordering, q.stats, providers)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make(chan *providerResponse, len(providers))
+	inFlight, next := 0, 0
+
+	var errs []error
+
+	// queryNextProvider starts querying the next provider allowed by its circuit breaker. It
+	// returns false if there are no providers left to query.
+	queryNextProvider := func() bool {
+		for next < len(providers) {
+			provider := providers[next]
+			next++
+
+			if q.startProviderQuery(ctx, logger, provider, query, responses) {
+				inFlight++
+
+				return true
+			}
+		}
+
+		return false
+	}
+
+	queryNextProvider()
+
+	if q.queryMode == QueryModeRace {
+		for queryNextProvider() {
+		}
+	}
+
+	for inFlight > 0 {
+		var hedgeDelay time.Duration
+		if q.queryMode == QueryModeHedged && next < len(providers) {
+			hedgeDelay = q.hedgeDelay
+		}
+
+		res, err := awaitProviderResponse(ctx, responses, hedgeDelay)
 		if err != nil {
-			logger.Error(err, "Failed to query provider for weather.", providerLogKey, provider.ProviderName())
+			return nil, contextDoneError(err)
+		}
+
+		if res == nil {
+			logger.V(1).Info("Hedging, provider has not answered within the hedge delay.")
+
+			queryNextProvider()
+
+			continue
+		}
+
+		inFlight--
+
+		if res.err != nil {
+			logger.Error(res.err, "Failed to query provider.", providerLogKey, res.provider.ProviderName())
+		}
+
+		if res.value != nil {
+			return &providerResult{value: res.value, provider: res.provider.ProviderName()}, nil
 		}
 
-		if res != nil {
-			return res, nil
+		// The other providers won't answer the query either.
+		if isQueryError(res.err) {
+			return nil, res.err
+		}
+
+		errs = append(errs, res.err)
+
+		if q.queryMode != QueryModeRace && queryNextProvider() {
+			q.metrics.recordFailover(ctx, res.provider.ProviderName())
 		}
 	}
 
-	return nil, errors.New("providerquery: no successful provider responses")
+	return nil, noSuccessfulResponsesError(errs)
 }
 
-func (q *Queryer) queryProviderForWeather(
+// startProviderQuery starts querying provider in the background if allowed by its circuit
+// breaker, sending the response to responses. It returns false if the provider was skipped.
+func (q *Queryer) startProviderQuery(
 	ctx context.Context,
-	cityName string,
+	logger logr.Logger,
 	provider Provider,
-) (*weather.Summary, error) {
+	query providerQueryFunc,
+	responses chan<- *providerResponse,
+) bool {
+	breakerDone, allowed := q.breakers[provider.ProviderName()].allow(logger)
+	if !allowed {
+		logger.V(1).Info("Skipping provider, circuit breaker is open.", providerLogKey, provider.ProviderName())
+
+		return false
+	}
+
+	go func() {
+		start := q.clock.Now()
+		res, err := q.queryProvider(ctx, provider, query)
+		outcome := newBreakerOutcome(ctx, err)
+
+		duration := q.clock.Now().Sub(start)
+
+		breakerDone(outcome)
+		q.stats.observe(provider.ProviderName(), outcome, duration, q.clock.Now())
+		q.metrics.recordProviderRequest(ctx, provider.ProviderName(), outcome, duration)
+
+		responses <- &providerResponse{provider: provider, value: res, err: err}
+	}()
+
+	return true
+}
+
+func (q *Queryer) queryProvider(
+	ctx context.Context,
+	provider Provider,
+	query providerQueryFunc,
+) (_ interface{}, err error) {
+	ctx, span := q.tracer.Start(ctx, "Provider.Query", trace.WithAttributes(attribute.String(providerAttributeKey, provider.ProviderName())))
+	defer func() { endSpan(span, err) }()
+
 	if ctx.Err() != nil {
-		return nil, errors.Wrap(ctx.Err(), "providerquery: context done before exhausting providers")
+		return nil, contextDoneError(ctx.Err())
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, q.providerTimeout)
 	defer cancel()
 
-	weatherSummary, err := provider.GetWeatherSummary(ctx, cityName)
+	value, err := query(ctx, provider)
 	if err != nil {
-		return nil, errors.Wrap(err, "get weather summary")
+		return nil, errors.Wrap(err, "query provider")
 	}
 
-	return weatherSummary, nil
+	return value, nil
 }
 
-func (q *Queryer) cacheWeatherResult(ctx context.Context, logger logr.Logger, result *WeatherResult) {
-	entry := resultCacheEntry{result: result.Weather, createdAt: result.CreatedAt}
+func (q *Queryer) cacheResult(ctx context.Context, logger logr.Logger, key resultCacheKey, res *result) {
+	entry := resultCacheEntry{result: res.value, provider: res.provider, sources: res.sources, createdAt: res.createdAt}
+
+	ctx, span := q.tracer.Start(ctx, "Cache.Set", trace.WithAttributes(attribute.String(cacheKeyAttributeKey, key.String())))
 
 	cacheSetCtx, cacheSetCancel := context.WithTimeout(ctx, q.cacheTimeout)
 	defer cacheSetCancel()
 
-	// Cache the new weather summary result.
-	if err := q.cache.Set(cacheSetCtx, resultCacheKey{}, entry, result.Expiry); err != nil {
+	// Cache the new result.
+	err := q.cache.Set(cacheSetCtx, key, entry, res.expiry)
+	endSpan(span, err)
+
+	if err != nil {
 		logger.Error(err, "Failed to set result in cache.")
+
+		q.metrics.recordCacheError(ctx, "set")
 	} else {
 		logger.V(1).Info("Cached result.")
 	}
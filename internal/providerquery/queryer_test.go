@@ -3,14 +3,73 @@ package providerquery
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/byatesrae/weather"
 )
 
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(name string) *ProviderMock {
+		return &ProviderMock{
+			ProviderNameFunc: func() string {
+				return name
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		giveProviders []Provider
+		giveOptions   []func(o *NewOptions)
+		expectedErr   string
+	}{
+		{
+			name:          "unique_names",
+			giveProviders: []Provider{newProvider("provider1"), newProvider("provider2")},
+		},
+		{
+			name:          "duplicate_names",
+			giveProviders: []Provider{newProvider("provider1"), newProvider("provider2"), newProvider("provider1")},
+			expectedErr:   `providerquery: duplicate provider name "provider1"`,
+		},
+		{
+			name:          "zero_provider_timeout",
+			giveProviders: []Provider{newProvider("provider1")},
+			giveOptions:   []func(o *NewOptions){WithProviderTimeout(0)},
+			expectedErr:   "providerquery: provider timeout must be greater than 0",
+		},
+		{
+			name:          "negative_result_timeout",
+			giveProviders: []Provider{newProvider("provider1")},
+			giveOptions:   []func(o *NewOptions){WithResultTimeout(-time.Second)},
+			expectedErr:   "providerquery: result timeout must be greater than 0",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := New(tc.giveProviders, &CacheMock{}, tc.giveOptions...)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, actual)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, actual)
+			}
+		})
+	}
+}
+
 func TestQueryerReadWeatherResult(t *testing.T) {
 	t.Parallel()
 
@@ -21,6 +80,16 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 		Weather:   &weather.Summary{Temperature: 123.456},
 		CreatedAt: clock.now,
 		Expiry:    clock.now.Add(resultCacheTTL),
+		Provider:  "goodProvider",
+	}
+
+	staleResult := &WeatherResult{
+		Weather:   goodResult.Weather,
+		CreatedAt: clock.now.Add(-time.Minute),
+		Expiry:    clock.now.Add(-time.Minute).Add(resultCacheTTL),
+		Provider:  "goodProvider",
+		Stale:     true,
+		Age:       time.Minute,
 	}
 
 	goodProvider := &ProviderMock{
@@ -41,6 +110,49 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 		},
 	}
 
+	notFoundProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, fmt.Errorf("intentional test error: %w", ErrLocationNotFound)
+		},
+		ProviderNameFunc: func() string {
+			return "notFoundProvider"
+		},
+	}
+
+	invalidInputProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, fmt.Errorf("intentional test error: %w", ErrInvalidInput)
+		},
+		ProviderNameFunc: func() string {
+			return "invalidInputProvider"
+		},
+	}
+
+	unsupportedProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, fmt.Errorf("intentional test error: %w", ErrLocationUnsupported)
+		},
+		ProviderNameFunc: func() string {
+			return "unsupportedProvider"
+		},
+	}
+
+	otherUnsupportedProvider := &ProviderMock{
+		GetWeatherSummaryFunc: unsupportedProvider.GetWeatherSummaryFunc,
+		ProviderNameFunc: func() string {
+			return "otherUnsupportedProvider"
+		},
+	}
+
+	timeoutProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, fmt.Errorf("intentional test error: %w", context.DeadlineExceeded)
+		},
+		ProviderNameFunc: func() string {
+			return "timeoutProvider"
+		},
+	}
+
 	hangingProvider := &ProviderMock{
 		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
 			<-ctx.Done()
@@ -62,7 +174,15 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 	}
 	cacheWithResult := &CacheMock{
 		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
-			return resultCacheEntry{result: goodResult.Weather, createdAt: goodResult.CreatedAt}, goodResult.Expiry, nil
+			return resultCacheEntry{result: goodResult.Weather, provider: goodResult.Provider, createdAt: goodResult.CreatedAt}, goodResult.Expiry, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+	cacheWithExpiredResult := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return resultCacheEntry{result: staleResult.Weather, provider: staleResult.Provider, createdAt: staleResult.CreatedAt}, staleResult.Expiry, nil
 		},
 		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
 			return nil
@@ -76,6 +196,14 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 			return errors.New("intentional test error")
 		},
 	}
+	loadErrCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, fmt.Errorf("intentional test error: %w", ErrLocationNotFound)
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
 	hangCache := &CacheMock{
 		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
 			<-ctx.Done()
@@ -99,52 +227,136 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 	}{
 		{
 			name:        "success",
-			withQueryer: New([]Provider{goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
 			expected:    goodResult,
 		},
 		{
 			name:        "success_cache_error",
-			withQueryer: New([]Provider{goodProvider}, errCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{goodProvider}, errCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
 			expected:    goodResult,
 		},
 		{
 			name:        "success_cache_hang",
-			withQueryer: New([]Provider{goodProvider}, hangCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{goodProvider}, hangCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
 			expected:    goodResult,
 		},
 		{
 			name:        "success_use_cache_provider_err",
-			withQueryer: New([]Provider{errProvider}, cacheWithResult, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, cacheWithResult, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
 			expected:    goodResult,
 		},
 		{
 			name:        "success_use_cache_provider_hang",
-			withQueryer: New([]Provider{hangingProvider}, cacheWithResult, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{hangingProvider}, cacheWithResult, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
 			expected:    goodResult,
 		},
+		{
+			name:        "success_stale_cache_provider_err",
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, cacheWithExpiredResult, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expected:    staleResult,
+		},
+		{
+			name:        "success_stale_within_max_staleness",
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, cacheWithExpiredResult, withClock(clock), WithResultCacheTTL(resultCacheTTL), WithMaxStaleness(time.Minute)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expected:    staleResult,
+		},
+		{
+			name:        "stale_past_max_staleness",
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, cacheWithExpiredResult, withClock(clock), WithResultCacheTTL(resultCacheTTL), WithMaxStaleness(time.Second*30)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: cached result expired 59s ago, exceeding the max staleness of 30s",
+		},
 		{
 			name:        "provider_err_empty_cache",
-			withQueryer: New([]Provider{errProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: all providers unavailable: no successful provider responses",
+		},
+		{
+			name:        "location_not_found_no_failover",
+			withQueryer: newTestQueryer(t, []Provider{notFoundProvider, goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "query provider: intentional test error: providerquery: location not found",
+		},
+		{
+			name:        "invalid_input_no_failover",
+			withQueryer: newTestQueryer(t, []Provider{invalidInputProvider, goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "query provider: intentional test error: providerquery: invalid input",
+		},
+		{
+			name:        "success_location_unsupported_failover",
+			withQueryer: newTestQueryer(t, []Provider{unsupportedProvider, goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expected:    goodResult,
+		},
+		{
+			name:        "location_unsupported_by_all_providers",
+			withQueryer: newTestQueryer(t, []Provider{unsupportedProvider, otherUnsupportedProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: location not found: no provider covers the location",
+		},
+		{
+			name:        "provider_timeout_empty_cache",
+			withQueryer: newTestQueryer(t, []Provider{timeoutProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: upstream timeout: no successful provider responses",
+		},
+		{
+			name:        "success_provider_hang_failover",
+			withQueryer: newTestQueryer(t, []Provider{hangingProvider, goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL), WithProviderTimeout(time.Millisecond*10)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
-			expectedErr: "providerqueryer: failed to load a new result and no cached result to fall back on",
+			expected:    goodResult,
+		},
+		{
+			name:        "provider_hang_result_timeout",
+			withQueryer: newTestQueryer(t, []Provider{hangingProvider, goodProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL), WithProviderTimeout(time.Minute), WithResultTimeout(time.Millisecond*10)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: upstream timeout: providerquery: context done before exhausting providers: context deadline exceeded",
+		},
+		{
+			name:        "provider_timeout_and_err_empty_cache",
+			withQueryer: newTestQueryer(t, []Provider{timeoutProvider, errProvider}, emptyCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "providerquery: all providers unavailable: no successful provider responses",
+		},
+		{
+			name:        "cache_load_err",
+			withQueryer: newTestQueryer(t, []Provider{goodProvider}, loadErrCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			giveContext: context.Background(),
+			giveCity:    "ABC",
+			expectedErr: "intentional test error: providerquery: location not found",
 		},
 		{
 			name:        "provider_err_cache_err",
-			withQueryer: New([]Provider{errProvider}, errCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
+			withQueryer: newTestQueryer(t, []Provider{errProvider}, errCache, withClock(clock), WithResultCacheTTL(resultCacheTTL)),
 			giveContext: context.Background(),
 			giveCity:    "ABC",
-			expectedErr: "providerqueryer: failed to load a new result and no cached result to fall back on",
+			expectedErr: "providerquery: all providers unavailable: no successful provider responses",
 		},
 	} {
 		tc := tc
@@ -166,3 +378,241 @@ func TestQueryerReadWeatherResult(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryerReadWeatherResultPerCity(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	cityTemperatures := map[string]float64{"Sydney": 20, "Melbourne": 15}
+
+	provider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: cityTemperatures[cityName]}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "provider"
+		},
+	}
+
+	cache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t, []Provider{provider}, cache, withClock(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	sydney, err := queryer.ReadWeatherResult(ctx, "Sydney")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(20), sydney.Weather.Temperature)
+
+	melbourne, err := queryer.ReadWeatherResult(ctx, "Melbourne")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(15), melbourne.Weather.Temperature)
+
+	_, err = queryer.ReadWeatherResult(ctx, "  sydney ")
+	assert.NoError(t, err)
+
+	_, err = queryer.ReadWeatherResult(ctx, " ")
+	assert.EqualError(t, err, "providerquery: invalid input: city is required")
+
+	var actualKeys []interface{}
+	for _, call := range cache.GetCalls() {
+		actualKeys = append(actualKeys, call.Key)
+	}
+
+	assert.Equal(
+		t,
+		[]interface{}{
+			resultCacheKey{city: "sydney"},
+			resultCacheKey{city: "melbourne"},
+			resultCacheKey{city: "sydney"},
+		},
+		actualKeys,
+	)
+}
+
+func TestNormalizeCity(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		give     string
+		expected string
+	}{
+		{give: "Sydney", expected: "sydney"},
+		{give: "  SYDNEY  ", expected: "sydney"},
+		{give: "Sydney,  Nova   Scotia", expected: "sydney, nova scotia"},
+		{give: "", expected: ""},
+	} {
+		assert.Equal(t, tc.expected, normalizeCity(tc.give), "normalizeCity(%q)", tc.give)
+	}
+}
+
+func TestQueryerReadWeatherResultByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	provider := &ProviderMock{
+		GetWeatherSummaryByCoordinatesFunc: func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 20}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "provider"
+		},
+	}
+
+	cache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t, []Provider{provider}, cache, withClock(clock), WithCoordinatePrecision(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	_, err := queryer.ReadWeatherResultByCoordinates(ctx, Coordinates{Latitude: -33.8688, Longitude: 151.2093})
+	assert.NoError(t, err)
+
+	_, err = queryer.ReadWeatherResultByCoordinates(ctx, Coordinates{Latitude: -33.8712, Longitude: 151.2071})
+	assert.NoError(t, err)
+
+	_, err = queryer.ReadWeatherResultByCoordinates(ctx, Coordinates{Latitude: -91, Longitude: 151.2071})
+	assert.EqualError(t, err, "providerquery: invalid input: coordinates: latitude -91 is not in the range [-90, 90]")
+
+	for _, call := range cache.GetCalls() {
+		assert.Equal(t, resultCacheKey{coordinates: "-33.87,151.21"}, call.Key)
+	}
+
+	for _, call := range provider.GetWeatherSummaryByCoordinatesCalls() {
+		assert.Equal(t, Coordinates{Latitude: -33.87, Longitude: 151.21}, call.Coordinates)
+	}
+
+	assert.Len(t, cache.GetCalls(), 2)
+}
+
+func TestQueryerReadWeatherResultTooStale(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	errProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, errors.New("intentional test error")
+		},
+		ProviderNameFunc: func() string {
+			return "errProvider"
+		},
+	}
+
+	cacheWithExpiredResult := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return resultCacheEntry{result: &weather.Summary{}, createdAt: clock.now.Add(-time.Hour)}, clock.now.Add(-time.Minute), nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{errProvider},
+		cacheWithExpiredResult,
+		withClock(clock),
+		WithResultCacheTTL(time.Second*3),
+		WithMaxStaleness(time.Second*30),
+	)
+
+	_, err := queryer.ReadWeatherResult(context.Background(), "Sydney")
+
+	var tooStaleErr *TooStaleError
+	if assert.ErrorAs(t, err, &tooStaleErr) {
+		assert.Equal(t, &TooStaleError{Staleness: time.Minute, MaxStaleness: time.Second * 30, RetryAfter: time.Second * 3}, tooStaleErr)
+	}
+}
+
+func TestQueryerReadWeatherResultRefreshAhead(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	cachedResult := &WeatherResult{
+		Weather:   &weather.Summary{Temperature: 10},
+		CreatedAt: clock.now.Add(-time.Second * 2),
+		Expiry:    clock.now.Add(time.Millisecond * 500),
+		Provider:  "goodProvider",
+		Age:       time.Second * 2,
+	}
+
+	release := make(chan struct{})
+
+	blockingProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			<-release
+
+			return &weather.Summary{Temperature: 20}, ctx.Err()
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	cacheWithResult := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return resultCacheEntry{result: cachedResult.Weather, provider: cachedResult.Provider, createdAt: cachedResult.CreatedAt}, cachedResult.Expiry, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return ctx.Err()
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{blockingProvider},
+		cacheWithResult,
+		withClock(clock),
+		WithResultCacheTTL(time.Second*3),
+		WithRefreshAheadWindow(time.Second),
+	)
+
+	// The request context is cancelled once the result is read, which shouldn't affect the refresh.
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		actual, err := queryer.ReadWeatherResult(ctx, "Sydney")
+		cancel()
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, cachedResult, actual)
+		}
+	}
+
+	close(release)
+
+	assert.Eventually(t, func() bool { return len(cacheWithResult.SetCalls()) == 1 }, time.Second, time.Millisecond*10)
+
+	setCall := cacheWithResult.SetCalls()[0]
+	assert.Equal(t, resultCacheEntry{result: &weather.Summary{Temperature: 20}, provider: "goodProvider", createdAt: clock.now}, setCall.Val)
+	assert.Equal(t, clock.now.Add(time.Second*3), setCall.Expiry)
+	assert.Len(t, blockingProvider.GetWeatherSummaryCalls(), 1, "only one refresh at a time")
+}
+
+// newTestQueryer creates a new [Queryer], failing t if it can't be created.
+func newTestQueryer(t *testing.T, providers []Provider, cache Cache, overrides ...func(o *NewOptions)) *Queryer {
+	t.Helper()
+
+	queryer, err := New(providers, cache, overrides...)
+	require.NoError(t, err, "create queryer")
+
+	return queryer
+}
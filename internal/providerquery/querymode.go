@@ -0,0 +1,66 @@
+package providerquery
+
+import (
+	"context"
+	"time"
+)
+
+// QueryMode decides how providers are queried when loading a new result.
+type QueryMode string
+
+const (
+	// QueryModeSequential queries providers one at a time, only querying the next provider
+	// once the previous has failed.
+	QueryModeSequential QueryMode = "sequential"
+
+	// QueryModeHedged queries providers one at a time but, if a provider hasn't answered
+	// within the hedge delay (see [WithHedgeDelay]), also queries the next provider in
+	// parallel. The first successful answer wins.
+	QueryModeHedged QueryMode = "hedged"
+
+	// QueryModeRace queries all providers in parallel. The first successful answer wins.
+	QueryModeRace QueryMode = "race"
+)
+
+// Valid reports whether m is a known query mode.
+func (m QueryMode) Valid() bool {
+	switch m {
+	case QueryModeSequential, QueryModeHedged, QueryModeRace:
+		return true
+	default:
+		return false
+	}
+}
+
+// providerResponse is the response of a single provider query.
+type providerResponse struct {
+	provider Provider
+	value    interface{}
+	err      error
+}
+
+// awaitProviderResponse waits for a response on responses. If delay is > 0 and elapses
+// first, it returns nil.
+func awaitProviderResponse(
+	ctx context.Context,
+	responses <-chan *providerResponse,
+	delay time.Duration,
+) (*providerResponse, error) {
+	var delayElapsed <-chan time.Time
+
+	if delay > 0 {
+		delayTimer := time.NewTimer(delay)
+		defer delayTimer.Stop()
+
+		delayElapsed = delayTimer.C
+	}
+
+	select {
+	case response := <-responses:
+		return response, nil
+	case <-delayElapsed:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
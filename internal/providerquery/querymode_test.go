@@ -0,0 +1,194 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestQueryerReadWeatherResultQueryMode(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	// newProvider creates a provider named name that answers after delay (unless its context
+	// is done first) with err, or a summary if err is nil.
+	newProvider := func(name string, delay time.Duration, err error) *ProviderMock {
+		return &ProviderMock{
+			GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				return &weather.Summary{}, nil
+			},
+			ProviderNameFunc: func() string {
+				return name
+			},
+		}
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	for _, tc := range []struct {
+		name             string
+		giveMode         QueryMode
+		giveHedgeDelay   time.Duration
+		giveProviders    []*ProviderMock
+		expectedProvider string
+		expectedCalls    []int // The number of calls to each of giveProviders.
+		expectedErr      string
+	}{
+		{
+			name:     "sequential_slow_provider",
+			giveMode: QueryModeSequential,
+			giveProviders: []*ProviderMock{
+				newProvider("slowProvider", time.Millisecond*200, nil),
+				newProvider("fastProvider", 0, nil),
+			},
+			expectedProvider: "slowProvider",
+			expectedCalls:    []int{1, 0},
+		},
+		{
+			name:     "sequential_err_provider",
+			giveMode: QueryModeSequential,
+			giveProviders: []*ProviderMock{
+				newProvider("errProvider", 0, errors.New("intentional test error")),
+				newProvider("fastProvider", 0, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name:           "hedged_slow_provider",
+			giveMode:       QueryModeHedged,
+			giveHedgeDelay: time.Millisecond * 10,
+			giveProviders: []*ProviderMock{
+				newProvider("slowProvider", time.Second, nil),
+				newProvider("fastProvider", 0, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name:           "hedged_fast_provider",
+			giveMode:       QueryModeHedged,
+			giveHedgeDelay: time.Millisecond * 500,
+			giveProviders: []*ProviderMock{
+				newProvider("fastProvider", 0, nil),
+				newProvider("slowProvider", time.Second, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 0},
+		},
+		{
+			name:           "hedged_err_provider",
+			giveMode:       QueryModeHedged,
+			giveHedgeDelay: time.Millisecond * 500,
+			giveProviders: []*ProviderMock{
+				newProvider("errProvider", 0, errors.New("intentional test error")),
+				newProvider("fastProvider", 0, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name:           "hedged_unsupported_provider",
+			giveMode:       QueryModeHedged,
+			giveHedgeDelay: time.Millisecond * 500,
+			giveProviders: []*ProviderMock{
+				newProvider("unsupportedProvider", 0, fmt.Errorf("intentional test error: %w", ErrLocationUnsupported)),
+				newProvider("fastProvider", time.Millisecond*10, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name:     "race",
+			giveMode: QueryModeRace,
+			giveProviders: []*ProviderMock{
+				newProvider("slowProvider", time.Second, nil),
+				newProvider("errProvider", 0, errors.New("intentional test error")),
+				newProvider("fastProvider", time.Millisecond*10, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1, 1},
+		},
+		{
+			name:     "race_unsupported_provider",
+			giveMode: QueryModeRace,
+			giveProviders: []*ProviderMock{
+				newProvider("unsupportedProvider", 0, fmt.Errorf("intentional test error: %w", ErrLocationUnsupported)),
+				newProvider("fastProvider", time.Millisecond*10, nil),
+			},
+			expectedProvider: "fastProvider",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name:     "race_all_err",
+			giveMode: QueryModeRace,
+			giveProviders: []*ProviderMock{
+				newProvider("errProvider1", 0, errors.New("intentional test error")),
+				newProvider("errProvider2", 0, errors.New("intentional test error")),
+			},
+			expectedCalls: []int{1, 1},
+			expectedErr:   "providerquery: all providers unavailable: no successful provider responses",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			providers := make([]Provider, len(tc.giveProviders))
+			for i, provider := range tc.giveProviders {
+				providers[i] = provider
+			}
+
+			queryer := newTestQueryer(t,
+				providers,
+				emptyCache,
+				withClock(clock),
+				WithQueryMode(tc.giveMode),
+				WithHedgeDelay(tc.giveHedgeDelay),
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			start := time.Now()
+			actual, actualErr := queryer.ReadWeatherResult(ctx, "Sydney")
+
+			assert.Less(t, time.Since(start), time.Millisecond*500, "losing providers are not waited on")
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, actualErr, tc.expectedErr)
+			} else if assert.NoError(t, actualErr) {
+				assert.Equal(t, tc.expectedProvider, actual.Provider)
+			}
+
+			for i, provider := range tc.giveProviders {
+				assert.Len(t, provider.GetWeatherSummaryCalls(), tc.expectedCalls[i], "calls to %s", provider.ProviderName())
+			}
+		})
+	}
+}
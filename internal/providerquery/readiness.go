@@ -0,0 +1,78 @@
+package providerquery
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachePinger is implemented by caches that can check they're reachable (e.g. caches stored
+// remotely). Caches that don't implement it are assumed to always be reachable.
+type CachePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Readiness is the state of the dependencies the [Queryer] reads results from.
+type Readiness struct {
+	Providers      []ProviderReadiness
+	CacheErr       error     // Why the cache is unreachable, nil if it's reachable.
+	FreshestResult time.Time // When the freshest result read was fetched from its provider (zero if none).
+}
+
+// ProviderReadiness is the state of a provider.
+type ProviderReadiness struct {
+	Provider    string    // The name of the provider, see [Provider.ProviderName].
+	Breaker     string    // The state of the provider's circuit breaker for the next query: "closed", "half_open" or "open".
+	Available   bool      // False if the provider will be skipped (its circuit breaker being open).
+	LastSuccess time.Time // When the provider last responded successfully (zero if never).
+}
+
+// freshestResultTracker tracks the freshest result read or loaded.
+type freshestResultTracker struct {
+	mu        sync.Mutex
+	createdAt time.Time
+}
+
+// observe records a result created (fetched from its provider) at createdAt.
+func (t *freshestResultTracker) observe(createdAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if createdAt.After(t.createdAt) {
+		t.createdAt = createdAt
+	}
+}
+
+// get returns when the freshest result observed was created (zero if none).
+func (t *freshestResultTracker) get() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.createdAt
+}
+
+// Readiness returns the state of each provider & the cache, along with when the freshest
+// result was fetched from its provider. The cache is pinged if it implements [CachePinger].
+func (q *Queryer) Readiness(ctx context.Context) *Readiness {
+	readiness := &Readiness{FreshestResult: q.freshestResult.get()}
+
+	for _, stats := range q.stats.get(q.providers) {
+		state := q.breakers[stats.Provider].nextState()
+
+		readiness.Providers = append(readiness.Providers, ProviderReadiness{
+			Provider:    stats.Provider,
+			Breaker:     state.String(),
+			Available:   state != breakerOpen,
+			LastSuccess: stats.LastSuccess,
+		})
+	}
+
+	if pinger, ok := q.cache.(CachePinger); ok {
+		pingCtx, pingCancel := context.WithTimeout(ctx, q.cacheTimeout)
+		defer pingCancel()
+
+		readiness.CacheErr = pinger.Ping(pingCtx)
+	}
+
+	return readiness
+}
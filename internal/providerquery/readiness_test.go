@@ -0,0 +1,92 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+)
+
+// pingingCache is a cache implementing CachePinger.
+type pingingCache struct {
+	*CacheMock
+	pingErr error
+}
+
+func (c *pingingCache) Ping(ctx context.Context) error {
+	return c.pingErr
+}
+
+func TestQueryerReadiness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)
+	clock := &manualClock{now: now}
+
+	errProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, errors.New("Test123")
+		},
+		ProviderNameFunc: func() string {
+			return "errProvider"
+		},
+	}
+
+	goodProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 10}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	unreachableCache := &pingingCache{
+		CacheMock: &CacheMock{
+			GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+				return nil, time.Time{}, nil
+			},
+			SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+				return nil
+			},
+		},
+		pingErr: errors.New("Test456"),
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{errProvider, goodProvider},
+		unreachableCache,
+		withClock(clock),
+		WithBreakerFailureThreshold(2),
+		WithBreakerCoolDown(time.Minute),
+	)
+
+	readiness := queryer.Readiness(context.Background())
+	assert.True(t, readiness.FreshestResult.IsZero(), "no results read yet")
+
+	for _, city := range []string{"Sydney", "Melbourne"} {
+		_, err := queryer.ReadWeatherResult(context.Background(), city)
+		require.NoError(t, err)
+	}
+
+	readiness = queryer.Readiness(context.Background())
+	assert.Equal(t, &Readiness{
+		Providers: []ProviderReadiness{
+			{Provider: "errProvider", Breaker: "open", Available: false},
+			{Provider: "goodProvider", Breaker: "closed", Available: true, LastSuccess: now},
+		},
+		CacheErr:       errors.New("Test456"),
+		FreshestResult: now,
+	}, readiness)
+
+	// errProvider is probed once the cool-down has elapsed, so is available again.
+	clock.Add(time.Minute)
+
+	readiness = queryer.Readiness(context.Background())
+	assert.Equal(t, ProviderReadiness{Provider: "errProvider", Breaker: "half_open", Available: true}, readiness.Providers[0])
+}
@@ -0,0 +1,83 @@
+package providerquery
+
+import (
+	"context"
+	"time"
+)
+
+// HotCityScheduler keeps the weather results of a set of (frequently read) cities warm,
+// loading new results before the cached results expire. Reads of those cities are then
+// always served from the cache.
+type HotCityScheduler struct {
+	queryer  *Queryer
+	cities   []string
+	interval time.Duration
+}
+
+// NewHotCitySchedulerOptions are options for the NewHotCityScheduler function.
+type NewHotCitySchedulerOptions struct {
+	interval time.Duration
+}
+
+// WithHotCityRefreshInterval sets how often the cached results of hot cities are checked. A
+// new result is loaded for each city whose cached result would otherwise expire before the
+// next check.
+func WithHotCityRefreshInterval(interval time.Duration) func(o *NewHotCitySchedulerOptions) {
+	return func(o *NewHotCitySchedulerOptions) {
+		o.interval = interval
+	}
+}
+
+// NewHotCityScheduler creates a new [HotCityScheduler] that keeps the weather results of
+// cities warm using queryer.
+func NewHotCityScheduler(
+	queryer *Queryer,
+	cities []string,
+	overrides ...func(o *NewHotCitySchedulerOptions),
+) *HotCityScheduler {
+	options := &NewHotCitySchedulerOptions{
+		interval: time.Second,
+	}
+
+	for _, override := range overrides {
+		override(options)
+	}
+
+	return &HotCityScheduler{
+		queryer:  queryer,
+		cities:   cities,
+		interval: options.interval,
+	}
+}
+
+// Run keeps the weather results of the hot cities warm until ctx is done.
+func (s *HotCityScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.warm(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// warm loads new weather results for each hot city whose cached result expires before the
+// next run.
+func (s *HotCityScheduler) warm(ctx context.Context) {
+	logger := s.queryer.getLoggerFromContext(ctx)
+
+	for _, city := range s.cities {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := s.queryer.warmWeatherResult(ctx, city, s.interval); err != nil {
+			logger.Error(err, "Failed to warm hot city result.", cityLogKey, city)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package providerquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestHotCitySchedulerRun(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	for _, tc := range []struct {
+		name          string
+		giveExpiry    time.Time // The expiry of cached results, or zero if none are cached.
+		expectedCalls int       // The number of provider calls per hot city.
+	}{
+		{
+			name:          "not_cached",
+			expectedCalls: 1,
+		},
+		{
+			name:          "expiring",
+			giveExpiry:    clock.now.Add(time.Millisecond * 500),
+			expectedCalls: 1,
+		},
+		{
+			name:          "warm",
+			giveExpiry:    clock.now.Add(time.Minute),
+			expectedCalls: 0,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &ProviderMock{
+				GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+					return &weather.Summary{}, nil
+				},
+				ProviderNameFunc: func() string {
+					return "goodProvider"
+				},
+			}
+
+			cache := &CacheMock{
+				GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+					if tc.giveExpiry.IsZero() {
+						return nil, time.Time{}, nil
+					}
+
+					return resultCacheEntry{result: &weather.Summary{}, createdAt: clock.now}, tc.giveExpiry, nil
+				},
+				SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+					return nil
+				},
+			}
+
+			queryer := newTestQueryer(t, []Provider{provider}, cache, withClock(clock))
+
+			scheduler := NewHotCityScheduler(
+				queryer,
+				[]string{"Sydney", "Melbourne"},
+				WithHotCityRefreshInterval(time.Second),
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			done := make(chan struct{})
+			go func() {
+				scheduler.Run(ctx)
+				close(done)
+			}()
+
+			assert.Eventually(t, func() bool {
+				return len(cache.GetCalls()) >= 2 && len(provider.GetWeatherSummaryCalls()) >= tc.expectedCalls*2
+			}, time.Second, time.Millisecond*10)
+
+			cancel()
+			<-done
+
+			calls := map[string]int{}
+			for _, call := range provider.GetWeatherSummaryCalls() {
+				calls[call.CityName]++
+			}
+
+			assert.Equal(t, tc.expectedCalls, calls["Sydney"])
+			assert.Equal(t, tc.expectedCalls, calls["Melbourne"])
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package providerquery
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReadSerializedResult reads the result cached under key (as passed to [Cache], in its
+// string form), loading a new result should it be missing or expired. The result is returned
+// serialized (see [encoding.BinaryMarshaler]) along with its expiry.
+//
+// It allows a cache shared by multiple replicas to have a single replica load results on
+// behalf of the others (see peercache.WithLoader).
+func (q *Queryer) ReadSerializedResult(ctx context.Context, key string) ([]byte, time.Time, error) {
+	parsedKey, err := parseResultCacheKey(key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	logger := q.getLoggerFromContext(ctx).WithValues("key", key)
+
+	var ttl time.Duration
+	var load providerLoadFunc
+
+	switch {
+	case parsedKey.forecastDays != 0:
+		ttl, load = q.forecastCacheTTL, q.forecastLoader(cityForecastQuery(parsedKey.city, parsedKey.forecastDays))
+	case parsedKey.coordinates != "":
+		coordinates, err := parseCoordinates(parsedKey.coordinates)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		ttl, load = q.resultCacheTTL, q.weatherLoader(coordinatesWeatherQuery(coordinates))
+	default:
+		_, query, err := cityWeatherQuery(parsedKey.city)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		ttl, load = q.resultCacheTTL, q.weatherLoader(query)
+	}
+
+	res, err := q.readResult(ctx, logger, parsedKey, ttl, load)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	entry := resultCacheEntry{result: res.value, provider: res.provider, sources: res.sources, createdAt: res.createdAt}
+
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return data, res.expiry, nil
+}
+
+// parseResultCacheKey parses a key formatted by resultCacheKey.String.
+func parseResultCacheKey(s string) (resultCacheKey, error) {
+	var key resultCacheKey
+
+	location := s
+
+	if rest, ok := strings.CutPrefix(s, "forecast:"); ok {
+		days, city, ok := strings.Cut(rest, ":city:")
+		if !ok {
+			return resultCacheKey{}, errors.Errorf("providerquery: invalid forecast cache key %q", s)
+		}
+
+		forecastDays, err := strconv.Atoi(days)
+		if err != nil || forecastDays < 1 {
+			return resultCacheKey{}, errors.Errorf("providerquery: invalid forecast days in cache key %q", s)
+		}
+
+		key.forecastDays = forecastDays
+		location = "city:" + city
+	}
+
+	if city, ok := strings.CutPrefix(location, "city:"); ok && city != "" {
+		key.city = city
+
+		return key, nil
+	}
+
+	if coordinates, ok := strings.CutPrefix(location, "coordinates:"); ok && key.forecastDays == 0 {
+		key.coordinates = coordinates
+
+		return key, nil
+	}
+
+	return resultCacheKey{}, errors.Errorf("providerquery: invalid cache key %q", s)
+}
+
+// parseCoordinates parses coordinates formatted by Coordinates.format.
+func parseCoordinates(s string) (Coordinates, error) {
+	latitude, longitude, ok := strings.Cut(s, ",")
+	if !ok {
+		return Coordinates{}, errors.Errorf("providerquery: invalid coordinates %q", s)
+	}
+
+	var coordinates Coordinates
+	var err error
+
+	if coordinates.Latitude, err = strconv.ParseFloat(latitude, 64); err != nil {
+		return Coordinates{}, errors.Errorf("providerquery: invalid latitude %q", latitude)
+	}
+
+	if coordinates.Longitude, err = strconv.ParseFloat(longitude, 64); err != nil {
+		return Coordinates{}, errors.Errorf("providerquery: invalid longitude %q", longitude)
+	}
+
+	if err := coordinates.Validate(); err != nil {
+		return Coordinates{}, errors.Wrap(err, "providerquery: invalid coordinates")
+	}
+
+	return coordinates, nil
+}
@@ -0,0 +1,153 @@
+package providerquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestParseResultCacheKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        string
+		expected    resultCacheKey
+		expectedErr string
+	}{
+		{
+			name:     "city",
+			give:     "city:new york",
+			expected: resultCacheKey{city: "new york"},
+		},
+		{
+			name:     "coordinates",
+			give:     "coordinates:-33.87,151.21",
+			expected: resultCacheKey{coordinates: "-33.87,151.21"},
+		},
+		{
+			name:     "forecast",
+			give:     "forecast:3:city:sydney",
+			expected: resultCacheKey{city: "sydney", forecastDays: 3},
+		},
+		{
+			name:        "forecast_coordinates",
+			give:        "forecast:3:coordinates:-33.87,151.21",
+			expectedErr: `providerquery: invalid forecast cache key "forecast:3:coordinates:-33.87,151.21"`,
+		},
+		{
+			name:        "forecast_days",
+			give:        "forecast:x:city:sydney",
+			expectedErr: `providerquery: invalid forecast days in cache key "forecast:x:city:sydney"`,
+		},
+		{
+			name:        "empty_city",
+			give:        "city:",
+			expectedErr: `providerquery: invalid cache key "city:"`,
+		},
+		{
+			name:        "unknown",
+			give:        "postcode:2000",
+			expectedErr: `providerquery: invalid cache key "postcode:2000"`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parseResultCacheKey(tc.give)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, actual)
+				assert.Equal(t, tc.give, actual.String())
+			}
+		})
+	}
+}
+
+func TestQueryerReadSerializedResult(t *testing.T) {
+	t.Parallel()
+
+	clock := fixedClock{now: time.Date(2020, time.November, 11, 10, 10, 10, 0, time.UTC)}
+
+	provider := &ForecastProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 20}, nil
+		},
+		GetWeatherSummaryByCoordinatesFunc: func(ctx context.Context, coordinates Coordinates) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: coordinates.Latitude}, nil
+		},
+		GetWeatherForecastFunc: func(ctx context.Context, cityName string, days int) (*weather.Forecast, error) {
+			return &weather.Forecast{Daily: make([]weather.ForecastEntry, days)}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	queryer := newTestQueryer(t,
+		[]Provider{provider},
+		emptyCache,
+		withClock(clock),
+		WithResultCacheTTL(time.Second*3),
+		WithForecastCacheTTL(time.Minute*10),
+	)
+
+	for _, tc := range []struct {
+		name           string
+		giveKey        string
+		expectedResult interface{}
+		expectedExpiry time.Time
+	}{
+		{
+			name:           "city",
+			giveKey:        "city:sydney",
+			expectedResult: &weather.Summary{Temperature: 20},
+			expectedExpiry: clock.now.Add(time.Second * 3),
+		},
+		{
+			name:           "coordinates",
+			giveKey:        "coordinates:-33.87,151.21",
+			expectedResult: &weather.Summary{Temperature: -33.87},
+			expectedExpiry: clock.now.Add(time.Second * 3),
+		},
+		{
+			name:           "forecast",
+			giveKey:        "forecast:2:city:sydney",
+			expectedResult: &weather.Forecast{Daily: make([]weather.ForecastEntry, 2)},
+			expectedExpiry: clock.now.Add(time.Minute * 10),
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, expiry, err := queryer.ReadSerializedResult(context.Background(), tc.giveKey)
+			require.NoError(t, err)
+
+			actual, err := unmarshalResultCacheEntry(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, resultCacheEntry{result: tc.expectedResult, provider: "goodProvider", createdAt: clock.now}, actual)
+			assert.Equal(t, tc.expectedExpiry, expiry)
+		})
+	}
+}
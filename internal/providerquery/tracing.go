@@ -0,0 +1,29 @@
+package providerquery
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer used by the [Queryer], see [WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/providerquery"
+
+// The keys of attributes attached to spans, along with providerAttributeKey.
+const (
+	cityAttributeKey         = "city"
+	coordinatesAttributeKey  = "coordinates"
+	forecastDaysAttributeKey = "forecast_days"
+	staleAttributeKey        = "stale"
+	cacheKeyAttributeKey     = "cache.key"
+	cacheHitAttributeKey     = "cache.hit"
+)
+
+// endSpan ends span, recording err (if any) as the reason it failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
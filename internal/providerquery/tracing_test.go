@@ -0,0 +1,89 @@
+package providerquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/byatesrae/weather"
+)
+
+func TestQueryerReadWeatherResultTracing(t *testing.T) {
+	t.Parallel()
+
+	errProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return nil, errors.New("Test123")
+		},
+		ProviderNameFunc: func() string {
+			return "errProvider"
+		},
+	}
+
+	goodProvider := &ProviderMock{
+		GetWeatherSummaryFunc: func(ctx context.Context, cityName string) (*weather.Summary, error) {
+			return &weather.Summary{Temperature: 10}, nil
+		},
+		ProviderNameFunc: func() string {
+			return "goodProvider"
+		},
+	}
+
+	emptyCache := &CacheMock{
+		GetFunc: func(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+			return nil, time.Time{}, nil
+		},
+		SetFunc: func(ctx context.Context, key, val interface{}, expiry time.Time) error {
+			return nil
+		},
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+
+	queryer := newTestQueryer(t,
+		[]Provider{errProvider, goodProvider},
+		emptyCache,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := queryer.ReadWeatherResult(context.Background(), "Sydney")
+	require.NoError(t, err)
+
+	// The result is cached in the background.
+	assert.Eventually(t, func() bool { return len(recorder.Ended()) == 5 }, time.Second, time.Millisecond*10)
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+
+	require.Len(t, spans["Queryer.ReadWeatherResult"], 1)
+	root := spans["Queryer.ReadWeatherResult"][0]
+	assert.Contains(t, root.Attributes(), attribute.String(providerAttributeKey, "goodProvider"))
+
+	require.Len(t, spans["Cache.Get"], 1)
+	assert.Equal(t, root.SpanContext().SpanID(), spans["Cache.Get"][0].Parent().SpanID())
+	assert.Contains(t, spans["Cache.Get"][0].Attributes(), attribute.Bool(cacheHitAttributeKey, false))
+
+	require.Len(t, spans["Cache.Set"], 1)
+	assert.Equal(t, root.SpanContext().SpanID(), spans["Cache.Set"][0].Parent().SpanID())
+
+	// Each provider attempt has a span, failed attempts recording their error.
+	require.Len(t, spans["Provider.Query"], 2)
+	for _, span := range spans["Provider.Query"] {
+		assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	assert.Equal(t, codes.Error, spans["Provider.Query"][0].Status().Code)
+	assert.Contains(t, spans["Provider.Query"][0].Attributes(), attribute.String(providerAttributeKey, "errProvider"))
+	assert.Equal(t, codes.Unset, spans["Provider.Query"][1].Status().Code)
+	assert.Contains(t, spans["Provider.Query"][1].Attributes(), attribute.String(providerAttributeKey, "goodProvider"))
+}
@@ -0,0 +1,105 @@
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// conn is a connection to Redis.
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	w       *bufio.Writer
+}
+
+// do sends a command to Redis, returning the reply (see readReply). The command must complete
+// by deadline.
+func (c *conn) do(deadline time.Time, args ...[]byte) (interface{}, error) {
+	if err := c.netConn.SetDeadline(deadline); err != nil {
+		return nil, errors.Wrap(err, "rediscache: set deadline")
+	}
+
+	if err := writeCommand(c.w, args...); err != nil {
+		return nil, errors.Wrap(err, "rediscache: write command")
+	}
+
+	return readReply(c.r)
+}
+
+// pool is a pool of idle connections to Redis, safe for concurrent access. Connections are
+// dialed as needed, with at most size of them kept idle for reuse.
+type pool struct {
+	address     string
+	password    string
+	dialTimeout time.Duration
+	idle        chan *conn
+}
+
+// newPool creates a new pool of connections to the Redis server at address. If password is
+// set, connections are authenticated with it.
+func newPool(address, password string, size int, dialTimeout time.Duration) *pool {
+	return &pool{
+		address:     address,
+		password:    password,
+		dialTimeout: dialTimeout,
+		idle:        make(chan *conn, size),
+	}
+}
+
+// get returns an idle connection, or dials a new one if there are none.
+func (p *pool) get(ctx context.Context, deadline time.Time) (*conn, error) {
+	select {
+	case c := <-p.idle:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: p.dialTimeout}
+
+	netConn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return nil, errors.Wrap(err, "rediscache: dial")
+	}
+
+	c := &conn{netConn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if p.password != "" {
+		if _, err := c.do(deadline, []byte("AUTH"), []byte(p.password)); err != nil {
+			netConn.Close()
+
+			return nil, errors.Wrap(err, "rediscache: authenticate")
+		}
+	}
+
+	return c, nil
+}
+
+// put returns c to the pool once it's no longer used. A connection that is broken (having
+// failed part way through a command) or doesn't fit in the pool is closed.
+func (p *pool) put(c *conn, broken bool) {
+	if !broken {
+		select {
+		case p.idle <- c:
+			return
+		default:
+		}
+	}
+
+	c.netConn.Close()
+}
+
+// close closes all idle connections.
+func (p *pool) close() {
+	for {
+		select {
+		case c := <-p.idle:
+			c.netConn.Close()
+		default:
+			return
+		}
+	}
+}
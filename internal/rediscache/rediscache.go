@@ -0,0 +1,362 @@
+// Package rediscache contains a cache stored in Redis, such that it can be shared by multiple
+// replicas of a service.
+package rediscache
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/byatesrae/weather/internal/memorycache"
+	"github.com/byatesrae/weather/internal/platform/nooplogr"
+)
+
+// Cache is used to store & retrieve values while Redis is unreachable.
+type Cache interface {
+	Get(ctx context.Context, key interface{}) (interface{}, time.Time, error)
+	Set(ctx context.Context, key, val interface{}, expiry time.Time) error
+}
+
+// NewOptions are the options for the [New] function.
+type NewOptions struct {
+	password             string
+	keyPrefix            string
+	poolSize             int
+	dialTimeout          time.Duration
+	timeout              time.Duration
+	staleRetention       time.Duration
+	retryInterval        time.Duration
+	fallback             Cache
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+}
+
+// WithPassword sets the password connections to Redis are authenticated with.
+func WithPassword(password string) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.password = password
+	}
+}
+
+// WithKeyPrefix sets the prefix of all keys stored in Redis, to avoid clashes with other
+// users of the same Redis server.
+func WithKeyPrefix(keyPrefix string) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.keyPrefix = keyPrefix
+	}
+}
+
+// WithPoolSize sets the maximum number of idle connections kept for reuse.
+func WithPoolSize(poolSize int) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.poolSize = poolSize
+	}
+}
+
+// WithDialTimeout sets the timeout for connecting to Redis.
+func WithDialTimeout(dialTimeout time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.dialTimeout = dialTimeout
+	}
+}
+
+// WithTimeout sets the timeout for a single Get or Set, unless the context passed to it has
+// an earlier deadline.
+func WithTimeout(timeout time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithStaleRetention sets how long values are kept in Redis after their expiry, during which
+// they may still be served stale.
+func WithStaleRetention(staleRetention time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.staleRetention = staleRetention
+	}
+}
+
+// WithRetryInterval sets how long Redis is skipped for, using the fallback cache instead,
+// once found to be unreachable.
+func WithRetryInterval(retryInterval time.Duration) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.retryInterval = retryInterval
+	}
+}
+
+// WithFallback sets the cache used while Redis is unreachable. Defaults to a
+// [memorycache.MemoryCache].
+func WithFallback(fallback Cache) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.fallback = fallback
+	}
+}
+
+// WithGetLoggerFromContext sets a function used to retrieve a [logr.Logger] from
+// the context.
+func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) logr.Logger) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.getLoggerFromContext = getLoggerFromContext
+	}
+}
+
+// RedisCache is a cache stored in Redis, that is safe for concurrent access. Keys must be a
+// string or [fmt.Stringer] & values must be []byte or implement [encoding.BinaryMarshaler].
+// Values are always retrieved as []byte.
+//
+// Values are also set in a fallback cache, which is used in place of Redis while it's
+// unreachable.
+type RedisCache struct {
+	pool                 *pool
+	keyPrefix            string
+	timeout              time.Duration
+	staleRetention       time.Duration
+	retryInterval        time.Duration
+	fallback             Cache
+	getLoggerFromContext func(ctx context.Context) logr.Logger
+
+	// Redis is skipped until this time, having been found to be unreachable.
+	unreachableUntil time.Time
+	m                sync.Mutex
+}
+
+// New creates a new [RedisCache] for the Redis server at address (e.g "localhost:6379").
+// Connections are made as needed, so Redis doesn't need to be reachable yet.
+func New(address string, overrides ...func(o *NewOptions)) *RedisCache {
+	noopLogger := nooplogr.New()
+
+	options := NewOptions{
+		keyPrefix:      "weather:",
+		poolSize:       10,
+		dialTimeout:    time.Millisecond * 500,
+		timeout:        time.Millisecond * 500,
+		staleRetention: time.Hour,
+		retryInterval:  time.Second * 5,
+		fallback:       memorycache.New(),
+		getLoggerFromContext: func(ctx context.Context) logr.Logger {
+			return noopLogger
+		},
+	}
+
+	for _, override := range overrides {
+		override(&options)
+	}
+
+	return &RedisCache{
+		pool:                 newPool(address, options.password, options.poolSize, options.dialTimeout),
+		keyPrefix:            options.keyPrefix,
+		timeout:              options.timeout,
+		staleRetention:       options.staleRetention,
+		retryInterval:        options.retryInterval,
+		fallback:             options.fallback,
+		getLoggerFromContext: options.getLoggerFromContext,
+	}
+}
+
+// Get retrieves a value from cache as well as the time it expires.
+func (c *RedisCache) Get(ctx context.Context, key interface{}) (interface{}, time.Time, error) {
+	name, err := c.keyName(key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if c.isUnreachable() {
+		return c.fallback.Get(ctx, key)
+	}
+
+	reply, err := c.do(ctx, []byte("GET"), []byte(name))
+	if err != nil {
+		if c.checkUnreachable(ctx, err) {
+			return c.fallback.Get(ctx, key)
+		}
+
+		return nil, time.Time{}, errors.Wrap(err, "rediscache: get")
+	}
+
+	if reply == nil {
+		return nil, time.Time{}, nil
+	}
+
+	record, ok := reply.([]byte)
+	if !ok {
+		return nil, time.Time{}, errors.Errorf("rediscache: get: unexpected reply %T", reply)
+	}
+
+	val, expiry, err := decodeRecord(record)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return val, expiry, nil
+}
+
+// Set will set a value to be cached as well as an expiry. The value is evicted from Redis
+// once the stale retention (see [WithStaleRetention]) has passed since expiry.
+func (c *RedisCache) Set(ctx context.Context, key, val interface{}, expiry time.Time) error {
+	name, err := c.keyName(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalValue(val)
+	if err != nil {
+		return err
+	}
+
+	// The fallback is always kept up to date, should Redis become unreachable.
+	if err := c.fallback.Set(ctx, key, data, expiry); err != nil {
+		return errors.Wrap(err, "rediscache: set fallback")
+	}
+
+	ttl := time.Until(expiry) + c.staleRetention
+	if ttl <= 0 || c.isUnreachable() {
+		return nil
+	}
+
+	ttlMillis := ttl.Milliseconds()
+	if ttlMillis < 1 {
+		ttlMillis = 1
+	}
+
+	_, err = c.do(
+		ctx,
+		[]byte("SET"),
+		[]byte(name),
+		encodeRecord(data, expiry),
+		[]byte("PX"),
+		[]byte(strconv.FormatInt(ttlMillis, 10)),
+	)
+	if err != nil && !c.checkUnreachable(ctx, err) {
+		return errors.Wrap(err, "rediscache: set")
+	}
+
+	return nil
+}
+
+// Ping checks Redis is reachable.
+func (c *RedisCache) Ping(ctx context.Context) error {
+	if _, err := c.do(ctx, []byte("PING")); err != nil {
+		return errors.Wrap(err, "rediscache: ping")
+	}
+
+	return nil
+}
+
+// Close closes all idle connections to Redis.
+func (c *RedisCache) Close() error {
+	c.pool.close()
+
+	return nil
+}
+
+// do sends a command to Redis using a pooled connection.
+func (c *RedisCache) do(ctx context.Context, args ...[]byte) (interface{}, error) {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	conn, err := c.pool.get(ctx, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(deadline, args...)
+
+	// An error reply leaves the connection in a usable state, unlike any other error.
+	c.pool.put(conn, err != nil && !errors.As(err, new(Error)))
+
+	return reply, err
+}
+
+// checkUnreachable reports whether err means Redis is unreachable, in which case Redis is
+// skipped until the retry interval has passed. Only network errors count, not the caller
+// giving up (ctx being done) or an unexpected reply, such that a single client disconnecting
+// doesn't switch every request to the fallback.
+func (c *RedisCache) checkUnreachable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || !errors.As(err, new(net.Error)) {
+		return false
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.unreachableUntil = time.Now().Add(c.retryInterval)
+
+	c.getLoggerFromContext(ctx).Error(err, "Redis is unreachable, falling back to the in-memory cache.", "retryIn", c.retryInterval)
+
+	return true
+}
+
+// isUnreachable reports whether Redis is being skipped, having been found to be unreachable.
+func (c *RedisCache) isUnreachable() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return time.Now().Before(c.unreachableUntil)
+}
+
+// keyName returns the name key is stored under in Redis.
+func (c *RedisCache) keyName(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case string:
+		return c.keyPrefix + k, nil
+	case fmt.Stringer:
+		return c.keyPrefix + k.String(), nil
+	default:
+		return "", errors.Errorf("rediscache: unsupported key type %T", key)
+	}
+}
+
+// marshalValue returns the serialized form of val.
+func marshalValue(val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case []byte:
+		return v, nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return nil, errors.Wrap(err, "rediscache: marshal value")
+		}
+
+		return data, nil
+	default:
+		return nil, errors.Errorf("rediscache: unsupported value type %T", val)
+	}
+}
+
+// encodeRecord encodes data & its expiry as they are stored in Redis, being the expiry (in
+// unix nanoseconds, or 0 if not set) as 8 big endian bytes followed by data.
+func encodeRecord(data []byte, expiry time.Time) []byte {
+	var expiryNanos int64
+	if !expiry.IsZero() {
+		expiryNanos = expiry.UnixNano()
+	}
+
+	record := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(record, uint64(expiryNanos))
+	copy(record[8:], data)
+
+	return record
+}
+
+// decodeRecord decodes a record encoded by encodeRecord.
+func decodeRecord(record []byte) ([]byte, time.Time, error) {
+	if len(record) < 8 {
+		return nil, time.Time{}, errors.New("rediscache: record is too short")
+	}
+
+	var expiry time.Time
+	if expiryNanos := int64(binary.BigEndian.Uint64(record)); expiryNanos != 0 {
+		expiry = time.Unix(0, expiryNanos).UTC()
+	}
+
+	return record[8:], expiry, nil
+}
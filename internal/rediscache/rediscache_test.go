@@ -0,0 +1,218 @@
+package rediscache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stringerKey is a key implementing fmt.Stringer.
+type stringerKey struct{ name string }
+
+func (k stringerKey) String() string {
+	return "stringer:" + k.name
+}
+
+// binaryValue is a value implementing encoding.BinaryMarshaler.
+type binaryValue struct{ data string }
+
+func (v binaryValue) MarshalBinary() ([]byte, error) {
+	return []byte(v.data), nil
+}
+
+func TestRedisCacheGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_value_for_key", func(t *testing.T) {
+		t.Parallel()
+
+		server := startRESPServer(t, "")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		c := New(server.address())
+		t.Cleanup(func() { c.Close() })
+
+		val, expiry, err := c.Get(ctx, "Test123")
+		assert.Nil(t, val)
+		assert.True(t, expiry.IsZero())
+		assert.Nil(t, err)
+	})
+
+	t.Run("value_retrieved", func(t *testing.T) {
+		t.Parallel()
+
+		server := startRESPServer(t, "secret")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		c := New(server.address(), WithPassword("secret"), WithKeyPrefix("test:"), WithStaleRetention(time.Minute))
+		t.Cleanup(func() { c.Close() })
+
+		expectedExpiry := time.Now().Add(time.Minute).UTC().Round(0)
+
+		for _, tc := range []struct {
+			giveKey     interface{}
+			giveValue   interface{}
+			expectedKey string
+			expected    []byte
+		}{
+			{giveKey: "Test123", giveValue: []byte("456"), expectedKey: "test:Test123", expected: []byte("456")},
+			{giveKey: stringerKey{name: "Test123"}, giveValue: binaryValue{data: "789"}, expectedKey: "test:stringer:Test123", expected: []byte("789")},
+		} {
+			err := c.Set(ctx, tc.giveKey, tc.giveValue, expectedExpiry)
+			require.NoError(t, err)
+
+			actualValue, actualExpiry, err := c.Get(ctx, tc.giveKey)
+			assert.Equal(t, tc.expected, actualValue)
+			assert.Equal(t, expectedExpiry, actualExpiry)
+			assert.Nil(t, err)
+		}
+
+		commands := server.receivedCommands()
+		require.Len(t, commands, 4)
+		assert.Equal(t, []string{"SET", "test:Test123"}, commands[0][:2])
+		assert.Equal(t, "PX", commands[0][3])
+
+		ttlMillis, err := strconv.Atoi(commands[0][4])
+		require.NoError(t, err)
+		assert.InDelta(t, (time.Minute * 2).Milliseconds(), ttlMillis, 1000, "TTL includes the stale retention")
+
+		assert.Equal(t, []string{"GET", "test:stringer:Test123"}, commands[3])
+
+		assert.Equal(t, 1, server.connectionCount(), "connections are reused")
+	})
+}
+
+func TestRedisCacheErr(t *testing.T) {
+	t.Parallel()
+
+	server := startRESPServer(t, "secret")
+
+	for _, tc := range []struct {
+		name        string
+		giveOptions []func(o *NewOptions)
+		giveKey     interface{}
+		giveValue   interface{}
+		expectedErr string
+	}{
+		{
+			name:        "wrong_password",
+			giveOptions: []func(o *NewOptions){WithPassword("wrong")},
+			giveKey:     "Test123",
+			giveValue:   []byte("456"),
+			expectedErr: "rediscache: set: rediscache: authenticate: rediscache: redis error: WRONGPASS invalid password",
+		},
+		{
+			name:        "unsupported_key",
+			giveOptions: []func(o *NewOptions){WithPassword("secret")},
+			giveKey:     123,
+			giveValue:   []byte("456"),
+			expectedErr: "rediscache: unsupported key type int",
+		},
+		{
+			name:        "unsupported_value",
+			giveOptions: []func(o *NewOptions){WithPassword("secret")},
+			giveKey:     "Test123",
+			giveValue:   456,
+			expectedErr: "rediscache: unsupported value type int",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			c := New(server.address(), tc.giveOptions...)
+			t.Cleanup(func() { c.Close() })
+
+			err := c.Set(ctx, tc.giveKey, tc.giveValue, time.Now().Add(time.Minute))
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := startRESPServer(t, "")
+	server.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := New(server.address(), WithRetryInterval(time.Minute))
+	t.Cleanup(func() { c.Close() })
+
+	expectedExpiry := time.Now().Add(time.Minute)
+
+	err := c.Set(ctx, "Test123", []byte("456"), expectedExpiry)
+	assert.NoError(t, err)
+
+	actualValue, actualExpiry, err := c.Get(ctx, "Test123")
+	assert.Equal(t, []byte("456"), actualValue)
+	assert.Equal(t, expectedExpiry, actualExpiry)
+	assert.Nil(t, err)
+
+	assert.True(t, c.isUnreachable(), "redis is skipped until the retry interval passes")
+}
+
+func TestRedisCacheContextDone(t *testing.T) {
+	t.Parallel()
+
+	server := startRESPServer(t, "")
+
+	c := New(server.address(), WithRetryInterval(time.Minute))
+	t.Cleanup(func() { c.Close() })
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := c.Get(cancelledCtx, "Test123")
+	assert.Error(t, err)
+	assert.False(t, c.isUnreachable(), "redis is still used after the caller gives up")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	err = c.Set(ctx, "Test123", []byte("456"), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, server.receivedCommands(), 1, "set is sent to redis")
+}
+
+func TestRedisCachePing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reachable", func(t *testing.T) {
+		t.Parallel()
+
+		server := startRESPServer(t, "")
+
+		c := New(server.address())
+		t.Cleanup(func() { c.Close() })
+
+		assert.NoError(t, c.Ping(context.Background()))
+		assert.Equal(t, [][]string{{"PING"}}, server.receivedCommands())
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		server := startRESPServer(t, "")
+		server.close()
+
+		c := New(server.address())
+		t.Cleanup(func() { c.Close() })
+
+		assert.Error(t, c.Ping(context.Background()))
+	})
+}
@@ -0,0 +1,121 @@
+package rediscache
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Error is an error reply from Redis, e.g "WRONGTYPE Operation against a key holding the
+// wrong kind of value".
+type Error string
+
+// Error implements error.
+func (e Error) Error() string {
+	return "rediscache: redis error: " + string(e)
+}
+
+// writeCommand writes args as a RESP array of bulk strings to w, as Redis expects commands.
+func writeCommand(w *bufio.Writer, args ...[]byte) error {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(len(args)))
+	w.WriteString("\r\n")
+
+	for _, arg := range args {
+		w.WriteByte('$')
+		w.WriteString(strconv.Itoa(len(arg)))
+		w.WriteString("\r\n")
+		w.Write(arg)
+		w.WriteString("\r\n")
+	}
+
+	return w.Flush() // Any earlier write errors are returned here.
+}
+
+// readReply reads a RESP reply from r. Depending on the type of reply, it returns a string
+// (simple string), int64 (integer), []byte (bulk string), []interface{} (array) or nil (null
+// bulk string or array). An error reply is returned as an [Error].
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, errors.New("rediscache: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "rediscache: parse integer reply")
+		}
+
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errors.Wrap(err, "rediscache: parse bulk string length")
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, n+2) // Including the trailing "\r\n".
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, errors.Wrap(err, "rediscache: read bulk string")
+		}
+
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errors.Wrap(err, "rediscache: parse array length")
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		elements := make([]interface{}, n)
+		for i := range elements {
+			element, err := readReply(r)
+			if err != nil {
+				var redisErr Error
+				if !errors.As(err, &redisErr) {
+					return nil, err
+				}
+
+				element = redisErr
+			}
+
+			elements[i] = element
+		}
+
+		return elements, nil
+	default:
+		return nil, errors.Errorf("rediscache: unknown reply type %q", line[0])
+	}
+}
+
+// readLine reads a line terminated by "\r\n" from r, excluding the terminator.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, errors.Wrap(err, "rediscache: read reply")
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errors.New("rediscache: malformed reply line")
+	}
+
+	return line[:len(line)-2], nil
+}
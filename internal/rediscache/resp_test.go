@@ -0,0 +1,93 @@
+package rediscache
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCommand(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := writeCommand(bufio.NewWriter(&buf), []byte("SET"), []byte("key"), []byte("a\r\nb"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$4\r\na\r\nb\r\n", buf.String())
+}
+
+func TestReadReply(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		give        string
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "simple_string",
+			give:     "+OK\r\n",
+			expected: "OK",
+		},
+		{
+			name:        "error",
+			give:        "-ERR unknown command\r\n",
+			expectedErr: "rediscache: redis error: ERR unknown command",
+		},
+		{
+			name:     "integer",
+			give:     ":42\r\n",
+			expected: int64(42),
+		},
+		{
+			name:     "bulk_string",
+			give:     "$4\r\na\r\nb\r\n",
+			expected: []byte("a\r\nb"),
+		},
+		{
+			name:     "null_bulk_string",
+			give:     "$-1\r\n",
+			expected: nil,
+		},
+		{
+			name:     "array",
+			give:     "*3\r\n$3\r\nfoo\r\n:1\r\n-ERR oops\r\n",
+			expected: []interface{}{[]byte("foo"), int64(1), Error("ERR oops")},
+		},
+		{
+			name:        "unknown_type",
+			give:        "?\r\n",
+			expectedErr: "rediscache: unknown reply type '?'",
+		},
+		{
+			name:        "malformed",
+			give:        "+OK\n",
+			expectedErr: "rediscache: malformed reply line",
+		},
+		{
+			name:        "truncated",
+			give:        "$10\r\nabc",
+			expectedErr: "rediscache: read bulk string: unexpected EOF",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := readReply(bufio.NewReader(strings.NewReader(tc.give)))
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package rediscache
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// respServer is an in-process stand-in for Redis, supporting just enough of the RESP protocol
+// (AUTH, GET & SET with PX) to test against.
+type respServer struct {
+	listener net.Listener
+	password string
+
+	m           sync.Mutex
+	values      map[string][]byte
+	expiries    map[string]time.Time
+	commands    [][]string
+	connections int
+}
+
+// startRESPServer starts a respServer, requiring clients to authenticate with password if set.
+// It's stopped when the test completes.
+func startRESPServer(t *testing.T, password string) *respServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &respServer{
+		listener: listener,
+		password: password,
+		values:   make(map[string][]byte),
+		expiries: make(map[string]time.Time),
+	}
+
+	go s.serve()
+
+	t.Cleanup(s.close)
+
+	return s
+}
+
+// address returns the address the server is listening on.
+func (s *respServer) address() string {
+	return s.listener.Addr().String()
+}
+
+// close stops the server listening.
+func (s *respServer) close() {
+	s.listener.Close()
+}
+
+// connectionCount returns the number of connections accepted.
+func (s *respServer) connectionCount() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.connections
+}
+
+// receivedCommands returns the commands received, excluding AUTH.
+func (s *respServer) receivedCommands() [][]string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([][]string(nil), s.commands...)
+}
+
+func (s *respServer) serve() {
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.m.Lock()
+		s.connections++
+		s.m.Unlock()
+
+		go s.serveConn(netConn)
+	}
+}
+
+func (s *respServer) serveConn(netConn net.Conn) {
+	defer netConn.Close()
+
+	r := bufio.NewReader(netConn)
+	w := bufio.NewWriter(netConn)
+	authenticated := s.password == ""
+
+	for {
+		request, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		elements, _ := request.([]interface{})
+
+		args := make([]string, len(elements))
+		for i, element := range elements {
+			arg, _ := element.([]byte)
+			args[i] = string(arg)
+		}
+
+		if len(args) == 0 {
+			w.WriteString("-ERR empty command\r\n")
+		} else if strings.ToUpper(args[0]) == "AUTH" {
+			if len(args) == 2 && args[1] == s.password {
+				authenticated = true
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid password\r\n")
+			}
+		} else if !authenticated {
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		} else {
+			w.WriteString(s.handle(args))
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// handle handles a command (other than AUTH), returning the RESP encoded reply.
+func (s *respServer) handle(args []string) string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.commands = append(s.commands, args)
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := s.values[args[1]]
+		if !ok || (!s.expiries[args[1]].IsZero() && time.Now().After(s.expiries[args[1]])) {
+			return "$-1\r\n"
+		}
+
+		return "$" + strconv.Itoa(len(value)) + "\r\n" + string(value) + "\r\n"
+	case "SET":
+		s.values[args[1]] = []byte(args[2])
+		delete(s.expiries, args[1])
+
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			millis, err := strconv.Atoi(args[4])
+			if err != nil {
+				return "-ERR value is not an integer or out of range\r\n"
+			}
+
+			s.expiries[args[1]] = time.Now().Add(time.Duration(millis) * time.Millisecond)
+		}
+
+		return "+OK\r\n"
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
@@ -2,18 +2,38 @@ package weatherstack
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/byatesrae/weather/internal/platform/nooplogr"
 )
 
+// tracerName is the name of the tracer used by the [Client], see [WithTracerProvider].
+const tracerName = "github.com/byatesrae/weather/internal/weatherstack"
+
+// apiErrorCodeAttributeKey is the span attribute holding the code of an [APIError].
+const apiErrorCodeAttributeKey = "weatherstack.error.code"
+
+// maxBodySize is the maximum size of a response body read from the API. Its responses are a
+// few KB, so a larger body is an error rather than something worth buffering.
+const maxBodySize = 1 << 20
+
 // NewOptions are the options for the [New] function.
 type NewOptions struct {
 	client               HTTPClient
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracerProvider       trace.TracerProvider
 }
 
 // NewWithHTTPClient sets the HTTPClient in the [New] function.
@@ -31,12 +51,20 @@ func WithGetLoggerFromContext(getLoggerFromContext func(ctx context.Context) log
 	}
 }
 
+// WithTracerProvider sets the provider of the tracer used to trace requests to the API.
+func WithTracerProvider(tracerProvider trace.TracerProvider) func(o *NewOptions) {
+	return func(o *NewOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
 // Client is used to interact with the Weatherstack API.
 type Client struct {
 	client               HTTPClient
 	endpointURL          string
 	accessKey            string
 	getLoggerFromContext func(ctx context.Context) logr.Logger
+	tracer               trace.Tracer
 }
 
 // New creates a new [Client].
@@ -48,6 +76,7 @@ func New(endpointURL, accessKey string, optionOverrides ...func(*NewOptions)) *C
 		getLoggerFromContext: func(ctx context.Context) logr.Logger {
 			return noopLogger
 		},
+		tracerProvider: trace.NewNoopTracerProvider(),
 	}
 
 	for _, optionOverride := range optionOverrides {
@@ -59,5 +88,119 @@ func New(endpointURL, accessKey string, optionOverrides ...func(*NewOptions)) *C
 		accessKey:            accessKey,
 		endpointURL:          endpointURL,
 		getLoggerFromContext: options.getLoggerFromContext,
+		tracer:               options.tracerProvider.Tracer(tracerName),
+	}
+}
+
+// requiredFields are the fields of a response that must be present, being decoded from the
+// response body & validated by get.
+type requiredFields interface {
+	validate() error
+}
+
+// get sends a GET request to the endpoint at path with query (merged with the parameters
+// common to all requests), decoding a successful response body into apiResponse & required
+// (if not nil). An unsuccessful response body is returned as an [*APIError].
+func (c *Client) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	apiResponse interface{},
+	required requiredFields,
+) (err error) {
+	logger := c.getLoggerFromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", c.endpointURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "weatherstack: create request")
+	}
+
+	q := req.URL.Query()
+	q.Add("access_key", c.accessKey)
+	q.Add("units", "m")
+
+	for k, v := range query {
+		q[k] = v
+	}
+
+	req.URL.RawQuery = q.Encode()
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		"weatherstack GET /"+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(http.MethodGet),
+			semconv.HTTPURLKey.String(endpoint), // Without the query, which holds credentials.
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}()
+
+	req = req.WithContext(ctx)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "weatherstack: execute request")
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+
+	if res.Body != nil {
+		defer func() {
+			err := res.Body.Close()
+			if err != nil {
+				logger.Error(err, "Error closing response body.")
+			}
+		}()
 	}
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("weatherstack: unexpected response status code %v", res.StatusCode)
+	}
+
+	if res.Body != nil {
+		// One byte past the limit is read to tell a body at the limit from one over it.
+		body, err := io.ReadAll(io.LimitReader(res.Body, maxBodySize+1))
+		if err != nil {
+			return errors.Wrap(err, "weatherstack: read body")
+		}
+
+		if len(body) > maxBodySize {
+			return errors.Errorf("weatherstack: read body: larger than %d bytes", maxBodySize)
+		}
+
+		// Unsuccessful responses have status code 200 too, so are told apart by their body.
+		var errorResponse apiErrorResponse
+		if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Error != nil {
+			span.SetAttributes(attribute.Int(apiErrorCodeAttributeKey, errorResponse.Error.Code))
+
+			return errorResponse.Error
+		}
+
+		if err := json.Unmarshal(body, apiResponse); err != nil {
+			return errors.Wrap(err, "weatherstack: decode body")
+		}
+
+		if required != nil {
+			if err := json.Unmarshal(body, required); err != nil {
+				return errors.Wrap(err, "weatherstack: decode body")
+			}
+		}
+	}
+
+	// Without a body, every required field is missing.
+	if required != nil {
+		return required.validate()
+	}
+
+	return nil
 }
@@ -2,65 +2,97 @@ package weatherstack
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
 
 // CurrentSuccess is a successful response from the Weatherstack API "Current" endpoint.
 type CurrentSuccess struct {
-	Current CurrentWeather `json:"current"`
+	Location Location       `json:"location"`
+	Current  CurrentWeather `json:"current"`
+}
+
+// Location is part of a successful response from the Weatherstack API, describing the location
+// queried.
+type Location struct {
+	Name           string `json:"name"`
+	UTCOffset      string `json:"utc_offset"`                // The location's shift from UTC in hours, e.g "10.0".
+	LocaltimeEpoch *int64 `json:"localtime_epoch,omitempty"` // The current time at the location, unix time in seconds.
 }
 
 // CurrentWeather is part of a successful response from the Weatherstack API "Current" endpoint.
+//
+// Pointer fields are optional and are nil when not included in the response.
 type CurrentWeather struct {
-	Temperature int `json:"temperature"` // The location temperature in degrees celsius.
-	WindSpeed   int `json:"wind_speed"`  // The location windspeed in km/h.
+	Temperature         int      `json:"temperature"`                    // The location temperature in degrees celsius.
+	WindSpeed           int      `json:"wind_speed"`                     // The location windspeed in km/h.
+	WindDegree          *float64 `json:"wind_degree,omitempty"`          // The direction the wind is blowing from in degrees (meteorological).
+	WeatherCode         *int     `json:"weather_code,omitempty"`         // The weather condition code, see https://weatherstack.com/site_resources/weatherstack-weather-condition-codes.zip.
+	WeatherDescriptions []string `json:"weather_descriptions,omitempty"` // Descriptions of the weather condition, e.g "Partly cloudy".
+	Pressure            *float64 `json:"pressure,omitempty"`             // The atmospheric pressure in millibar (hPa).
+	Precip              *float64 `json:"precip,omitempty"`               // The precipitation in mm.
+	Humidity            *float64 `json:"humidity,omitempty"`             // The relative humidity as a percentage.
+	CloudCover          *float64 `json:"cloudcover,omitempty"`           // The cloud cover as a percentage.
+	FeelsLike           *float64 `json:"feelslike,omitempty"`            // The apparent temperature in degrees celsius.
+	Visibility          *float64 `json:"visibility,omitempty"`           // The visibility in km.
+	ObservationTime     string   `json:"observation_time,omitempty"`     // The UTC time of day the data was collected, e.g "12:14 PM".
 }
 
-// CurrentByCityName returns a summary of the weather for a city.
-func (c *Client) CurrentByCityName(ctx context.Context, cityName string) (*CurrentSuccess, error) {
-	logger := c.getLoggerFromContext(ctx)
+// currentRequiredFields are the fields of a response from the "Current" endpoint that must be
+// present. Without them, the weather would be zeroes.
+type currentRequiredFields struct {
+	Current *struct {
+		Temperature *int `json:"temperature"`
+		WindSpeed   *int `json:"wind_speed"`
+	} `json:"current"`
+}
 
-	if cityName == "" {
-		return nil, errors.New("weatherstack: cityname is required")
+// validate returns an error if a required field is missing.
+func (f *currentRequiredFields) validate() error {
+	if f.Current == nil {
+		return errors.New("weatherstack: response has no current weather")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/current", c.endpointURL), http.NoBody)
-	if err != nil {
-		return nil, errors.Wrap(err, "weatherstack: create request")
+	if f.Current.Temperature == nil {
+		return errors.New("weatherstack: response has no current.temperature")
 	}
 
-	q := req.URL.Query()
-	q.Add("access_key", c.accessKey)
-	q.Add("query", cityName)
-	q.Add("units", "m")
-	req.URL.RawQuery = q.Encode()
-
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "weatherstack: execute request")
+	if f.Current.WindSpeed == nil {
+		return errors.New("weatherstack: response has no current.wind_speed")
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("weatherstack: unexpected response status code %v", res.StatusCode)
+	return nil
+}
+
+// CurrentByCityName returns a summary of the weather for a city.
+func (c *Client) CurrentByCityName(ctx context.Context, cityName string) (*CurrentSuccess, error) {
+	if cityName == "" {
+		return nil, errors.New("weatherstack: cityname is required")
 	}
 
+	return c.current(ctx, cityName)
+}
+
+// CurrentByCoordinates returns a summary of the weather for a latitude/longitude (in
+// decimal degrees).
+func (c *Client) CurrentByCoordinates(ctx context.Context, latitude, longitude float64) (*CurrentSuccess, error) {
+	return c.current(ctx, coordinatesQuery(latitude, longitude))
+}
+
+// current queries the "Current" endpoint with query, which should identify a location.
+// See https://weatherstack.com/documentation#query_parameter.
+func (c *Client) current(ctx context.Context, query string) (*CurrentSuccess, error) {
 	var apiResponse CurrentSuccess
-	if res.Body != nil {
-		defer func() {
-			err := res.Body.Close()
-			if err != nil {
-				logger.Error(err, "Error closing response body.")
-			}
-		}()
-
-		if err := json.NewDecoder(res.Body).Decode(&apiResponse); err != nil {
-			return nil, errors.Wrap(err, "weatherstack: decode body")
-		}
+	if err := c.get(ctx, "current", url.Values{"query": []string{query}}, &apiResponse, &currentRequiredFields{}); err != nil {
+		return nil, err
 	}
 
 	return &apiResponse, nil
 }
+
+// coordinatesQuery returns the "query" parameter value identifying a latitude/longitude.
+func coordinatesQuery(latitude, longitude float64) string {
+	return strconv.FormatFloat(latitude, 'f', -1, 64) + "," + strconv.FormatFloat(longitude, 'f', -1, 64)
+}
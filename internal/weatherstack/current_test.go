@@ -10,6 +10,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServiceCurrentByCityName(t *testing.T) {
@@ -71,6 +76,114 @@ func TestServiceCurrentByCityName(t *testing.T) {
 			expected:     nil,
 			expectedErr:  "weatherstack: decode body: json: cannot unmarshal string into Go value of type weatherstack.CurrentSuccess",
 		},
+		{
+			name: "api_error",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"success":false,"error":{"code":104,"type":"usage_limit_reached","info":"Test123"}}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: api error 104 (usage_limit_reached): Test123",
+		},
+		{
+			name: "missing_current",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"location":{"name":"Sydney"}}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: response has no current weather",
+		},
+		{
+			name: "null_current",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"location":{"name":"Sydney"},"current":null}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: response has no current weather",
+		},
+		{
+			name: "empty_current",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"location":{"name":"Sydney"},"current":{}}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: response has no current.temperature",
+		},
+		{
+			name: "missing_wind_speed",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader([]byte(`{"location":{"name":"Sydney"},"current":{"temperature":10}}`)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: response has no current.wind_speed",
+		},
+		{
+			name: "body_too_large",
+			withClient: New(
+				"",
+				"",
+				NewWithHTTPClient(&HTTPClientMock{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						r := io.NopCloser(bytes.NewReader(make([]byte, maxBodySize+1)))
+
+						return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+					},
+				}),
+			),
+			giveContext:  context.Background(),
+			giveCityName: "Sydney",
+			expected:     nil,
+			expectedErr:  "weatherstack: read body: larger than 1048576 bytes",
+		},
 		{
 			name: "http_client_error",
 			withClient: New(
@@ -162,4 +275,93 @@ func TestServiceCurrentByCityName(t *testing.T) {
 		assert.Nil(t, actualResult)
 		assert.ErrorIs(t, actualErr, ctx.Err())
 	})
+
+	t.Run("error_response_body_closed", func(t *testing.T) {
+		t.Parallel()
+
+		body := &closeRecorder{Reader: bytes.NewReader(nil)}
+
+		client := New("", "", NewWithHTTPClient(&HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusInternalServerError, Body: body}, nil
+			},
+		}))
+
+		actualResult, actualErr := client.CurrentByCityName(context.Background(), "ABC")
+		assert.Nil(t, actualResult)
+		assert.EqualError(t, actualErr, "weatherstack: unexpected response status code 500")
+		assert.True(t, body.closed, "body closed")
+	})
+}
+
+// closeRecorder is a response body recording whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+
+	return nil
+}
+
+func TestServiceCurrentByCoordinates(t *testing.T) {
+	t.Parallel()
+
+	dummyResult := CurrentSuccess{
+		Current: CurrentWeather{
+			WindSpeed:   123,
+			Temperature: 456,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := New("", "", NewWithHTTPClient(&HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "-33.87,151.21", req.URL.Query().Get("query"))
+
+			payload, err := json.Marshal(dummyResult)
+			assert.NoError(t, err)
+
+			r := io.NopCloser(bytes.NewReader(payload))
+
+			return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+		},
+	}))
+
+	actual, err := client.CurrentByCoordinates(ctx, -33.87, 151.21)
+	assert.NoError(t, err)
+	assert.Equal(t, &dummyResult, actual)
+}
+
+func TestServiceCurrentByCityNameTracing(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	client := New("https://example.com", "Test123",
+		NewWithHTTPClient(&HTTPClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.True(t, trace.SpanContextFromContext(req.Context()).IsValid(), "request context holds the span")
+
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			},
+		}),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	_, err := client.CurrentByCityName(context.Background(), "Sydney")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "weatherstack GET /current", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.url", "https://example.com/current"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusBadGateway))
+	}
 }
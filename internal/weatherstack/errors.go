@@ -0,0 +1,63 @@
+package weatherstack
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of [APIError], to be matched with errors.Is.
+var (
+	ErrInvalidKey      = errors.New("weatherstack: invalid access key")
+	ErrQuotaExceeded   = errors.New("weatherstack: quota exceeded")
+	ErrUnknownLocation = errors.New("weatherstack: unknown location")
+	ErrInvalidQuery    = errors.New("weatherstack: invalid query")
+)
+
+// The codes of the errors returned by the Weatherstack API that are classified, see
+// https://weatherstack.com/documentation#api_error_codes.
+const (
+	apiErrorCodeInvalidKey    = 101 // The access key is missing or invalid.
+	apiErrorCodeInactiveUser  = 102 // The account is inactive or blocked.
+	apiErrorCodeQuotaExceeded = 104 // The monthly request volume has been reached.
+	apiErrorCodeMissingQuery  = 601 // The query is missing or invalid.
+	apiErrorCodeNoResults     = 602 // The query didn't return any results.
+)
+
+// The generic request_failed (615) error isn't classified. Nothing documented tells a 615 for an
+// unknown location from any other failed request, so it's left as a provider failure.
+
+// APIError is an error returned by the Weatherstack API. Weatherstack returns errors with
+// status code 200 (OK), in a body of the form {"success": false, "error": {...}}.
+type APIError struct {
+	Code int    `json:"code"` // e.g 104, see https://weatherstack.com/documentation#api_error_codes.
+	Type string `json:"type"` // e.g "usage_limit_reached".
+	Info string `json:"info"` // A human readable description of the error.
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("weatherstack: api error %v (%s): %s", e.Code, e.Type, e.Info)
+}
+
+// Is reports whether the error is of the class target, one of [ErrInvalidKey],
+// [ErrQuotaExceeded], [ErrUnknownLocation] or [ErrInvalidQuery].
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidKey:
+		return e.Code == apiErrorCodeInvalidKey || e.Code == apiErrorCodeInactiveUser
+	case ErrQuotaExceeded:
+		return e.Code == apiErrorCodeQuotaExceeded
+	case ErrUnknownLocation:
+		return e.Code == apiErrorCodeNoResults
+	case ErrInvalidQuery:
+		return e.Code == apiErrorCodeMissingQuery
+	default:
+		return false
+	}
+}
+
+// apiErrorResponse is an unsuccessful response from the Weatherstack API.
+type apiErrorResponse struct {
+	Error *APIError `json:"error"`
+}
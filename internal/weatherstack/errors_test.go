@@ -0,0 +1,39 @@
+package weatherstack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		give     *APIError
+		expected error // Nil if the error isn't classified.
+	}{
+		{name: "invalid_access_key", give: &APIError{Code: 101, Type: "invalid_access_key"}, expected: ErrInvalidKey},
+		{name: "inactive_user", give: &APIError{Code: 102, Type: "inactive_user"}, expected: ErrInvalidKey},
+		{name: "usage_limit_reached", give: &APIError{Code: 104, Type: "usage_limit_reached"}, expected: ErrQuotaExceeded},
+		{name: "request_failed", give: &APIError{Code: 615, Type: "request_failed", Info: "Your API request failed. Please try again or contact support."}},
+		{name: "missing_query", give: &APIError{Code: 601, Type: "missing_query"}, expected: ErrInvalidQuery},
+		{name: "no_results", give: &APIError{Code: 602, Type: "no_results"}, expected: ErrUnknownLocation},
+		{name: "invalid_language", give: &APIError{Code: 605, Type: "invalid_language"}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("Test123: %w", tc.give)
+
+			for _, class := range []error{ErrInvalidKey, ErrQuotaExceeded, ErrUnknownLocation, ErrInvalidQuery} {
+				assert.Equal(t, class == tc.expected, errors.Is(wrapped, class), class.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package weatherstack
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ForecastSuccess is a successful response from the Weatherstack API "Forecast" endpoint.
+type ForecastSuccess struct {
+	Location Location               `json:"location"`
+	Forecast map[string]ForecastDay `json:"forecast"` // Keyed by date (YYYY-MM-DD).
+}
+
+// ForecastDay is part of a successful response from the Weatherstack API "Forecast" endpoint.
+// It is the forecast for one day.
+type ForecastDay struct {
+	Date    string         `json:"date"`    // The day in the location's timezone (YYYY-MM-DD).
+	MinTemp int            `json:"mintemp"` // The minimum temperature in degrees celsius.
+	MaxTemp int            `json:"maxtemp"` // The maximum temperature in degrees celsius.
+	AvgTemp int            `json:"avgtemp"` // The average temperature in degrees celsius.
+	Hourly  []ForecastHour `json:"hourly"`
+}
+
+// ForecastHour is part of a successful response from the Weatherstack API "Forecast" endpoint.
+// It is the forecast for one interval of a day, with the same datapoints as the "Current" endpoint.
+type ForecastHour struct {
+	CurrentWeather
+
+	Time string `json:"time"` // The start of the interval in the location's timezone (HMM, e.g "0", "300", "1500").
+}
+
+// forecastRequiredFields are the fields of a response from the "Forecast" endpoint that must be
+// present. Without them, the forecast would have no days.
+type forecastRequiredFields struct {
+	Forecast map[string]struct{} `json:"forecast"`
+}
+
+// validate returns an error if a required field is missing.
+func (f *forecastRequiredFields) validate() error {
+	if len(f.Forecast) == 0 {
+		return errors.New("weatherstack: response has no forecast")
+	}
+
+	return nil
+}
+
+// ForecastByCityName returns a forecast for a city covering days days, in intervals of
+// intervalHours hours (one of 1, 3, 6, 12 or 24).
+func (c *Client) ForecastByCityName(ctx context.Context, cityName string, days, intervalHours int) (*ForecastSuccess, error) {
+	if cityName == "" {
+		return nil, errors.New("weatherstack: cityname is required")
+	}
+
+	if days < 1 {
+		return nil, errors.New("weatherstack: days must be at least 1")
+	}
+
+	switch intervalHours {
+	case 1, 3, 6, 12, 24:
+	default:
+		return nil, errors.Errorf("weatherstack: unsupported interval %v", intervalHours)
+	}
+
+	query := url.Values{
+		"query":         []string{cityName},
+		"forecast_days": []string{strconv.Itoa(days)},
+		"hourly":        []string{"1"},
+		"interval":      []string{strconv.Itoa(intervalHours)},
+	}
+
+	var apiResponse ForecastSuccess
+	if err := c.get(ctx, "forecast", query, &apiResponse, &forecastRequiredFields{}); err != nil {
+		return nil, err
+	}
+
+	return &apiResponse, nil
+}
@@ -0,0 +1,124 @@
+package weatherstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceForecastByCityName(t *testing.T) {
+	t.Parallel()
+
+	dummyResult := ForecastSuccess{
+		Location: Location{Name: "Sydney", UTCOffset: "10.0"},
+		Forecast: map[string]ForecastDay{
+			"2020-11-11": {
+				Date:    "2020-11-11",
+				MinTemp: 1,
+				MaxTemp: 3,
+				AvgTemp: 2,
+				Hourly:  []ForecastHour{{Time: "300", CurrentWeather: CurrentWeather{Temperature: 2, WindSpeed: 4}}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		giveCityName string
+		giveDays     int
+		giveInterval int
+		giveBody     string // The response body, dummyResult if empty.
+		expected     *ForecastSuccess
+		expectedErr  string
+	}{
+		{
+			name:         "success",
+			giveCityName: "Sydney",
+			giveDays:     2,
+			giveInterval: 3,
+			expected:     &dummyResult,
+		},
+		{
+			name:         "missing_forecast",
+			giveCityName: "Sydney",
+			giveDays:     2,
+			giveInterval: 3,
+			giveBody:     `{"location":{"name":"Sydney"}}`,
+			expectedErr:  "weatherstack: response has no forecast",
+		},
+		{
+			name:         "empty_forecast",
+			giveCityName: "Sydney",
+			giveDays:     2,
+			giveInterval: 3,
+			giveBody:     `{"location":{"name":"Sydney"},"forecast":{}}`,
+			expectedErr:  "weatherstack: response has no forecast",
+		},
+		{
+			name:         "missing_city_name",
+			giveCityName: "",
+			giveDays:     2,
+			giveInterval: 3,
+			expectedErr:  "weatherstack: cityname is required",
+		},
+		{
+			name:         "invalid_days",
+			giveCityName: "Sydney",
+			giveDays:     0,
+			giveInterval: 3,
+			expectedErr:  "weatherstack: days must be at least 1",
+		},
+		{
+			name:         "invalid_interval",
+			giveCityName: "Sydney",
+			giveDays:     2,
+			giveInterval: 2,
+			expectedErr:  "weatherstack: unsupported interval 2",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			client := New("http://weatherstack", "", NewWithHTTPClient(&HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/forecast", req.URL.Path)
+					assert.Equal(t, tc.giveCityName, req.URL.Query().Get("query"))
+					assert.Equal(t, "2", req.URL.Query().Get("forecast_days"))
+					assert.Equal(t, "1", req.URL.Query().Get("hourly"))
+					assert.Equal(t, "3", req.URL.Query().Get("interval"))
+
+					payload := []byte(tc.giveBody)
+					if tc.giveBody == "" {
+						var err error
+						payload, err = json.Marshal(dummyResult)
+						assert.NoError(t, err)
+					}
+
+					r := io.NopCloser(bytes.NewReader(payload))
+
+					return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+				},
+			}))
+
+			actual, err := client.ForecastByCityName(ctx, tc.giveCityName, tc.giveDays, tc.giveInterval)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
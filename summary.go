@@ -1,7 +1,29 @@
 package weather
 
+import "time"
+
 // Summary represents weather datapoints for a location at a point in time.
+//
+// Fields that are pointers (or empty strings) are optional, as not every provider can supply
+// them, and are omitted from JSON when not set.
 type Summary struct {
-	WindSpeed   float64 `json:"wind_speed"`          // The location windspeed in km/h.
-	Temperature float64 `json:"temperature_degrees"` // The location temperature in degrees celsius.
+	WindSpeed            float64    `json:"wind_speed"`                       // The location windspeed in km/h.
+	Temperature          float64    `json:"temperature_degrees"`              // The location temperature in degrees celsius.
+	FeelsLike            *float64   `json:"feels_like_degrees,omitempty"`     // The apparent temperature in degrees celsius.
+	Humidity             *float64   `json:"humidity_percent,omitempty"`       // The relative humidity as a percentage.
+	Pressure             *float64   `json:"pressure_hpa,omitempty"`           // The atmospheric pressure (at sea level) in hPa.
+	CloudCover           *float64   `json:"cloud_cover_percent,omitempty"`    // The cloud cover as a percentage.
+	Visibility           *float64   `json:"visibility_km,omitempty"`          // The visibility in km.
+	WindDirection        *float64   `json:"wind_direction_degrees,omitempty"` // The direction the wind is blowing from in degrees (meteorological).
+	WindDirectionCompass string     `json:"wind_direction_compass,omitempty"` // WindDirection as a 16 point compass point, e.g "NNE".
+	WindGust             *float64   `json:"wind_gust_speed,omitempty"`        // The wind gust speed in km/h.
+	Precipitation        *float64   `json:"precipitation_mm,omitempty"`       // The precipitation volume in mm.
+	Condition            *Condition `json:"condition,omitempty"`              // The weather condition, e.g "rain".
+	ObservedAt           *time.Time `json:"observed_at,omitempty"`            // When the provider observed the datapoints.
+}
+
+// SetWindDirection sets both WindDirection & WindDirectionCompass from degrees.
+func (s *Summary) SetWindDirection(degrees float64) {
+	s.WindDirection = &degrees
+	s.WindDirectionCompass = CompassPoint(degrees)
 }